@@ -0,0 +1,177 @@
+package adktest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	adktest "github.com/inference-gateway/a2a/adk/adktest"
+	server "github.com/inference-gateway/a2a/adk/server"
+	sdk "github.com/inference-gateway/sdk"
+	assert "github.com/stretchr/testify/assert"
+	require "github.com/stretchr/testify/require"
+	zap "go.uber.org/zap"
+)
+
+// recordingT records the failures of assertions expected to fail
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, format)
+}
+
+// newLookupAgent returns an agent answering with the scripted LLM and looking up records
+func newLookupAgent(t *testing.T, llm server.LLMClient, lookups *[]map[string]interface{}) server.OpenAICompatibleAgent {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			*lookups = append(*lookups, args)
+			return "record 7: Ada Lovelace", nil
+		}))
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llm).WithToolBox(toolBox).Build()
+	require.NoError(t, err)
+	return agent
+}
+
+func TestScriptedLLMClient(t *testing.T) {
+	llm := adktest.NewScriptedLLMClient(
+		adktest.ToolCall("lookup", map[string]interface{}{"id": 7}).WithToolCall("audit", nil),
+		adktest.Text("Found it."),
+	).Then(adktest.Fail(errors.New("overloaded")))
+
+	response, err := llm.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Find record 7"}})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.ToolCalls, response.Choices[0].FinishReason)
+	require.NotNil(t, response.Choices[0].Message.ToolCalls)
+	toolCalls := *response.Choices[0].Message.ToolCalls
+	require.Len(t, toolCalls, 2)
+	assert.Equal(t, "call-0-0", toolCalls[0].Id)
+	assert.Equal(t, `{"id":7}`, toolCalls[0].Function.Arguments)
+	assert.Equal(t, "audit", toolCalls[1].Function.Name)
+
+	streamResponses, streamErrors := llm.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Again"}})
+	var streamed, finishReason string
+	chunks := 0
+	for chunk := range streamResponses {
+		chunks++
+		streamed += chunk.Choices[0].Delta.Content
+		finishReason = chunk.Choices[0].FinishReason
+	}
+	require.NoError(t, <-streamErrors)
+	assert.Equal(t, "Found it.", streamed)
+	assert.Equal(t, "stop", finishReason)
+	assert.Equal(t, 3, chunks, "content is streamed word by word")
+
+	_, err = llm.CreateChatCompletion(context.Background(), nil)
+	assert.EqualError(t, err, "overloaded")
+
+	_, err = llm.CreateChatCompletion(context.Background(), nil)
+	assert.ErrorIs(t, err, adktest.ErrScriptExhausted)
+
+	requests := llm.Requests()
+	require.Len(t, requests, 4)
+	assert.True(t, requests[1].Stream)
+	assert.Equal(t, "Again", requests[1].LastMessage().Content)
+	assert.Zero(t, llm.Remaining())
+}
+
+func TestHarness_Send(t *testing.T) {
+	llm := adktest.NewScriptedLLMClient(
+		adktest.ToolCall("lookup", map[string]interface{}{"id": 7}),
+		adktest.Text("Record 7 is Ada Lovelace."),
+	)
+	var lookups []map[string]interface{}
+	harness := adktest.NewHarness(t, newLookupAgent(t, llm, &lookups))
+
+	task := harness.SendText("Who is record 7?")
+
+	adktest.AssertFinalState(t, task, adk.TaskStateCompleted)
+	adktest.AssertScriptConsumed(t, llm)
+	assert.Equal(t, []map[string]interface{}{{"id": float64(7)}}, lookups)
+
+	requests := llm.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, sdk.Tool, requests[1].LastMessage().Role, "the tool result is sent back to the LLM")
+	assert.Equal(t, "record 7: Ada Lovelace", requests[1].LastMessage().Content)
+
+	card, err := harness.Client.GetAgentCard(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-agent", card.Name)
+}
+
+func TestHarness_Stream(t *testing.T) {
+	llm := adktest.NewScriptedLLMClient(
+		adktest.ToolCall("lookup", map[string]interface{}{"id": 7}),
+		adktest.Text("Record 7 is Ada Lovelace."),
+	)
+	var lookups []map[string]interface{}
+	harness := adktest.NewHarness(t, newLookupAgent(t, llm, &lookups))
+
+	events := harness.StreamText("Who is record 7?")
+
+	adktest.AssertStates(t, events, adk.TaskStateWorking, adk.TaskStateCompleted)
+	adktest.AssertEventOrder(t, events, "tool:lookup:started", "tool:lookup:completed", "text", "state:completed")
+	assert.Equal(t, "Record 7 is Ada Lovelace.", adktest.StreamedText(events))
+	adktest.AssertScriptConsumed(t, llm)
+	assert.Len(t, lookups, 1)
+
+	recorder := &recordingT{TB: t}
+	assert.False(t, adktest.AssertEventOrder(recorder, events, "text", "tool:lookup:started"))
+	assert.False(t, adktest.AssertStates(recorder, events, adk.TaskStateCompleted))
+	assert.Len(t, recorder.failures, 2)
+}
+
+// echoHandler streams the text of the message back word by word
+type echoHandler struct{}
+
+func (h echoHandler) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return h.HandleStreamingTask(ctx, task, message, server.NopStreamEventEmitter)
+}
+
+func (echoHandler) HandleStreamingTask(ctx context.Context, task *adk.Task, message *adk.Message, emitter server.StreamEventEmitter) (*adk.Task, error) {
+	text, _ := message.Parts[0].(map[string]interface{})["text"].(string)
+	for _, word := range strings.SplitAfter(text, " ") {
+		if err := emitter.EmitChunk(ctx, word); err != nil {
+			return nil, err
+		}
+	}
+	task.Artifacts = append(task.Artifacts, adk.Artifact{
+		ArtifactID: "echo",
+		Parts:      []adk.Part{map[string]interface{}{"kind": "text", "text": text}},
+	})
+	task.Status.State = adk.TaskStateCompleted
+	return task, nil
+}
+
+func TestHarness_StreamWithTaskHandler(t *testing.T) {
+	harness := adktest.NewHarness(t, nil, adktest.WithTaskHandler(echoHandler{}))
+
+	events := harness.StreamText("Hello there")
+
+	adktest.AssertStates(t, events, adk.TaskStateWorking, adk.TaskStateCompleted)
+	adktest.AssertEventOrder(t, events, "text", "artifact", "state:completed")
+	assert.Equal(t, "Hello there", adktest.StreamedText(events))
+
+	task := harness.SendText("Hello again")
+	adktest.AssertFinalState(t, task, adk.TaskStateCompleted)
+	require.Len(t, task.Artifacts, 1, "message/send processes the task with the same handler")
+}
+
+func TestHarness_SendFailsWhenScriptIsExhausted(t *testing.T) {
+	llm := adktest.NewScriptedLLMClient()
+	var lookups []map[string]interface{}
+	harness := adktest.NewHarness(t, newLookupAgent(t, llm, &lookups))
+
+	task := harness.SendText("Who is record 7?")
+
+	adktest.AssertFinalState(t, task, adk.TaskStateFailed)
+	assert.Len(t, llm.Requests(), 1)
+}
@@ -0,0 +1,186 @@
+package adktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	assert "github.com/stretchr/testify/assert"
+)
+
+// Event is an event of a message/stream response
+type Event struct {
+	Kind      string
+	TaskID    string
+	ContextID string
+	State     adk.TaskState
+	Final     bool
+
+	// Role is the role of the message of a status update
+	Role string
+	// Text joins the text parts of the message of a status update or of an artifact
+	Text string
+	// ToolName and ToolStatus describe the tool execution of a status update
+	ToolName   string
+	ToolStatus string
+
+	Raw map[string]interface{}
+}
+
+type streamEvent struct {
+	Kind      string `json:"kind"`
+	TaskID    string `json:"taskId"`
+	ContextID string `json:"contextId"`
+	Final     bool   `json:"final"`
+	Status    struct {
+		State   adk.TaskState `json:"state"`
+		Message *struct {
+			Role  string                   `json:"role"`
+			Parts []map[string]interface{} `json:"parts"`
+		} `json:"message"`
+	} `json:"status"`
+	Artifact struct {
+		Parts []map[string]interface{} `json:"parts"`
+	} `json:"artifact"`
+}
+
+// newEvent decodes an event received from message/stream
+func newEvent(result interface{}) (Event, error) {
+	var decoded streamEvent
+	if err := decode(result, &decoded); err != nil {
+		return Event{}, err
+	}
+
+	event := Event{
+		Kind:      decoded.Kind,
+		TaskID:    decoded.TaskID,
+		ContextID: decoded.ContextID,
+		State:     decoded.Status.State,
+		Final:     decoded.Final,
+	}
+	if raw, ok := result.(map[string]interface{}); ok {
+		event.Raw = raw
+	}
+
+	parts := decoded.Artifact.Parts
+	if decoded.Status.Message != nil {
+		event.Role = decoded.Status.Message.Role
+		parts = decoded.Status.Message.Parts
+	}
+
+	var text strings.Builder
+	for _, part := range parts {
+		switch part["kind"] {
+		case "text":
+			if value, ok := part["text"].(string); ok {
+				text.WriteString(value)
+			}
+		case "data":
+			data, _ := part["data"].(map[string]interface{})
+			if name, ok := data["tool_name"].(string); ok {
+				event.ToolName = name
+				event.ToolStatus, _ = data["status"].(string)
+			}
+		}
+	}
+	event.Text = text.String()
+	return event, nil
+}
+
+// Label names the event for AssertEventOrder:
+//   - "tool:<name>:<status>" for tool executions, such as "tool:lookup:completed"
+//   - "text" for content streamed by the agent
+//   - "state:<state>" for other status updates, such as "state:completed"
+//   - "artifact" for artifact updates
+func (e Event) Label() string {
+	switch e.Kind {
+	case "status-update":
+		if e.ToolName != "" {
+			return "tool:" + e.ToolName + ":" + e.ToolStatus
+		}
+		if !e.Final && e.State == adk.TaskStateWorking && e.Role == "assistant" && e.Text != "" {
+			return "text"
+		}
+		return "state:" + string(e.State)
+	case "artifact-update":
+		return "artifact"
+	default:
+		return e.Kind
+	}
+}
+
+// Labels returns the labels of the events
+func Labels(events []Event) []string {
+	labels := make([]string, 0, len(events))
+	for _, event := range events {
+		labels = append(labels, event.Label())
+	}
+	return labels
+}
+
+// States returns the task states of the status updates, consecutive repeats of a state are collapsed
+func States(events []Event) []adk.TaskState {
+	var states []adk.TaskState
+	for _, event := range events {
+		if event.Kind != "status-update" {
+			continue
+		}
+		if len(states) > 0 && states[len(states)-1] == event.State {
+			continue
+		}
+		states = append(states, event.State)
+	}
+	return states
+}
+
+// StreamedText joins the text streamed by the agent
+func StreamedText(events []Event) string {
+	var text strings.Builder
+	for _, event := range events {
+		if event.Label() == "text" {
+			text.WriteString(event.Text)
+		}
+	}
+	return text.String()
+}
+
+// AssertStates asserts that the task went through exactly the states, see States
+func AssertStates(t testing.TB, events []Event, expected ...adk.TaskState) bool {
+	t.Helper()
+	return assert.Equal(t, expected, States(events), "task state sequence")
+}
+
+// AssertEventOrder asserts that events with the labels were streamed in this order,
+// other events may come in between, see Event.Label
+func AssertEventOrder(t testing.TB, events []Event, labels ...string) bool {
+	t.Helper()
+
+	actual := Labels(events)
+	next := 0
+	for _, label := range actual {
+		if next < len(labels) && label == labels[next] {
+			next++
+		}
+	}
+	if next == len(labels) {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("event %q not streamed in order", labels[next]),
+		"expected order: %s\nstreamed events: %s", strings.Join(labels, ", "), strings.Join(actual, ", "))
+}
+
+// AssertFinalState asserts the state of the task
+func AssertFinalState(t testing.TB, task *adk.Task, expected adk.TaskState) bool {
+	t.Helper()
+	if !assert.NotNil(t, task, "task") {
+		return false
+	}
+	return assert.Equal(t, expected, task.Status.State, "state of task %s", task.ID)
+}
+
+// AssertScriptConsumed asserts that the fake LLM answered all its scripted turns
+func AssertScriptConsumed(t testing.TB, llm *ScriptedLLMClient) bool {
+	t.Helper()
+	return assert.Zero(t, llm.Remaining(), "scripted turns not answered")
+}
@@ -0,0 +1,247 @@
+// Package adktest provides a scripted fake LLM, an in-process A2A server harness and
+// assertion helpers for testing agents built with the ADK
+package adktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	uuid "github.com/google/uuid"
+	adk "github.com/inference-gateway/a2a/adk"
+	client "github.com/inference-gateway/a2a/adk/client"
+	server "github.com/inference-gateway/a2a/adk/server"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	require "github.com/stretchr/testify/require"
+	zap "go.uber.org/zap"
+)
+
+// handlerServer is implemented by servers that can be served by an http.Handler
+type handlerServer interface {
+	server.A2AServer
+	Handler() (http.Handler, error)
+}
+
+// Harness serves an agent with an A2A server on an httptest server and talks to it
+// through a real A2A client, exercising the JSON-RPC and streaming endpoints end to end
+type Harness struct {
+	Server server.A2AServer
+	Client client.A2AClient
+	URL    string
+
+	t       testing.TB
+	timeout time.Duration
+	poll    time.Duration
+}
+
+type harnessOptions struct {
+	configure   []func(cfg *config.Config)
+	agentCard   adk.AgentCard
+	logger      *zap.Logger
+	taskHandler server.TaskHandler
+	timeout     time.Duration
+	poll        time.Duration
+}
+
+// HarnessOption configures a Harness
+type HarnessOption func(*harnessOptions)
+
+// WithConfig adjusts the configuration of the server, which starts from the defaults
+func WithConfig(configure func(cfg *config.Config)) HarnessOption {
+	return func(o *harnessOptions) {
+		o.configure = append(o.configure, configure)
+	}
+}
+
+// WithAgentCard sets the agent card of the server
+func WithAgentCard(agentCard adk.AgentCard) HarnessOption {
+	return func(o *harnessOptions) {
+		o.agentCard = agentCard
+	}
+}
+
+// WithLogger sets the logger of the server and client, by default nothing is logged
+func WithLogger(logger *zap.Logger) HarnessOption {
+	return func(o *harnessOptions) {
+		o.logger = logger
+	}
+}
+
+// WithTaskHandler sets the task handler processing the tasks of message/send and message/stream
+func WithTaskHandler(handler server.TaskHandler) HarnessOption {
+	return func(o *harnessOptions) {
+		o.taskHandler = handler
+	}
+}
+
+// WithTimeout sets how long a request and its task may take, 10 seconds by default
+func WithTimeout(timeout time.Duration) HarnessOption {
+	return func(o *harnessOptions) {
+		o.timeout = timeout
+	}
+}
+
+// NewHarness starts an A2A server for the agent and a client connected to it,
+// both are shut down when the test finishes
+func NewHarness(t testing.TB, agent server.OpenAICompatibleAgent, options ...HarnessOption) *Harness {
+	t.Helper()
+
+	o := &harnessOptions{
+		agentCard: adk.AgentCard{
+			Name:               "test-agent",
+			Description:        "Agent under test",
+			URL:                "http://localhost",
+			Version:            "1.0.0",
+			Capabilities:       adk.AgentCapabilities{Streaming: boolPtr(true)},
+			DefaultInputModes:  []string{"text/plain"},
+			DefaultOutputModes: []string{"text/plain"},
+		},
+		logger:  zap.NewNop(),
+		timeout: 10 * time.Second,
+		poll:    10 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(o)
+	}
+
+	cfg, err := config.NewWithDefaults(context.Background(), nil)
+	require.NoError(t, err, "failed to create the default configuration")
+	for _, configure := range o.configure {
+		configure(cfg)
+	}
+
+	builder := server.NewA2AServerBuilder(*cfg, o.logger).WithAgentCard(o.agentCard)
+	if agent != nil {
+		builder = builder.WithAgent(agent)
+	}
+	if o.taskHandler != nil {
+		builder = builder.WithTaskHandler(o.taskHandler)
+	}
+	a2aServer, err := builder.Build()
+	require.NoError(t, err, "failed to build the A2A server")
+
+	served, ok := a2aServer.(handlerServer)
+	require.True(t, ok, "the A2A server cannot be served by an http.Handler")
+	handler, err := served.Handler()
+	require.NoError(t, err, "failed to set up the A2A endpoints")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go a2aServer.StartTaskProcessor(ctx)
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		cancel()
+		httpServer.Close()
+	})
+
+	return &Harness{
+		Server:  a2aServer,
+		Client:  client.NewClientWithLogger(httpServer.URL, o.logger),
+		URL:     httpServer.URL,
+		t:       t,
+		timeout: o.timeout,
+		poll:    o.poll,
+	}
+}
+
+// NewTextMessage returns a user message with the text
+func NewTextMessage(text string) *adk.Message {
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: uuid.New().String(),
+		Role:      "user",
+		Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": text}},
+	}
+}
+
+// Send sends the message with message/send and waits until its task is final or needs input
+func (h *Harness) Send(message *adk.Message) *adk.Task {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	response, err := h.Client.SendTask(ctx, adk.MessageSendParams{Message: *message})
+	require.NoError(h.t, err, "message/send failed")
+
+	var task adk.Task
+	require.NoError(h.t, decode(response.Result, &task), "message/send did not return a task")
+	return h.WaitForTask(task.ID)
+}
+
+// SendText sends a user message with the text, see Send
+func (h *Harness) SendText(text string) *adk.Task {
+	h.t.Helper()
+	return h.Send(NewTextMessage(text))
+}
+
+// WaitForTask polls the task until it is final or needs input
+func (h *Harness) WaitForTask(taskID string) *adk.Task {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	for {
+		response, err := h.Client.GetTask(ctx, adk.TaskQueryParams{ID: taskID})
+		require.NoError(h.t, err, "tasks/get failed")
+
+		var task adk.Task
+		require.NoError(h.t, decode(response.Result, &task), "tasks/get did not return a task")
+		if server.IsFinalTaskState(task.Status.State) {
+			return &task
+		}
+
+		select {
+		case <-ctx.Done():
+			require.FailNow(h.t, fmt.Sprintf("task %s is still %s after %s", taskID, task.Status.State, h.timeout))
+		case <-time.After(h.poll):
+		}
+	}
+}
+
+// Stream sends the message with message/stream and returns the events of the stream once it ended
+func (h *Harness) Stream(message *adk.Message) []Event {
+	h.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	eventChan := make(chan interface{})
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(eventChan)
+		errChan <- h.Client.SendTaskStreaming(ctx, adk.MessageSendParams{Message: *message}, eventChan)
+	}()
+
+	var events []Event
+	for result := range eventChan {
+		event, err := newEvent(result)
+		require.NoError(h.t, err, "message/stream sent an event that cannot be decoded")
+		events = append(events, event)
+	}
+	require.NoError(h.t, <-errChan, "message/stream failed")
+	return events
+}
+
+// StreamText streams a user message with the text, see Stream
+func (h *Harness) StreamText(text string) []Event {
+	h.t.Helper()
+	return h.Stream(NewTextMessage(text))
+}
+
+// decode converts a JSON-RPC result to its type
+func decode(result interface{}, target interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,246 @@
+package adktest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	server "github.com/inference-gateway/a2a/adk/server"
+	sdk "github.com/inference-gateway/sdk"
+)
+
+// ErrScriptExhausted is returned by a ScriptedLLMClient asked for more completions than it was scripted with
+var ErrScriptExhausted = errors.New("scripted llm client has no more turns")
+
+// Turn is one scripted answer of a ScriptedLLMClient: text, tool calls or an error
+type Turn struct {
+	Content   string
+	ToolCalls []sdk.ChatCompletionMessageToolCall
+	Err       error
+	Usage     *sdk.CompletionUsage
+}
+
+// Text returns a turn answering with the content
+func Text(content string) Turn {
+	return Turn{Content: content}
+}
+
+// ToolCall returns a turn calling the tool with the arguments
+func ToolCall(name string, arguments map[string]interface{}) Turn {
+	return Turn{}.WithToolCall(name, arguments)
+}
+
+// Fail returns a turn failing with err
+func Fail(err error) Turn {
+	return Turn{Err: err}
+}
+
+// WithToolCall adds a tool call to the turn, the tool calls of one turn are executed in order
+// unless the agent allows parallel tool calls with AGENT_CLIENT_MAX_PARALLEL_TOOL_CALLS
+func (t Turn) WithToolCall(name string, arguments map[string]interface{}) Turn {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		panic(fmt.Sprintf("adktest: arguments of tool call %s cannot be encoded: %v", name, err))
+	}
+
+	t.ToolCalls = append(append([]sdk.ChatCompletionMessageToolCall(nil), t.ToolCalls...), sdk.ChatCompletionMessageToolCall{
+		Type: sdk.Function,
+		Function: sdk.ChatCompletionMessageToolCallFunction{
+			Name:      name,
+			Arguments: string(data),
+		},
+	})
+	return t
+}
+
+// WithUsage sets the token usage reported with the turn
+func (t Turn) WithUsage(prompt int64, completion int64) Turn {
+	t.Usage = &sdk.CompletionUsage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+	return t
+}
+
+// Request is a completion request received by a ScriptedLLMClient
+type Request struct {
+	Messages []sdk.Message
+	Tools    []sdk.ChatCompletionTool
+	Stream   bool
+}
+
+// LastMessage returns the last message of the request
+func (r Request) LastMessage() sdk.Message {
+	if len(r.Messages) == 0 {
+		return sdk.Message{}
+	}
+	return r.Messages[len(r.Messages)-1]
+}
+
+var _ server.LLMClient = (*ScriptedLLMClient)(nil)
+
+// ScriptedLLMClient is a fake LLM answering each completion with its next scripted turn,
+// in order and whether the completion is streamed or not. Streamed turns are sent as
+// chunks, the content word by word, then one chunk per tool call and the finish reason.
+type ScriptedLLMClient struct {
+	mu       sync.Mutex
+	turns    []Turn
+	next     int
+	requests []Request
+}
+
+// NewScriptedLLMClient creates a fake LLM answering with the turns
+func NewScriptedLLMClient(turns ...Turn) *ScriptedLLMClient {
+	return &ScriptedLLMClient{turns: turns}
+}
+
+// Then appends turns to the script
+func (c *ScriptedLLMClient) Then(turns ...Turn) *ScriptedLLMClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.turns = append(c.turns, turns...)
+	return c
+}
+
+// Requests returns the completion requests received so far
+func (c *ScriptedLLMClient) Requests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Request(nil), c.requests...)
+}
+
+// Remaining returns the number of scripted turns not yet answered
+func (c *ScriptedLLMClient) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.turns) - c.next
+}
+
+// CreateChatCompletion implements server.LLMClient.CreateChatCompletion
+func (c *ScriptedLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	turn, index, err := c.take(messages, tools, false)
+	if err != nil {
+		return nil, err
+	}
+
+	message := sdk.Message{Role: sdk.Assistant, Content: turn.Content}
+	finishReason := sdk.Stop
+	if len(turn.ToolCalls) > 0 {
+		toolCalls := turn.toolCalls(index)
+		message.ToolCalls = &toolCalls
+		finishReason = sdk.ToolCalls
+	}
+
+	return &sdk.CreateChatCompletionResponse{
+		Id:      fmt.Sprintf("scripted-%d", index),
+		Model:   "scripted",
+		Object:  "chat.completion",
+		Choices: []sdk.ChatCompletionChoice{{FinishReason: finishReason, Message: message}},
+		Usage:   turn.Usage,
+	}, nil
+}
+
+// CreateStreamingChatCompletion implements server.LLMClient.CreateStreamingChatCompletion
+func (c *ScriptedLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+	errorChan := make(chan error, 1)
+
+	turn, index, err := c.take(messages, tools, true)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		for _, chunk := range turn.chunks(index) {
+			select {
+			case responseChan <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// take records the request and returns the next turn with its index
+func (c *ScriptedLLMClient) take(messages []sdk.Message, tools []sdk.ChatCompletionTool, stream bool) (Turn, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests = append(c.requests, Request{
+		Messages: append([]sdk.Message(nil), messages...),
+		Tools:    tools,
+		Stream:   stream,
+	})
+
+	if c.next >= len(c.turns) {
+		return Turn{}, 0, fmt.Errorf("%w: request %d received after %d turns", ErrScriptExhausted, len(c.requests), len(c.turns))
+	}
+
+	index := c.next
+	c.next++
+	turn := c.turns[index]
+	if turn.Err != nil {
+		return Turn{}, index, turn.Err
+	}
+	return turn, index, nil
+}
+
+// toolCalls returns the tool calls of the turn with their ids
+func (t Turn) toolCalls(index int) []sdk.ChatCompletionMessageToolCall {
+	toolCalls := make([]sdk.ChatCompletionMessageToolCall, len(t.ToolCalls))
+	for i, toolCall := range t.ToolCalls {
+		if toolCall.Id == "" {
+			toolCall.Id = fmt.Sprintf("call-%d-%d", index, i)
+		}
+		toolCalls[i] = toolCall
+	}
+	return toolCalls
+}
+
+// chunks splits the turn into the chunks of a stream
+func (t Turn) chunks(index int) []*sdk.CreateChatCompletionStreamResponse {
+	chunk := func(delta sdk.ChatCompletionStreamResponseDelta, finishReason string) *sdk.CreateChatCompletionStreamResponse {
+		return &sdk.CreateChatCompletionStreamResponse{
+			ID:      fmt.Sprintf("scripted-%d", index),
+			Model:   "scripted",
+			Object:  "chat.completion.chunk",
+			Choices: []sdk.ChatCompletionStreamChoice{{Delta: delta, FinishReason: finishReason}},
+		}
+	}
+
+	var chunks []*sdk.CreateChatCompletionStreamResponse
+	if t.Content != "" {
+		for _, word := range strings.SplitAfter(t.Content, " ") {
+			chunks = append(chunks, chunk(sdk.ChatCompletionStreamResponseDelta{Role: string(sdk.Assistant), Content: word}, ""))
+		}
+	}
+
+	finishReason := string(sdk.Stop)
+	for i, toolCall := range t.toolCalls(index) {
+		toolCallChunk := sdk.ChatCompletionMessageToolCallChunk{
+			Index: i,
+			ID:    toolCall.Id,
+			Type:  string(toolCall.Type),
+		}
+		toolCallChunk.Function.Name = toolCall.Function.Name
+		toolCallChunk.Function.Arguments = toolCall.Function.Arguments
+		chunks = append(chunks, chunk(sdk.ChatCompletionStreamResponseDelta{
+			ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{toolCallChunk},
+		}, ""))
+		finishReason = string(sdk.ToolCalls)
+	}
+
+	last := chunk(sdk.ChatCompletionStreamResponseDelta{}, finishReason)
+	last.Usage = t.Usage
+	return append(chunks, last)
+}
@@ -2,7 +2,7 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	adk "github.com/inference-gateway/a2a/adk"
@@ -40,6 +40,7 @@ type DefaultOpenAICompatibleAgent struct {
 func NewDefaultOpenAICompatibleAgent(logger *zap.Logger) *DefaultOpenAICompatibleAgent {
 	defaultConfig := &config.AgentConfig{
 		MaxChatCompletionIterations: 10,
+		MaxParallelToolCalls:        DefaultMaxParallelToolCalls,
 		SystemPrompt:                "You are a helpful AI assistant.",
 	}
 	return &DefaultOpenAICompatibleAgent{
@@ -67,7 +68,7 @@ func NewOpenAICompatibleAgentWithLLM(logger *zap.Logger, llmClient LLMClient) *D
 
 // NewOpenAICompatibleAgentWithConfig creates a new agent with LLM configuration
 func NewOpenAICompatibleAgentWithConfig(logger *zap.Logger, config *config.AgentConfig) (*DefaultOpenAICompatibleAgent, error) {
-	client, err := NewOpenAICompatibleLLMClient(config, logger)
+	client, err := NewLLMClient(config, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create llm client: %w", err)
 	}
@@ -77,12 +78,44 @@ func NewOpenAICompatibleAgentWithConfig(logger *zap.Logger, config *config.Agent
 	return agent, nil
 }
 
+// withOverrides returns a copy of the agent using another system prompt or model, sharing its tool box
+// A new LLM client is created when the model changes
+func (a *DefaultOpenAICompatibleAgent) withOverrides(systemPrompt string, model string) (*DefaultOpenAICompatibleAgent, error) {
+	cfg := config.AgentConfig{}
+	if a.config != nil {
+		cfg = *a.config
+	}
+
+	agent := *a
+	agent.config = &cfg
+
+	if systemPrompt != "" {
+		cfg.SystemPrompt = systemPrompt
+	}
+
+	if model != "" && model != cfg.Model {
+		cfg.Model = model
+		client, err := NewLLMClient(&cfg, a.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create llm client for model %s: %w", model, err)
+		}
+		agent.llmClient = client
+	}
+
+	return &agent, nil
+}
+
 // ProcessTask processes a task with optional tool calling capabilities
 func (a *DefaultOpenAICompatibleAgent) ProcessTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
 	if a.llmClient == nil {
 		return a.processWithoutLLM(task, message), nil
 	}
 
+	ctx, err := applyLLMRequestOptions(ctx, task, message, a.llmClient, a.config)
+	if err != nil {
+		return nil, err
+	}
+
 	messages := make([]adk.Message, 0)
 
 	if a.config.SystemPrompt != "" {
@@ -102,6 +135,10 @@ func (a *DefaultOpenAICompatibleAgent) ProcessTask(ctx context.Context, task *ad
 
 	messages = append(messages, task.History...)
 
+	if approval, ok := pendingToolApproval(task); ok && a.toolBox != nil {
+		return a.resumeToolApproval(ctx, task, message, approval, messages)
+	}
+
 	messages = append(messages, *message)
 
 	if a.toolBox != nil && len(a.toolBox.GetTools()) > 0 {
@@ -132,6 +169,11 @@ func (a *DefaultOpenAICompatibleAgent) processWithoutToolCalling(ctx context.Con
 		return task, nil
 	}
 
+	if err := checkTokenBudget(ctx, task); err != nil {
+		a.logger.Warn("llm budget exhausted", zap.Error(err), zap.String("task_id", task.ID))
+		return a.createErrorTask(task, budgetExhaustedMessage(err)), nil
+	}
+
 	result, err := a.llmClient.CreateChatCompletion(ctx, sdkMessages)
 	if err != nil {
 		a.logger.Error("llm completion failed",
@@ -154,6 +196,9 @@ func (a *DefaultOpenAICompatibleAgent) processWithoutToolCalling(ctx context.Con
 		return task, nil
 	}
 
+	recordLLMModel(ctx, task, a.llmClient, result.Model)
+	RecordTokenUsage(ctx, task, result.Usage)
+
 	if len(result.Choices) == 0 {
 		a.logger.Error("no choices returned from llm",
 			zap.String("task_id", task.ID),
@@ -228,6 +273,11 @@ func (a *DefaultOpenAICompatibleAgent) processWithToolCalling(ctx context.Contex
 			return a.createErrorTask(task, fmt.Sprintf("Message conversion failed: %v", err)), nil
 		}
 
+		if err := checkTokenBudget(ctx, task); err != nil {
+			a.logger.Warn("llm budget exhausted", zap.Error(err), zap.String("task_id", task.ID))
+			return a.createErrorTask(task, budgetExhaustedMessage(err)), nil
+		}
+
 		result, err := a.llmClient.CreateChatCompletion(ctx, sdkMessages, tools...)
 		if err != nil {
 			a.logger.Error("llm completion failed",
@@ -238,6 +288,9 @@ func (a *DefaultOpenAICompatibleAgent) processWithToolCalling(ctx context.Contex
 			return a.createErrorTask(task, fmt.Sprintf("LLM request failed: %v", err)), nil
 		}
 
+		recordLLMModel(ctx, task, a.llmClient, result.Model)
+		RecordTokenUsage(ctx, task, result.Usage)
+
 		if len(result.Choices) == 0 {
 			a.logger.Error("no choices in llm response",
 				zap.String("task_id", task.ID),
@@ -253,30 +306,16 @@ func (a *DefaultOpenAICompatibleAgent) processWithToolCalling(ctx context.Contex
 				zap.Int("count", len(*choice.Message.ToolCalls)),
 				zap.Int("iteration", iteration))
 
-			assistantMessage := &adk.Message{
-				Kind:      "message",
-				MessageID: fmt.Sprintf("assistant-%s-%d", task.ID, iteration),
-				Role:      "assistant",
-				Parts: []adk.Part{
-					map[string]interface{}{
-						"kind": "data",
-						"data": map[string]interface{}{
-							"tool_calls": *choice.Message.ToolCalls,
-							"content":    choice.Message.Content,
-						},
-					},
-				},
+			if approval := newToolApproval(a.toolBox, choice.Message.Content, *choice.Message.ToolCalls); approval != nil {
+				return a.createToolApprovalTask(ctx, task, approval), nil
 			}
-			task.History = append(task.History, *assistantMessage)
-			currentMessages = append(currentMessages, *assistantMessage)
 
-			toolResults, err := a.executeTools(ctx, task, *choice.Message.ToolCalls)
-			if err != nil {
-				a.logger.Error("tool execution failed", zap.Error(err))
-				return a.createErrorTask(task, fmt.Sprintf("Tool execution failed: %v", err)), nil
+			turn, stopped := a.handleToolCalls(ctx, task, fmt.Sprintf("assistant-%s-%d", task.ID, iteration), choice.Message.Content, *choice.Message.ToolCalls, nil)
+			if stopped != nil {
+				return stopped, nil
 			}
 
-			currentMessages = append(currentMessages, toolResults...)
+			currentMessages = append(currentMessages, turn...)
 			continue
 		}
 
@@ -309,6 +348,80 @@ func (a *DefaultOpenAICompatibleAgent) processWithToolCalling(ctx context.Contex
 	return a.createErrorTask(task, fmt.Sprintf("Maximum iterations (%d) reached without completion", a.config.MaxChatCompletionIterations)), nil
 }
 
+// handleToolCalls records the tool calls of the assistant in the task history and executes them.
+// It returns the assistant message followed by the tool results, or the task if processing must stop.
+func (a *DefaultOpenAICompatibleAgent) handleToolCalls(
+	ctx context.Context,
+	task *adk.Task,
+	messageID string,
+	content string,
+	toolCalls []sdk.ChatCompletionMessageToolCall,
+	decisions map[string]toolApprovalDecision,
+) ([]adk.Message, *adk.Task) {
+	assistantMessage := adk.Message{
+		Kind:      "message",
+		MessageID: messageID,
+		Role:      "assistant",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "data",
+				"data": map[string]interface{}{
+					"tool_calls": toolCalls,
+					"content":    content,
+				},
+			},
+		},
+	}
+	task.History = append(task.History, assistantMessage)
+
+	toolResults, err := a.executeTools(ctx, task, toolCalls, decisions)
+	var inputErr *InputRequiredError
+	if errors.As(err, &inputErr) {
+		return nil, a.createInputRequiredTask(task, inputErr.Message)
+	}
+	if err != nil {
+		a.logger.Error("tool execution failed", zap.Error(err))
+		return nil, a.createErrorTask(task, fmt.Sprintf("Tool execution failed: %v", err))
+	}
+
+	return append([]adk.Message{assistantMessage}, toolResults...), nil
+}
+
+// resumeToolApproval executes the tool calls that waited for the approval of the user and continues the conversation with their results
+func (a *DefaultOpenAICompatibleAgent) resumeToolApproval(ctx context.Context, task *adk.Task, message *adk.Message, approval *toolApproval, messages []adk.Message) (*adk.Task, error) {
+	decisions, ok := approval.decide(message)
+	if !ok {
+		a.logger.Info("reply does not decide on the pending tool calls, asking again",
+			zap.String("task_id", task.ID),
+			zap.String("context_id", task.ContextID))
+		return a.createToolApprovalTask(ctx, task, approval), nil
+	}
+	approval.resolve(ctx, task)
+
+	a.logger.Info("resuming tool calls after approval",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID),
+		zap.Int("count", len(approval.ToolCalls)))
+
+	turn, stopped := a.handleToolCalls(ctx, task, "assistant-"+task.ID+"-approved", approval.Content, approval.apply(decisions), decisions)
+	if stopped != nil {
+		return stopped, nil
+	}
+
+	return a.processWithToolCalling(ctx, task, append(messages, turn...))
+}
+
+// createToolApprovalTask moves the task to the input-required state, asking the user to approve the pending tool calls
+func (a *DefaultOpenAICompatibleAgent) createToolApprovalTask(ctx context.Context, task *adk.Task, approval *toolApproval) *adk.Task {
+	approval.request(ctx, task)
+
+	a.logger.Info("tool calls require approval",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID),
+		zap.Strings("tool_call_ids", approval.Pending))
+	return task
+}
+
 // processWithoutLLM processes the task without LLM when no client is available
 func (a *DefaultOpenAICompatibleAgent) processWithoutLLM(task *adk.Task, message *adk.Message) *adk.Task {
 	response := &adk.Message{
@@ -333,6 +446,30 @@ func (a *DefaultOpenAICompatibleAgent) processWithoutLLM(task *adk.Task, message
 	return task
 }
 
+// createInputRequiredTask moves the task to the input-required state, asking the user for the input a tool needs
+func (a *DefaultOpenAICompatibleAgent) createInputRequiredTask(task *adk.Task, prompt string) *adk.Task {
+	message := &adk.Message{
+		Kind:      "message",
+		MessageID: "input-required-" + task.ID,
+		Role:      "assistant",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": prompt,
+			},
+		},
+	}
+
+	task.History = append(task.History, *message)
+	task.Status.State = adk.TaskStateInputRequired
+	task.Status.Message = message
+
+	a.logger.Info("task requires user input",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID))
+	return task
+}
+
 // createErrorTask creates a task with error state and message
 func (a *DefaultOpenAICompatibleAgent) createErrorTask(task *adk.Task, errorMsg string) *adk.Task {
 	task.Status.State = adk.TaskStateFailed
@@ -350,42 +487,51 @@ func (a *DefaultOpenAICompatibleAgent) createErrorTask(task *adk.Task, errorMsg
 	return task
 }
 
-// executeTools executes all tool calls and returns the tool result messages
-func (a *DefaultOpenAICompatibleAgent) executeTools(ctx context.Context, task *adk.Task, toolCalls []sdk.ChatCompletionMessageToolCall) ([]adk.Message, error) {
-	toolResults := make([]adk.Message, 0, len(toolCalls))
-
-	for _, toolCall := range toolCalls {
-		if toolCall.Type != "function" {
-			continue
-		}
+// executeTools executes all tool calls, concurrently up to the configured limit, and returns the tool result messages in the order of the tool calls
+// Calls the user rejected are not executed, the decisions of the user are recorded in the tool results
+func (a *DefaultOpenAICompatibleAgent) executeTools(ctx context.Context, task *adk.Task, toolCalls []sdk.ChatCompletionMessageToolCall, decisions map[string]toolApprovalDecision) ([]adk.Message, error) {
+	results := make([]*adk.Message, len(toolCalls))
+	errs := make([]error, len(toolCalls))
 
+	ctx = withContextID(withTaskID(ctx, task.ID), task.ContextID)
+	runToolCalls(ctx, a.toolBox, toolCalls, a.config.MaxParallelToolCalls, func(ctx context.Context, index int, toolCall sdk.ChatCompletionMessageToolCall) {
 		function := toolCall.Function
-		if function.Name == "" {
-			continue
-		}
 
-		var args map[string]interface{}
-		if function.Arguments != "" {
-			if err := json.Unmarshal([]byte(function.Arguments), &args); err != nil {
+		var result string
+		if err := validateToolCall(toolCall); err != nil {
+			errs[index] = err
+			result = toolErrorResult(err)
+			a.logger.Error("invalid tool call",
+				zap.String("tool_call_id", toolCall.Id),
+				zap.Error(err))
+		} else if decision, exists := decisions[toolCall.Id]; exists && !decision.approved {
+			result = toolRejectedResult(function.Name)
+			a.logger.Info("tool call rejected by the user",
+				zap.String("tool", function.Name))
+		} else {
+			args, err := ParseToolArguments(function.Name, function.Arguments)
+			if err != nil {
 				a.logger.Error("failed to parse tool arguments",
 					zap.String("tool", function.Name),
 					zap.Error(err))
-				continue
+				recordToolCallFailure(ctx, a.toolBox, function.Name, err)
+			} else {
+				result, err = a.toolBox.ExecuteTool(ctx, function.Name, args)
 			}
-		}
 
-		result, err := a.toolBox.ExecuteTool(ctx, function.Name, args)
-		if err != nil {
-			result = fmt.Sprintf("Error executing tool: %v", err)
-			a.logger.Error("tool execution failed",
-				zap.String("tool", function.Name),
-				zap.Error(err))
-		} else {
-			a.logger.Info("tool executed successfully",
-				zap.String("tool", function.Name))
+			if err != nil {
+				errs[index] = err
+				result = toolErrorResult(err)
+				a.logger.Error("tool execution failed",
+					zap.String("tool", function.Name),
+					zap.Error(err))
+			} else {
+				a.logger.Info("tool executed successfully",
+					zap.String("tool", function.Name))
+			}
 		}
 
-		toolResultMessage := adk.Message{
+		results[index] = &adk.Message{
 			Kind:      "message",
 			MessageID: fmt.Sprintf("tool-result-%s", toolCall.Id),
 			Role:      "tool",
@@ -400,11 +546,21 @@ func (a *DefaultOpenAICompatibleAgent) executeTools(ctx context.Context, task *a
 				},
 			},
 		}
+		recordToolApproval(results[index], decisions, toolCall.Id)
+	})
 
-		toolResults = append(toolResults, toolResultMessage)
-		task.History = append(task.History, toolResultMessage)
+	toolResults := make([]adk.Message, 0, len(toolCalls))
+	for _, toolResultMessage := range results {
+		if toolResultMessage == nil {
+			continue
+		}
+		toolResults = append(toolResults, *toolResultMessage)
+		task.History = append(task.History, *toolResultMessage)
 	}
 
+	if inputErr := firstInputRequired(errs); inputErr != nil {
+		return toolResults, inputErr
+	}
 	return toolResults, nil
 }
 
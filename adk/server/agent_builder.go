@@ -30,6 +30,8 @@ type AgentBuilder interface {
 	WithMaxChatCompletion(max int) AgentBuilder
 	// WithMaxConversationHistory sets the maximum conversation history for the agent
 	WithMaxConversationHistory(max int) AgentBuilder
+	// WithMaxParallelToolCalls sets the maximum tool calls of one LLM turn executed concurrently
+	WithMaxParallelToolCalls(max int) AgentBuilder
 	// GetConfig returns the current agent configuration (for testing purposes)
 	GetConfig() *config.AgentConfig
 	// Build creates and returns the configured agent
@@ -80,6 +82,7 @@ func NewAgentBuilder(logger *zap.Logger) AgentBuilder {
 			Provider:                    "openai",
 			Model:                       "gpt-3.5-turbo",
 			MaxChatCompletionIterations: 10,
+			MaxParallelToolCalls:        DefaultMaxParallelToolCalls,
 			MaxConversationHistory:      20,
 			SystemPrompt:                "You are a helpful AI assistant.",
 		}
@@ -136,6 +139,12 @@ func (b *AgentBuilderImpl) WithMaxConversationHistory(max int) AgentBuilder {
 	return b
 }
 
+// WithMaxParallelToolCalls sets the maximum tool calls of one LLM turn executed concurrently
+func (b *AgentBuilderImpl) WithMaxParallelToolCalls(max int) AgentBuilder {
+	b.config.MaxParallelToolCalls = max
+	return b
+}
+
 // GetConfig returns the current agent configuration (for testing purposes)
 func (b *AgentBuilderImpl) GetConfig() *config.AgentConfig {
 	return b.config
@@ -154,7 +163,7 @@ func (b *AgentBuilderImpl) Build() (*DefaultOpenAICompatibleAgent, error) {
 	if b.llmClient != nil {
 		agent.llmClient = b.llmClient
 	} else if b.config != nil {
-		client, err := NewOpenAICompatibleLLMClient(b.config, b.logger)
+		client, err := NewLLMClient(b.config, b.logger)
 		if err != nil {
 			return nil, err
 		}
@@ -1,13 +1,22 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	config "github.com/inference-gateway/a2a/adk/server/config"
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
 	sdk "github.com/inference-gateway/sdk"
 	zap "go.uber.org/zap"
 )
@@ -22,14 +31,38 @@ type LLMClient interface {
 }
 
 var _ LLMClient = (*OpenAICompatibleLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*OpenAICompatibleLLMClient)(nil)
 
-// OpenAICompatibleLLMClient implements LLMClient using an OpenAI-compatible API via the Inference Gateway SDK
+// instrumentedLLMClient is implemented by LLM clients recording the attempts of their requests in the metrics
+type instrumentedLLMClient interface {
+	SetTelemetry(telemetry otel.OpenTelemetry)
+}
+
+// OpenAICompatibleLLMClient implements LLMClient using the OpenAI-compatible chat completions API of the Inference Gateway
 type OpenAICompatibleLLMClient struct {
-	client   sdk.Client
-	config   *config.AgentConfig
-	logger   *zap.Logger
-	provider sdk.Provider
-	model    string
+	httpClient *http.Client
+	config     *config.AgentConfig
+	logger     *zap.Logger
+	provider   sdk.Provider
+	model      string
+	telemetry  otel.OpenTelemetry
+}
+
+// Backoff between retries of LLM requests when not configured
+const (
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// chatCompletionRequest extends the SDK request with the sampling and tool options it does not support
+type chatCompletionRequest struct {
+	sdk.CreateChatCompletionRequest
+	Temperature      *float64    `json:"temperature,omitempty"`
+	TopP             *float64    `json:"top_p,omitempty"`
+	FrequencyPenalty *float64    `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64    `json:"presence_penalty,omitempty"`
+	Stop             []string    `json:"stop,omitempty"`
+	ToolChoice       interface{} `json:"tool_choice,omitempty"`
 }
 
 // NewOpenAICompatibleLLMClient creates a new OpenAI-compatible LLM client
@@ -46,26 +79,12 @@ func NewOpenAICompatibleLLMClient(cfg *config.AgentConfig, logger *zap.Logger) (
 		return nil, fmt.Errorf("model is required")
 	}
 
-	clientOptions := &sdk.ClientOptions{}
-
-	if cfg.BaseURL != "" {
-		clientOptions.BaseURL = cfg.BaseURL
-	}
-
-	if cfg.APIKey != "" {
-		clientOptions.APIKey = cfg.APIKey
-	}
+	httpClient := &http.Client{}
 
 	if cfg.Timeout > 0 {
-		clientOptions.Timeout = cfg.Timeout
-	}
-
-	if len(cfg.CustomHeaders) > 0 {
-		clientOptions.Headers = cfg.CustomHeaders
+		httpClient.Timeout = cfg.Timeout
 	}
 
-	client := sdk.NewClient(clientOptions)
-
 	provider, err := parseProvider(cfg.Provider)
 	if err != nil {
 		return nil, fmt.Errorf("invalid provider %s: %w", cfg.Provider, err)
@@ -74,73 +93,184 @@ func NewOpenAICompatibleLLMClient(cfg *config.AgentConfig, logger *zap.Logger) (
 	model := parseModelName(cfg.Model, cfg.Provider)
 
 	return &OpenAICompatibleLLMClient{
-		client:   client,
-		config:   cfg,
-		logger:   logger,
-		provider: provider,
-		model:    model,
+		httpClient: httpClient,
+		config:     cfg,
+		logger:     logger,
+		provider:   provider,
+		model:      model,
 	}, nil
 }
 
-// CreateChatCompletion implements LLMClient.CreateChatCompletion using SDK messages
-func (c *OpenAICompatibleLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
-	options := &sdk.CreateChatCompletionRequest{}
+// ResolveRequestOptions implements LLMRequestOptionsResolver, applying the override of the context to the configuration.
+// Top-p and penalties are only sent when configured with a non-default value.
+func (c *OpenAICompatibleLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	temperature := c.config.Temperature
+	options := LLMRequestOptions{
+		Model:       c.model,
+		Temperature: &temperature,
+	}
 
+	if c.config.TopP > 0 && c.config.TopP != 1 {
+		topP := c.config.TopP
+		options.TopP = &topP
+	}
+	if c.config.FrequencyPenalty != 0 {
+		frequencyPenalty := c.config.FrequencyPenalty
+		options.FrequencyPenalty = &frequencyPenalty
+	}
+	if c.config.PresencePenalty != 0 {
+		presencePenalty := c.config.PresencePenalty
+		options.PresencePenalty = &presencePenalty
+	}
 	if c.config.MaxTokens > 0 {
-		options.MaxTokens = &c.config.MaxTokens
+		maxTokens := c.config.MaxTokens
+		options.MaxTokens = &maxTokens
 	}
 
-	var response *sdk.CreateChatCompletionResponse
-	var lastErr error
-
-	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
-		if attempt > 0 {
-			c.logger.Debug("retrying llm request",
-				zap.Int("attempt", attempt),
-				zap.Int("max_retries", c.config.MaxRetries),
-				zap.Error(lastErr))
-
-			backoff := time.Duration(attempt) * time.Second
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-			}
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	options = options.merge(override)
+	options.Model = parseModelName(options.Model, c.config.Provider)
+	return options
+}
+
+// newRequest builds the chat completion request with the options resolved for the context
+func (c *OpenAICompatibleLLMClient) newRequest(ctx context.Context, messages []sdk.Message, tools []sdk.ChatCompletionTool, stream bool) chatCompletionRequest {
+	options := c.ResolveRequestOptions(ctx)
+
+	request := chatCompletionRequest{
+		CreateChatCompletionRequest: sdk.CreateChatCompletionRequest{
+			Model:     options.Model,
+			Messages:  messages,
+			MaxTokens: options.MaxTokens,
+			Stream:    &stream,
+		},
+		Temperature:      options.Temperature,
+		TopP:             options.TopP,
+		FrequencyPenalty: options.FrequencyPenalty,
+		PresencePenalty:  options.PresencePenalty,
+		Stop:             options.Stop,
+	}
+
+	if stream {
+		request.StreamOptions = &sdk.ChatCompletionStreamOptions{
+			IncludeUsage: true,
 		}
+	}
 
-		if len(tools) > 0 {
-			response, lastErr = c.client.WithMiddlewareOptions(&sdk.MiddlewareOptions{
-				SkipA2A: true,
-				SkipMCP: true,
-			}).WithOptions(options).WithTools(&tools).GenerateContent(
-				ctx,
-				c.provider,
-				c.model,
-				messages,
-			)
-		} else {
-			response, lastErr = c.client.WithMiddlewareOptions(&sdk.MiddlewareOptions{
-				SkipA2A: true,
-				SkipMCP: true,
-			}).WithOptions(options).GenerateContent(
-				ctx,
-				c.provider,
-				c.model,
-				messages,
-			)
+	if len(tools) > 0 {
+		request.Tools = &tools
+		request.ToolChoice = options.ToolChoice
+	}
+
+	return request
+}
+
+// post sends the chat completion request, returning the response if the gateway accepted it
+func (c *OpenAICompatibleLLMClient) post(ctx context.Context, request chatCompletionRequest) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(c.config.BaseURL, "/") + "/chat/completions")
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %s: %w", c.config.BaseURL, err)
+	}
+	query := endpoint.Query()
+	query.Set("provider", string(c.provider))
+	endpoint.RawQuery = query.Encode()
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("X-MCP-Bypass", "true")
+	httpRequest.Header.Set("X-A2A-Bypass", "true")
+	if c.config.UserAgent != "" {
+		httpRequest.Header.Set("User-Agent", c.config.UserAgent)
+	}
+	if c.config.APIKey != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+	for name, value := range c.config.CustomHeaders {
+		httpRequest.Header.Set(name, value)
+	}
+
+	response, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		defer func() {
+			_ = response.Body.Close()
+		}()
+
+		responseBody, _ := io.ReadAll(response.Body)
+		apiErr := &LLMAPIError{
+			StatusCode: response.StatusCode,
+			Body:       string(responseBody),
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+		}
+		var errorResp sdk.Error
+		if err := json.Unmarshal(responseBody, &errorResp); err == nil && errorResp.Error != nil {
+			apiErr.Message = *errorResp.Error
 		}
+		return nil, apiErr
+	}
+
+	return response, nil
+}
 
-		if lastErr == nil {
+// generateContent sends a non-streaming chat completion request
+func (c *OpenAICompatibleLLMClient) generateContent(ctx context.Context, request chatCompletionRequest) (*sdk.CreateChatCompletionResponse, error) {
+	response, err := c.post(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	var result sdk.CreateChatCompletionResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result, nil
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion using SDK messages
+func (c *OpenAICompatibleLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	request := c.newRequest(ctx, messages, tools, false)
+
+	var response *sdk.CreateChatCompletionResponse
+	var err error
+	attempts := 0
+
+	for {
+		attempts++
+		response, err = c.generateContent(ctx, request)
+		if err == nil || !c.shouldRetry(ctx, attempts, err) {
 			break
 		}
-
-		c.logger.Debug("llm request failed",
-			zap.Error(lastErr),
-			zap.Int("attempt", attempt+1))
+		if !c.waitRetry(ctx, attempts, err) {
+			c.recordAttempts(ctx, request.Model, attempts, false)
+			return nil, ctx.Err()
+		}
 	}
 
-	if lastErr != nil {
-		return nil, fmt.Errorf("llm request failed after %d retries: %w", c.config.MaxRetries, lastErr)
+	c.recordAttempts(ctx, request.Model, attempts, err == nil)
+
+	if err != nil {
+		c.logger.Debug("llm request failed",
+			zap.Error(err),
+			zap.Int("attempts", attempts))
+		if attempts == 1 {
+			return nil, fmt.Errorf("llm request failed: %w", err)
+		}
+		return nil, fmt.Errorf("llm request failed after %d attempts: %w", attempts, err)
 	}
 
 	if len(response.Choices) == 0 {
@@ -149,12 +279,14 @@ func (c *OpenAICompatibleLLMClient) CreateChatCompletion(ctx context.Context, me
 
 	c.logger.Info("llm chat completion successful",
 		zap.Int("choices", len(response.Choices)),
-		zap.Bool("has_tools", len(tools) > 0))
+		zap.Bool("has_tools", len(tools) > 0),
+		zap.Int("attempts", attempts))
 
 	return response, nil
 }
 
-// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion using SDK messages
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion using SDK messages.
+// A stream failing before its first chunk is retried like a chat completion.
 func (c *OpenAICompatibleLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
 	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
 	errorChan := make(chan error, 1)
@@ -163,80 +295,225 @@ func (c *OpenAICompatibleLLMClient) CreateStreamingChatCompletion(ctx context.Co
 		defer close(responseChan)
 		defer close(errorChan)
 
-		options := &sdk.CreateChatCompletionRequest{}
+		request := c.newRequest(ctx, messages, tools, true)
 
-		if c.config.MaxTokens > 0 {
-			options.MaxTokens = &c.config.MaxTokens
+		var err error
+		attempts := 0
+
+		for {
+			attempts++
+			var started bool
+			started, err = c.stream(ctx, request, responseChan)
+			if err == nil || started || !c.shouldRetry(ctx, attempts, err) {
+				break
+			}
+			if !c.waitRetry(ctx, attempts, err) {
+				c.recordAttempts(ctx, request.Model, attempts, false)
+				errorChan <- ctx.Err()
+				return
+			}
 		}
 
-		var events <-chan sdk.SSEvent
-		var err error
+		c.recordAttempts(ctx, request.Model, attempts, err == nil)
+
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// stream sends the streaming chat completion request and forwards its chunks,
+// it reports whether a chunk was forwarded before the stream failed.
+// A stream cut short by the cancellation of ctx fails with the error of ctx.
+func (c *OpenAICompatibleLLMClient) stream(ctx context.Context, request chatCompletionRequest, responseChan chan<- *sdk.CreateChatCompletionStreamResponse) (bool, error) {
+	response, err := c.post(ctx, request)
+	if err != nil {
+		return false, fmt.Errorf("failed to create stream: %w", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
 
-		if len(tools) > 0 {
-			events, err = c.client.WithMiddlewareOptions(&sdk.MiddlewareOptions{
-				SkipA2A: true,
-				SkipMCP: true,
-			}).WithOptions(options).WithTools(&tools).GenerateContentStream(
-				ctx,
-				c.provider,
-				c.model,
-				messages,
-			)
-		} else {
-			events, err = c.client.WithMiddlewareOptions(&sdk.MiddlewareOptions{
-				SkipA2A: true,
-				SkipMCP: true,
-			}).WithOptions(options).GenerateContentStream(
-				ctx,
-				c.provider,
-				c.model,
-				messages,
-			)
+	// The usage of the completion follows the chunk finishing it, it is attached to that chunk
+	// so that consumers that stop reading at the finish reason still receive it
+	var finished *sdk.CreateChatCompletionStreamResponse
+	started := false
+	send := func(streamResponse *sdk.CreateChatCompletionStreamResponse) bool {
+		select {
+		case responseChan <- streamResponse:
+			started = true
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	flush := func() bool {
+		if finished == nil {
+			return true
 		}
+		sent := send(finished)
+		finished = nil
+		return sent
+	}
+
+	reader := bufio.NewReader(response.Body)
+	for {
+		line, err := reader.ReadString('\n')
 		if err != nil {
-			errorChan <- fmt.Errorf("failed to create stream: %w", err)
-			return
+			if err != io.EOF {
+				return started, fmt.Errorf("failed to read stream: %w", err)
+			}
+			if !flush() {
+				return started, ctx.Err()
+			}
+			return started, nil
+		}
+
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
 		}
 
-		for event := range events {
-			if event.Event == nil {
-				continue
+		if data == "[DONE]" {
+			if !flush() {
+				return started, ctx.Err()
 			}
+			return started, nil
+		}
 
-			switch *event.Event {
-			case sdk.ContentDelta:
-				if event.Data != nil {
-					var streamResponse sdk.CreateChatCompletionStreamResponse
-					if err := json.Unmarshal(*event.Data, &streamResponse); err != nil {
-						c.logger.Debug("error parsing stream response", zap.Error(err))
-						continue
-					}
-
-					select {
-					case responseChan <- &streamResponse:
-					case <-ctx.Done():
-						return
-					}
-				}
-
-			case sdk.StreamEnd:
-				return
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &errResp); err == nil && errResp.Error != "" {
+			return started, fmt.Errorf("llm error: %s", errResp.Error)
+		}
 
-			default:
-				if event.Data != nil {
-					var errResp struct {
-						Error string `json:"error"`
-					}
-					if err := json.Unmarshal(*event.Data, &errResp); err == nil && errResp.Error != "" {
-						errorChan <- fmt.Errorf("llm error: %s", errResp.Error)
-						return
-					}
-				}
+		var streamResponse sdk.CreateChatCompletionStreamResponse
+		if err := json.Unmarshal([]byte(data), &streamResponse); err != nil {
+			c.logger.Debug("error parsing stream response", zap.Error(err))
+			continue
+		}
+
+		if finished != nil && len(streamResponse.Choices) == 0 && streamResponse.Usage != nil {
+			finished.Usage = streamResponse.Usage
+			if !flush() {
+				return started, ctx.Err()
 			}
+			continue
+		}
+		if !flush() {
+			return started, ctx.Err()
 		}
-	}()
 
-	return responseChan, errorChan
+		if len(streamResponse.Choices) > 0 && streamResponse.Choices[0].FinishReason != "" {
+			finished = &streamResponse
+			continue
+		}
+
+		if !send(&streamResponse) {
+			return started, ctx.Err()
+		}
+	}
+}
+
+// shouldRetry reports whether the failed attempt is retried: only timeouts, connection errors,
+// rate limits and server errors are, up to the configured number of retries. Failures whose
+// Retry-After exceeds the maximum backoff are not retried.
+func (c *OpenAICompatibleLLMClient) shouldRetry(ctx context.Context, attempts int, err error) bool {
+	if attempts > c.config.MaxRetries {
+		return false
+	}
+
+	var apiErr *LLMAPIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > c.retryMaxBackoff() {
+		c.logger.Warn("llm gateway asked to retry later than the maximum backoff, giving up",
+			zap.Duration("retry_after", apiErr.RetryAfter),
+			zap.Duration("max_backoff", c.retryMaxBackoff()))
+		return false
+	}
+	return isRetryableLLMError(ctx, err)
+}
+
+// waitRetry waits before the next attempt, it returns false if the context is done first
+func (c *OpenAICompatibleLLMClient) waitRetry(ctx context.Context, attempts int, err error) bool {
+	delay := c.retryDelay(attempts, err)
+
+	c.logger.Warn("retrying llm request",
+		zap.Int("attempt", attempts+1),
+		zap.Int("max_retries", c.config.MaxRetries),
+		zap.Duration("delay", delay),
+		zap.Error(err))
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// retryDelay returns the exponential backoff with jitter after the failed attempt,
+// or the Retry-After of the failure if the LLM gateway asked to wait longer, capped at the maximum backoff
+func (c *OpenAICompatibleLLMClient) retryDelay(attempts int, err error) time.Duration {
+	initial := c.config.RetryInitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryInitialBackoff
+	}
+	maxBackoff := c.retryMaxBackoff()
+
+	backoff := maxBackoff
+	if attempts-1 < 32 {
+		if exponential := initial << (attempts - 1); exponential > 0 && exponential < maxBackoff {
+			backoff = exponential
+		}
+	}
+	delay := backoff/2 + rand.N(backoff/2+1)
+
+	var apiErr *LLMAPIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = min(apiErr.RetryAfter, maxBackoff)
+	}
+	return delay
+}
+
+// retryMaxBackoff returns the configured maximum backoff between retries, or the default
+func (c *OpenAICompatibleLLMClient) retryMaxBackoff() time.Duration {
+	if c.config.RetryMaxBackoff <= 0 {
+		return defaultRetryMaxBackoff
+	}
+	return c.config.RetryMaxBackoff
+}
+
+// recordAttempts records the number of attempts of a request in the metrics
+func (c *OpenAICompatibleLLMClient) recordAttempts(ctx context.Context, model string, attempts int, success bool) {
+	if c.telemetry == nil {
+		return
+	}
+	c.telemetry.RecordLLMRequestAttempts(ctx, otel.TelemetryAttributes{
+		Provider: string(c.provider),
+		Model:    model,
+	}, attempts, success)
+}
+
+// SetTelemetry records the attempts of the requests of the client in the metrics
+func (c *OpenAICompatibleLLMClient) SetTelemetry(telemetry otel.OpenTelemetry) {
+	c.telemetry = telemetry
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
 }
 
 // parseProvider converts a provider string to SDK Provider type
@@ -0,0 +1,532 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+// Stores of the LLM response cache
+const (
+	LLMCacheStoreMemory = "memory"
+	LLMCacheStoreFile   = "file"
+)
+
+// LLMResponseCache stores the responses of chat completions by the key of their request
+type LLMResponseCache interface {
+	// Get returns the response stored for the key, reporting whether one was found and has not expired
+	Get(ctx context.Context, key string) (*sdk.CreateChatCompletionResponse, bool, error)
+	// Set stores the response for the key
+	Set(ctx context.Context, key string, response *sdk.CreateChatCompletionResponse) error
+}
+
+// NewLLMResponseCache creates the response cache of the configured store
+func NewLLMResponseCache(cfg config.LLMCacheConfig) (LLMResponseCache, error) {
+	switch cfg.Store {
+	case "", LLMCacheStoreMemory:
+		return NewMemoryLLMResponseCache(cfg.MaxEntries, cfg.TTL), nil
+	case LLMCacheStoreFile:
+		return NewFileLLMResponseCache(cfg.Dir, cfg.TTL)
+	default:
+		return nil, fmt.Errorf("unsupported llm cache store: %s", cfg.Store)
+	}
+}
+
+// cachedLLMResponse is a response stored in the cache
+type cachedLLMResponse struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Response json.RawMessage `json:"response"`
+}
+
+// expired reports whether the response outlived the ttl, a ttl of zero never expires
+func (r cachedLLMResponse) expired(now time.Time, ttl time.Duration) bool {
+	return ttl > 0 && now.Sub(r.StoredAt) >= ttl
+}
+
+// decode returns a copy of the stored response
+func (r cachedLLMResponse) decode() (*sdk.CreateChatCompletionResponse, error) {
+	var response sdk.CreateChatCompletionResponse
+	if err := json.Unmarshal(r.Response, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode cached llm response: %w", err)
+	}
+	return &response, nil
+}
+
+var _ LLMResponseCache = (*MemoryLLMResponseCache)(nil)
+
+// MemoryLLMResponseCache keeps the most recently used responses in memory,
+// evicting the least recently used one once it holds its maximum entries
+type MemoryLLMResponseCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryLLMCacheEntry struct {
+	key      string
+	response cachedLLMResponse
+}
+
+// NewMemoryLLMResponseCache creates an in-memory LRU cache, a maxEntries of zero keeps all responses
+func NewMemoryLLMResponseCache(maxEntries int, ttl time.Duration) *MemoryLLMResponseCache {
+	return &MemoryLLMResponseCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements LLMResponseCache.Get
+func (c *MemoryLLMResponseCache) Get(ctx context.Context, key string) (*sdk.CreateChatCompletionResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*memoryLLMCacheEntry)
+	if entry.response.expired(c.now(), c.ttl) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	response, err := entry.response.decode()
+	if err != nil {
+		return nil, false, err
+	}
+	return response, true, nil
+}
+
+// Set implements LLMResponseCache.Set
+func (c *MemoryLLMResponseCache) Set(ctx context.Context, key string, response *sdk.CreateChatCompletionResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to encode llm response: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := cachedLLMResponse{StoredAt: c.now(), Response: data}
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*memoryLLMCacheEntry).response = stored
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryLLMCacheEntry{key: key, response: stored})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryLLMCacheEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of responses in the cache
+func (c *MemoryLLMResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+var _ LLMResponseCache = (*FileLLMResponseCache)(nil)
+
+// FileLLMResponseCache stores each response as a JSON file in a directory, so that the
+// responses outlive the process and can be shared by the runs of a test suite
+type FileLLMResponseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewFileLLMResponseCache creates a file-backed cache in dir, creating the directory if needed
+func NewFileLLMResponseCache(dir string, ttl time.Duration) (*FileLLMResponseCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("llm cache directory is required for the file store")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create llm cache directory: %w", err)
+	}
+	return &FileLLMResponseCache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}, nil
+}
+
+// Get implements LLMResponseCache.Get
+func (c *FileLLMResponseCache) Get(ctx context.Context, key string) (*sdk.CreateChatCompletionResponse, bool, error) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cached llm response: %w", err)
+	}
+
+	var stored cachedLLMResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached llm response: %w", err)
+	}
+	if stored.expired(c.now(), c.ttl) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, false, fmt.Errorf("failed to remove expired llm response: %w", err)
+		}
+		return nil, false, nil
+	}
+
+	response, err := stored.decode()
+	if err != nil {
+		return nil, false, err
+	}
+	return response, true, nil
+}
+
+// Set implements LLMResponseCache.Set, the file is replaced atomically
+func (c *FileLLMResponseCache) Set(ctx context.Context, key string, response *sdk.CreateChatCompletionResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to encode llm response: %w", err)
+	}
+	data, err = json.Marshal(cachedLLMResponse{StoredAt: c.now(), Response: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode llm response: %w", err)
+	}
+
+	if err := writeFileAtomic(c.path(key), data); err != nil {
+		return fmt.Errorf("failed to write llm response: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a key
+func (c *FileLLMResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// writeFileAtomic replaces the file with the data, readers see either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+var _ LLMClient = (*CachingLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*CachingLLMClient)(nil)
+var _ instrumentedLLMClient = (*CachingLLMClient)(nil)
+
+// CachingLLMClient answers repeated completions from a cache instead of the LLM. Requests are
+// keyed by a hash of their model, sampling parameters, messages and tools, so it suits the
+// deterministic completions of evaluation and CI runs. Cached responses report no token usage
+// and are replayed as synthetic chunks to streaming completions.
+type CachingLLMClient struct {
+	client LLMClient
+	cache  LLMResponseCache
+	logger *zap.Logger
+}
+
+// NewCachingLLMClient wraps an LLM client with a response cache
+func NewCachingLLMClient(client LLMClient, cache LLMResponseCache, logger *zap.Logger) *CachingLLMClient {
+	return &CachingLLMClient{
+		client: client,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion
+func (c *CachingLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	key, response := c.lookup(ctx, messages, tools)
+	if response != nil {
+		return response, nil
+	}
+
+	response, err := c.client.CreateChatCompletion(ctx, messages, tools...)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, key, response)
+	return response, nil
+}
+
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion. Cached responses are
+// replayed as chunks, other streams are forwarded and stored once they completed without an error.
+func (c *CachingLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+	errorChan := make(chan error, 1)
+
+	key, cached := c.lookup(ctx, messages, tools)
+	if cached != nil {
+		go func() {
+			defer close(responseChan)
+			defer close(errorChan)
+
+			for _, chunk := range streamChunks(cached) {
+				select {
+				case responseChan <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return responseChan, errorChan
+	}
+
+	upstreamResponses, upstreamErrors := c.client.CreateStreamingChatCompletion(ctx, messages, tools...)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		assembler := newStreamAssembler()
+		for response := range upstreamResponses {
+			assembler.add(response)
+
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := <-upstreamErrors; err != nil {
+			errorChan <- err
+			return
+		}
+		if response := assembler.response(); response != nil {
+			c.store(ctx, key, response)
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// ResolveRequestOptions implements LLMRequestOptionsResolver for the wrapped client
+func (c *CachingLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	if resolver, ok := c.client.(LLMRequestOptionsResolver); ok {
+		return resolver.ResolveRequestOptions(ctx)
+	}
+
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	return LLMRequestOptions{}.merge(override)
+}
+
+// SetTelemetry passes the telemetry on to the wrapped client
+func (c *CachingLLMClient) SetTelemetry(telemetry otel.OpenTelemetry) {
+	if instrumented, ok := c.client.(instrumentedLLMClient); ok {
+		instrumented.SetTelemetry(telemetry)
+	}
+}
+
+// lookup returns the key of the request and its cached response, failures of the cache are logged and treated as misses
+func (c *CachingLLMClient) lookup(ctx context.Context, messages []sdk.Message, tools []sdk.ChatCompletionTool) (string, *sdk.CreateChatCompletionResponse) {
+	key, err := c.key(ctx, messages, tools)
+	if err != nil {
+		c.logger.Warn("failed to compute llm cache key", zap.Error(err))
+		return "", nil
+	}
+
+	response, found, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn("failed to read llm response cache", zap.String("key", key), zap.Error(err))
+		return key, nil
+	}
+	if !found {
+		c.logger.Debug("llm response cache miss", zap.String("key", key))
+		return key, nil
+	}
+
+	c.logger.Debug("llm response cache hit", zap.String("key", key))
+	response.Usage = nil
+	return key, response
+}
+
+// store caches the response of a request
+func (c *CachingLLMClient) store(ctx context.Context, key string, response *sdk.CreateChatCompletionResponse) {
+	if key == "" || response == nil || len(response.Choices) == 0 {
+		return
+	}
+	if err := c.cache.Set(ctx, key, response); err != nil {
+		c.logger.Warn("failed to write llm response cache", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// key hashes the model and sampling parameters sent for the context with the messages and tools of the request
+func (c *CachingLLMClient) key(ctx context.Context, messages []sdk.Message, tools []sdk.ChatCompletionTool) (string, error) {
+	data, err := json.Marshal(struct {
+		Options  LLMRequestOptions        `json:"options"`
+		Messages []sdk.Message            `json:"messages"`
+		Tools    []sdk.ChatCompletionTool `json:"tools,omitempty"`
+	}{
+		Options:  c.ResolveRequestOptions(ctx),
+		Messages: messages,
+		Tools:    tools,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// streamChunks splits a response into the chunks of a stream: the content, one chunk
+// per tool call and a last chunk carrying the finish reason
+func streamChunks(response *sdk.CreateChatCompletionResponse) []*sdk.CreateChatCompletionStreamResponse {
+	chunk := func(delta sdk.ChatCompletionStreamResponseDelta, finishReason string) *sdk.CreateChatCompletionStreamResponse {
+		return &sdk.CreateChatCompletionStreamResponse{
+			ID:      response.Id,
+			Created: response.Created,
+			Model:   response.Model,
+			Object:  "chat.completion.chunk",
+			Choices: []sdk.ChatCompletionStreamChoice{{Delta: delta, FinishReason: finishReason}},
+		}
+	}
+
+	if len(response.Choices) == 0 {
+		return nil
+	}
+	choice := response.Choices[0]
+
+	chunks := []*sdk.CreateChatCompletionStreamResponse{
+		chunk(sdk.ChatCompletionStreamResponseDelta{Role: string(sdk.Assistant), Content: choice.Message.Content}, ""),
+	}
+
+	if choice.Message.ToolCalls != nil {
+		for i, toolCall := range *choice.Message.ToolCalls {
+			toolCallChunk := sdk.ChatCompletionMessageToolCallChunk{
+				Index: i,
+				ID:    toolCall.Id,
+				Type:  string(toolCall.Type),
+			}
+			toolCallChunk.Function.Name = toolCall.Function.Name
+			toolCallChunk.Function.Arguments = toolCall.Function.Arguments
+			chunks = append(chunks, chunk(sdk.ChatCompletionStreamResponseDelta{
+				ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{toolCallChunk},
+			}, ""))
+		}
+	}
+
+	finishReason := string(choice.FinishReason)
+	if finishReason == "" {
+		finishReason = string(sdk.Stop)
+	}
+	return append(chunks, chunk(sdk.ChatCompletionStreamResponseDelta{}, finishReason))
+}
+
+// streamAssembler assembles the chunks of a stream into a complete response
+type streamAssembler struct {
+	assembled    sdk.CreateChatCompletionResponse
+	content      string
+	toolCalls    map[int]*sdk.ChatCompletionMessageToolCall
+	finishReason string
+}
+
+func newStreamAssembler() *streamAssembler {
+	return &streamAssembler{toolCalls: make(map[int]*sdk.ChatCompletionMessageToolCall)}
+}
+
+// add assembles a chunk
+func (a *streamAssembler) add(chunk *sdk.CreateChatCompletionStreamResponse) {
+	if chunk == nil {
+		return
+	}
+	if chunk.ID != "" {
+		a.assembled.Id = chunk.ID
+	}
+	if chunk.Model != "" {
+		a.assembled.Model = chunk.Model
+	}
+	if chunk.Created != 0 {
+		a.assembled.Created = chunk.Created
+	}
+	if len(chunk.Choices) == 0 {
+		return
+	}
+
+	choice := chunk.Choices[0]
+	a.content += choice.Delta.Content
+	for _, toolCallChunk := range choice.Delta.ToolCalls {
+		toolCall, ok := a.toolCalls[toolCallChunk.Index]
+		if !ok {
+			toolCall = &sdk.ChatCompletionMessageToolCall{Type: sdk.Function}
+			a.toolCalls[toolCallChunk.Index] = toolCall
+		}
+		if toolCallChunk.ID != "" {
+			toolCall.Id = toolCallChunk.ID
+		}
+		if toolCallChunk.Function.Name != "" {
+			toolCall.Function.Name = toolCallChunk.Function.Name
+		}
+		toolCall.Function.Arguments += toolCallChunk.Function.Arguments
+	}
+	if choice.FinishReason != "" {
+		a.finishReason = choice.FinishReason
+	}
+}
+
+// response returns the assembled response of a stream that ended without an error, nil when it was empty
+func (a *streamAssembler) response() *sdk.CreateChatCompletionResponse {
+	if a.content == "" && len(a.toolCalls) == 0 {
+		return nil
+	}
+
+	message := sdk.Message{Role: sdk.Assistant, Content: a.content}
+	finishReason := a.finishReason
+	if len(a.toolCalls) > 0 {
+		toolCalls := orderedToolCalls(a.toolCalls)
+		message.ToolCalls = &toolCalls
+		if finishReason == "" {
+			finishReason = string(sdk.ToolCalls)
+		}
+	}
+	if finishReason == "" {
+		finishReason = string(sdk.Stop)
+	}
+
+	response := a.assembled
+	response.Object = "chat.completion"
+	response.Choices = []sdk.ChatCompletionChoice{{
+		FinishReason: sdk.ChatCompletionChoiceFinishReason(finishReason),
+		Message:      message,
+	}}
+	return &response
+}
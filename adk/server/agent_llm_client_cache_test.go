@@ -0,0 +1,263 @@
+package server_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// streamOf returns a stream answering the chunks
+func streamOf(chunks ...*sdk.CreateChatCompletionStreamResponse) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse, len(chunks))
+	errorChan := make(chan error)
+	for _, chunk := range chunks {
+		responseChan <- chunk
+	}
+	close(responseChan)
+	close(errorChan)
+	return responseChan, errorChan
+}
+
+func streamChunk(delta sdk.ChatCompletionStreamResponseDelta, finishReason string) *sdk.CreateChatCompletionStreamResponse {
+	return &sdk.CreateChatCompletionStreamResponse{
+		Model:   "gpt-4o",
+		Choices: []sdk.ChatCompletionStreamChoice{{Delta: delta, FinishReason: finishReason}},
+	}
+}
+
+func TestMemoryLLMResponseCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts the least recently used response", func(t *testing.T) {
+		cache := server.NewMemoryLLMResponseCache(2, time.Hour)
+		require.NoError(t, cache.Set(ctx, "a", completionResponse("A")))
+		require.NoError(t, cache.Set(ctx, "b", completionResponse("B")))
+
+		_, found, err := cache.Get(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, found)
+
+		require.NoError(t, cache.Set(ctx, "c", completionResponse("C")))
+		assert.Equal(t, 2, cache.Len())
+
+		_, found, _ = cache.Get(ctx, "b")
+		assert.False(t, found, "b was the least recently used")
+		response, found, _ := cache.Get(ctx, "a")
+		require.True(t, found)
+		assert.Equal(t, "A", response.Choices[0].Message.Content)
+	})
+
+	t.Run("expires responses after the ttl", func(t *testing.T) {
+		cache := server.NewMemoryLLMResponseCache(10, 20*time.Millisecond)
+		require.NoError(t, cache.Set(ctx, "a", completionResponse("A")))
+
+		_, found, _ := cache.Get(ctx, "a")
+		require.True(t, found)
+
+		time.Sleep(30 * time.Millisecond)
+		_, found, _ = cache.Get(ctx, "a")
+		assert.False(t, found)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("returns copies of the responses", func(t *testing.T) {
+		cache := server.NewMemoryLLMResponseCache(10, 0)
+		require.NoError(t, cache.Set(ctx, "a", completionResponse("A")))
+
+		response, _, _ := cache.Get(ctx, "a")
+		response.Choices[0].Message.Content = "changed"
+
+		response, _, _ = cache.Get(ctx, "a")
+		assert.Equal(t, "A", response.Choices[0].Message.Content)
+	})
+}
+
+func TestFileLLMResponseCache(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "llm-cache")
+
+	cache, err := server.NewFileLLMResponseCache(dir, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, cache.Set(ctx, "a", newToolCallResponse("search")))
+
+	reopened, err := server.NewFileLLMResponseCache(dir, 50*time.Millisecond)
+	require.NoError(t, err)
+	response, found, err := reopened.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, found, "responses outlive the cache instance")
+	require.NotNil(t, response.Choices[0].Message.ToolCalls)
+	assert.Equal(t, "search", (*response.Choices[0].Message.ToolCalls)[0].Function.Name)
+
+	_, found, err = reopened.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	time.Sleep(60 * time.Millisecond)
+	_, found, err = reopened.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, found)
+	_, err = os.Stat(filepath.Join(dir, "a.json"))
+	assert.True(t, os.IsNotExist(err), "expired responses are removed")
+
+	_, err = server.NewFileLLMResponseCache("", time.Hour)
+	assert.EqualError(t, err, "llm cache directory is required for the file store")
+}
+
+func TestCachingLLMClient(t *testing.T) {
+	tools := []sdk.ChatCompletionTool{{Type: "function", Function: sdk.FunctionObject{Name: "search"}}}
+	messages := []sdk.Message{{Role: sdk.User, Content: "Hi"}}
+	temperature := 0.0
+
+	tests := []struct {
+		name          string
+		messages      []sdk.Message
+		tools         []sdk.ChatCompletionTool
+		override      *server.LLMRequestOptions
+		expectedCalls int
+	}{
+		{
+			name:          "identical request is answered from the cache",
+			messages:      messages,
+			expectedCalls: 1,
+		},
+		{
+			name:          "different messages miss",
+			messages:      []sdk.Message{{Role: sdk.User, Content: "Hello"}},
+			expectedCalls: 2,
+		},
+		{
+			name:          "different tools miss",
+			messages:      messages,
+			tools:         tools,
+			expectedCalls: 2,
+		},
+		{
+			name:          "different sampling parameters miss",
+			messages:      messages,
+			override:      &server.LLMRequestOptions{Temperature: &temperature},
+			expectedCalls: 2,
+		},
+		{
+			name:          "different model misses",
+			messages:      messages,
+			override:      &server.LLMRequestOptions{Model: "openai/gpt-4o-mini"},
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmClient := &mocks.FakeLLMClient{}
+			llmClient.CreateChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+				return withUsage(completionResponse("Hello"), 10, 5), nil
+			}
+			client := server.NewCachingLLMClient(llmClient, server.NewMemoryLLMResponseCache(10, time.Hour), zap.NewNop())
+
+			first, err := client.CreateChatCompletion(context.Background(), messages)
+			require.NoError(t, err)
+			require.NotNil(t, first.Usage)
+
+			ctx := server.WithLLMRequestOptions(context.Background(), tt.override)
+			second, err := client.CreateChatCompletion(ctx, tt.messages, tt.tools...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCalls, llmClient.CreateChatCompletionCallCount())
+			assert.Equal(t, "Hello", second.Choices[0].Message.Content)
+			if tt.expectedCalls == 1 {
+				assert.Nil(t, second.Usage, "cached responses use no tokens")
+			}
+		})
+	}
+}
+
+func TestCachingLLMClient_ReplaysStream(t *testing.T) {
+	response := newToolCallResponse("search", "lookup")
+	response.Model = "gpt-4o"
+	response.Choices[0].Message.Content = "Let me check."
+	response.Choices[0].FinishReason = sdk.ToolCalls
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturns(response, nil)
+	client := server.NewCachingLLMClient(llmClient, server.NewMemoryLLMResponseCache(10, time.Hour), zap.NewNop())
+
+	messages := []sdk.Message{{Role: sdk.User, Content: "Find it"}}
+	_, err := client.CreateChatCompletion(context.Background(), messages)
+	require.NoError(t, err)
+
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(context.Background(), messages)
+
+	var content, finishReason string
+	var toolCalls []string
+	for chunk := range streamResponses {
+		assert.Equal(t, "gpt-4o", chunk.Model)
+		choice := chunk.Choices[0]
+		content += choice.Delta.Content
+		for _, toolCall := range choice.Delta.ToolCalls {
+			toolCalls = append(toolCalls, toolCall.ID+":"+toolCall.Function.Name+":"+toolCall.Function.Arguments)
+		}
+		if choice.FinishReason != "" {
+			finishReason = choice.FinishReason
+		}
+	}
+	require.NoError(t, <-streamErrors)
+
+	assert.Equal(t, "Let me check.", content)
+	assert.Equal(t, []string{"call-0:search:{}", "call-1:lookup:{}"}, toolCalls)
+	assert.Equal(t, "tool_calls", finishReason)
+	assert.Equal(t, 0, llmClient.CreateStreamingChatCompletionCallCount())
+}
+
+func TestCachingLLMClient_StoresStream(t *testing.T) {
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateStreamingChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+		return streamOf(
+			streamChunk(sdk.ChatCompletionStreamResponseDelta{Role: "assistant", Content: "Hel"}, ""),
+			streamChunk(sdk.ChatCompletionStreamResponseDelta{Content: "lo"}, ""),
+			streamChunk(sdk.ChatCompletionStreamResponseDelta{}, "stop"),
+		)
+	}
+	client := server.NewCachingLLMClient(llmClient, server.NewMemoryLLMResponseCache(10, time.Hour), zap.NewNop())
+
+	messages := []sdk.Message{{Role: sdk.User, Content: "Hi"}}
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(context.Background(), messages)
+	for range streamResponses {
+	}
+	require.NoError(t, <-streamErrors)
+
+	response, err := client.CreateChatCompletion(context.Background(), messages)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, llmClient.CreateChatCompletionCallCount(), "the streamed response was cached")
+	assert.Equal(t, "Hello", response.Choices[0].Message.Content)
+	assert.Equal(t, sdk.Stop, response.Choices[0].FinishReason)
+	assert.Equal(t, "gpt-4o", response.Model)
+}
+
+func TestNewLLMClient_Cache(t *testing.T) {
+	cfg := &config.AgentConfig{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		Cache:    config.LLMCacheConfig{Enable: true, Store: "memory", MaxEntries: 10, TTL: time.Hour},
+	}
+	client, err := server.NewLLMClient(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &server.CachingLLMClient{}, client)
+
+	cfg.Cache.Store = "file"
+	_, err = server.NewLLMClient(cfg, zap.NewNop())
+	assert.EqualError(t, err, "failed to create llm response cache: llm cache directory is required for the file store")
+
+	cfg.Cache.Store = "redis"
+	_, err = server.NewLLMClient(cfg, zap.NewNop())
+	assert.EqualError(t, err, "failed to create llm response cache: unsupported llm cache store: redis")
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+// Modes of a cassette
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// CassetteRequest is a chat completion request recorded in a cassette
+type CassetteRequest struct {
+	Options  *LLMRequestOptions       `json:"options,omitempty"`
+	Messages []sdk.Message            `json:"messages"`
+	Tools    []sdk.ChatCompletionTool `json:"tools,omitempty"`
+	Stream   bool                     `json:"stream,omitempty"`
+}
+
+// CassetteInteraction is a request recorded in a cassette with its response, the chunks of a
+// streaming request or the error the request failed with
+type CassetteInteraction struct {
+	Request  CassetteRequest                           `json:"request"`
+	Response *sdk.CreateChatCompletionResponse         `json:"response,omitempty"`
+	Chunks   []*sdk.CreateChatCompletionStreamResponse `json:"chunks,omitempty"`
+	Error    string                                    `json:"error,omitempty"`
+}
+
+// Cassette is the file of the interactions recorded by a CassetteLLMClient
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+var _ LLMClient = (*CassetteLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*CassetteLLMClient)(nil)
+var _ instrumentedLLMClient = (*CassetteLLMClient)(nil)
+
+// CassetteLLMClient records the chat completions of a live LLM client to a cassette file and
+// replays them later without an LLM, so that agent tests run offline and deterministically.
+// Replay is strict: each recorded interaction answers one identical request, and requests
+// matching no remaining interaction fail with an UnmatchedLLMRequestError.
+type CassetteLLMClient struct {
+	path   string
+	mode   string
+	client LLMClient
+	logger *zap.Logger
+
+	mu           sync.Mutex
+	interactions []CassetteInteraction
+	keys         []string
+	played       []bool
+}
+
+// NewCassetteRecorder creates a cassette recording the completions of the client to path,
+// the cassette file is rewritten after each completion
+func NewCassetteRecorder(client LLMClient, path string, logger *zap.Logger) (*CassetteLLMClient, error) {
+	if client == nil {
+		return nil, fmt.Errorf("llm client to record is required")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("cassette path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	return &CassetteLLMClient{
+		path:   path,
+		mode:   CassetteModeRecord,
+		client: client,
+		logger: logger,
+	}, nil
+}
+
+// NewCassettePlayer creates a cassette replaying the completions recorded in path
+func NewCassettePlayer(path string, logger *zap.Logger) (*CassetteLLMClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+
+	c := &CassetteLLMClient{
+		path:         path,
+		mode:         CassetteModeReplay,
+		logger:       logger,
+		interactions: cassette.Interactions,
+		keys:         make([]string, len(cassette.Interactions)),
+		played:       make([]bool, len(cassette.Interactions)),
+	}
+	for i, interaction := range cassette.Interactions {
+		if c.keys[i], err = interaction.Request.key(); err != nil {
+			return nil, fmt.Errorf("failed to decode interaction %d of cassette %s: %w", i, path, err)
+		}
+	}
+	return c, nil
+}
+
+// Mode returns whether the cassette records or replays
+func (c *CassetteLLMClient) Mode() string {
+	return c.mode
+}
+
+// Remaining returns the number of recorded interactions that have not been replayed
+func (c *CassetteLLMClient) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := 0
+	for _, played := range c.played {
+		if !played {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion
+func (c *CassetteLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	request := newCassetteRequest(ctx, messages, tools, false)
+
+	if c.mode == CassetteModeReplay {
+		interaction, err := c.play(request)
+		if err != nil {
+			return nil, err
+		}
+		if interaction.Error != "" {
+			return nil, errors.New(interaction.Error)
+		}
+		return interaction.Response, nil
+	}
+
+	response, err := c.client.CreateChatCompletion(ctx, messages, tools...)
+	interaction := CassetteInteraction{Request: request, Response: response}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+	c.record(interaction)
+	return response, err
+}
+
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion
+func (c *CassetteLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	request := newCassetteRequest(ctx, messages, tools, true)
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+	errorChan := make(chan error, 1)
+
+	if c.mode == CassetteModeReplay {
+		go func() {
+			defer close(responseChan)
+			defer close(errorChan)
+
+			interaction, err := c.play(request)
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			for _, chunk := range interaction.Chunks {
+				select {
+				case responseChan <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if interaction.Error != "" {
+				errorChan <- errors.New(interaction.Error)
+			}
+		}()
+		return responseChan, errorChan
+	}
+
+	upstreamResponses, upstreamErrors := c.client.CreateStreamingChatCompletion(ctx, messages, tools...)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		interaction := CassetteInteraction{Request: request}
+		for response := range upstreamResponses {
+			if response != nil {
+				chunk := *response
+				interaction.Chunks = append(interaction.Chunks, &chunk)
+			}
+
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		err := <-upstreamErrors
+		if err != nil {
+			interaction.Error = err.Error()
+		}
+		c.record(interaction)
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// ResolveRequestOptions implements LLMRequestOptionsResolver for the recorded client
+func (c *CassetteLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	if resolver, ok := c.client.(LLMRequestOptionsResolver); ok {
+		return resolver.ResolveRequestOptions(ctx)
+	}
+
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	return LLMRequestOptions{}.merge(override)
+}
+
+// SetTelemetry passes the telemetry on to the recorded client
+func (c *CassetteLLMClient) SetTelemetry(telemetry otel.OpenTelemetry) {
+	if instrumented, ok := c.client.(instrumentedLLMClient); ok {
+		instrumented.SetTelemetry(telemetry)
+	}
+}
+
+// play returns the first interaction not yet replayed that was recorded for an identical request
+func (c *CassetteLLMClient) play(request CassetteRequest) (CassetteInteraction, error) {
+	key, err := request.key()
+	if err != nil {
+		return CassetteInteraction{}, fmt.Errorf("failed to encode llm request: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, recorded := range c.keys {
+		if c.played[i] || recorded != key {
+			continue
+		}
+		c.played[i] = true
+		c.logger.Debug("replaying llm interaction", zap.String("cassette", c.path), zap.Int("interaction", i))
+		return c.interactions[i], nil
+	}
+	return CassetteInteraction{}, NewUnmatchedLLMRequestError(c.path, request.summary())
+}
+
+// record appends the interaction and rewrites the cassette file
+func (c *CassetteLLMClient) record(interaction CassetteInteraction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+	data, err := json.MarshalIndent(Cassette{Interactions: c.interactions}, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(c.path, data)
+	}
+	if err != nil {
+		c.logger.Error("failed to write cassette", zap.String("cassette", c.path), zap.Error(err))
+	}
+}
+
+// newCassetteRequest returns the request of a completion with the options override of the context
+func newCassetteRequest(ctx context.Context, messages []sdk.Message, tools []sdk.ChatCompletionTool, stream bool) CassetteRequest {
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	return CassetteRequest{
+		Options:  override,
+		Messages: append([]sdk.Message(nil), messages...),
+		Tools:    tools,
+		Stream:   stream,
+	}
+}
+
+// key returns the encoding two identical requests share
+func (r CassetteRequest) key() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// summary describes the request by its last message for error messages
+func (r CassetteRequest) summary() string {
+	if len(r.Messages) == 0 {
+		return "without messages"
+	}
+
+	last := r.Messages[len(r.Messages)-1]
+	content := last.Content
+	if len(content) > 80 {
+		content = content[:80] + "..."
+	}
+	return fmt.Sprintf("%s: %q (%d messages, %d tools)", last.Role, content, len(r.Messages), len(r.Tools))
+}
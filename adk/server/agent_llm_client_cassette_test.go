@@ -0,0 +1,200 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordCassette records a completion, a streaming completion and a failed completion to a cassette file
+func recordCassette(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "cassettes", "greeting.json")
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, completionResponse("Hello"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, nil, errors.New("overloaded"))
+	llmClient.CreateStreamingChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+		return streamOf(
+			streamChunk(sdk.ChatCompletionStreamResponseDelta{Role: "assistant", Content: "Bon"}, ""),
+			streamChunk(sdk.ChatCompletionStreamResponseDelta{Content: "jour"}, "stop"),
+		)
+	}
+
+	recorder, err := server.NewCassetteRecorder(llmClient, path, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = recorder.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.NoError(t, err)
+
+	streamResponses, streamErrors := recorder.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Salut"}})
+	for range streamResponses {
+	}
+	require.NoError(t, <-streamErrors)
+
+	temperature := 0.0
+	ctx := server.WithLLMRequestOptions(context.Background(), &server.LLMRequestOptions{Temperature: &temperature})
+	_, err = recorder.CreateChatCompletion(ctx, []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.EqualError(t, err, "overloaded")
+
+	return path
+}
+
+func TestCassetteLLMClient_RecordAndReplay(t *testing.T) {
+	path := recordCassette(t)
+
+	player, err := server.NewCassettePlayer(path, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, server.CassetteModeReplay, player.Mode())
+	assert.Equal(t, 3, player.Remaining())
+
+	streamResponses, streamErrors := player.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Salut"}})
+	var streamed, finishReason string
+	for chunk := range streamResponses {
+		streamed += chunk.Choices[0].Delta.Content
+		finishReason = chunk.Choices[0].FinishReason
+	}
+	require.NoError(t, <-streamErrors)
+	assert.Equal(t, "Bonjour", streamed)
+	assert.Equal(t, "stop", finishReason)
+
+	response, err := player.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", response.Choices[0].Message.Content)
+
+	temperature := 0.0
+	ctx := server.WithLLMRequestOptions(context.Background(), &server.LLMRequestOptions{Temperature: &temperature})
+	_, err = player.CreateChatCompletion(ctx, []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	assert.EqualError(t, err, "overloaded", "recorded errors are replayed")
+
+	assert.Equal(t, 0, player.Remaining())
+}
+
+func TestCassetteLLMClient_ReplayIsStrict(t *testing.T) {
+	temperature := 1.0
+
+	tests := []struct {
+		name     string
+		messages []sdk.Message
+		override *server.LLMRequestOptions
+		stream   bool
+		replays  int
+	}{
+		{
+			name:     "different messages",
+			messages: []sdk.Message{{Role: sdk.User, Content: "Hello"}},
+		},
+		{
+			name:     "different options",
+			messages: []sdk.Message{{Role: sdk.User, Content: "Hi"}},
+			override: &server.LLMRequestOptions{Temperature: &temperature},
+		},
+		{
+			name:     "streaming request recorded without streaming",
+			messages: []sdk.Message{{Role: sdk.User, Content: "Hi"}},
+			stream:   true,
+		},
+		{
+			name:     "request replayed more often than recorded",
+			messages: []sdk.Message{{Role: sdk.User, Content: "Hi"}},
+			replays:  1,
+		},
+	}
+
+	path := recordCassette(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, err := server.NewCassettePlayer(path, zap.NewNop())
+			require.NoError(t, err)
+
+			ctx := server.WithLLMRequestOptions(context.Background(), tt.override)
+			for range tt.replays {
+				_, err := player.CreateChatCompletion(ctx, tt.messages)
+				require.NoError(t, err)
+			}
+
+			if tt.stream {
+				streamResponses, streamErrors := player.CreateStreamingChatCompletion(ctx, tt.messages)
+				for range streamResponses {
+				}
+				err = <-streamErrors
+			} else {
+				_, err = player.CreateChatCompletion(ctx, tt.messages)
+			}
+
+			var unmatchedErr *server.UnmatchedLLMRequestError
+			require.ErrorAs(t, err, &unmatchedErr)
+			assert.Equal(t, path, unmatchedErr.Cassette)
+		})
+	}
+}
+
+func TestDefaultOpenAICompatibleAgent_ReplaysCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lookup.json")
+	newToolBox := func() server.ToolBox {
+		toolBox := server.NewDefaultToolBox()
+		toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+			func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return "record 7", nil
+			}))
+		return toolBox
+	}
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, newToolCallResponse("lookup"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, completionResponse("Found record 7."), nil)
+	recorder, err := server.NewCassetteRecorder(llmClient, path, zap.NewNop())
+	require.NoError(t, err)
+
+	recording, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(recorder).WithToolBox(newToolBox()).Build()
+	require.NoError(t, err)
+	recorded, err := recording.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Find record 7"))
+	require.NoError(t, err)
+
+	replaying, err := server.NewAgentBuilder(zap.NewNop()).
+		WithConfig(&config.AgentConfig{
+			Provider: "openai",
+			Model:    "gpt-4o",
+			Cassette: config.LLMCassetteConfig{Mode: server.CassetteModeReplay, Path: path},
+		}).
+		WithToolBox(newToolBox()).
+		Build()
+	require.NoError(t, err)
+	replayed, err := replaying.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Find record 7"))
+	require.NoError(t, err)
+
+	require.Equal(t, adk.TaskStateCompleted, replayed.Status.State)
+	assert.Equal(t, "Found record 7.", textOf(replayed.Status.Message))
+	assert.Equal(t, textOf(recorded.Status.Message), textOf(replayed.Status.Message))
+}
+
+func TestNewLLMClient_Cassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.json")
+	cfg := &config.AgentConfig{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		Cassette: config.LLMCassetteConfig{Mode: server.CassetteModeRecord, Path: path},
+	}
+
+	client, err := server.NewLLMClient(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.IsType(t, &server.CassetteLLMClient{}, client)
+	assert.Equal(t, server.CassetteModeRecord, client.(*server.CassetteLLMClient).Mode())
+
+	cfg.Cassette.Mode = server.CassetteModeReplay
+	_, err = server.NewLLMClient(cfg, zap.NewNop())
+	assert.ErrorContains(t, err, "failed to read cassette "+path)
+
+	cfg.Cassette.Mode = "rewind"
+	_, err = server.NewLLMClient(cfg, zap.NewNop())
+	assert.EqualError(t, err, "unsupported cassette mode: rewind")
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+// States of a circuit breaker
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half-open"
+)
+
+// CircuitBreakerStatus reports the state of the circuit breaker of an LLM provider
+type CircuitBreakerStatus struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
+	InFlight int    `json:"in_flight"`
+}
+
+// CircuitBreaker guards the requests to an LLM provider. It opens after the configured number of
+// consecutive timeouts, connection errors, rate limits or server errors, fails fast while open and lets probe requests
+// through once the open timeout passed, closing again on their success. It also caps the requests
+// in flight to the provider, further requests wait for a slot.
+type CircuitBreaker struct {
+	provider string
+	cfg      config.CircuitBreakerConfig
+	logger   *zap.Logger
+	now      func() time.Time
+	slots    chan struct{}
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probes   int
+	inFlight int
+}
+
+// NewCircuitBreaker creates the circuit breaker of an LLM provider
+func NewCircuitBreaker(provider string, cfg config.CircuitBreakerConfig, logger *zap.Logger) *CircuitBreaker {
+	breaker := &CircuitBreaker{
+		provider: provider,
+		cfg:      cfg,
+		logger:   logger,
+		now:      time.Now,
+		state:    CircuitStateClosed,
+	}
+	if cfg.MaxConcurrentRequests > 0 {
+		breaker.slots = make(chan struct{}, cfg.MaxConcurrentRequests)
+	}
+	return breaker
+}
+
+// Status returns the state of the circuit breaker
+func (b *CircuitBreaker) Status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == CircuitStateOpen && !b.now().Before(b.openedAt.Add(b.cfg.OpenTimeout)) {
+		state = CircuitStateHalfOpen
+	}
+	return CircuitBreakerStatus{
+		Provider: b.provider,
+		State:    state,
+		Failures: b.failures,
+		InFlight: b.inFlight,
+	}
+}
+
+// acquire waits for a slot and admits a request, the returned function reports its outcome
+func (b *CircuitBreaker) acquire(ctx context.Context) (func(ctx context.Context, err error), error) {
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	probe, err := b.admit()
+	if err != nil {
+		b.releaseSlot()
+		return nil, err
+	}
+
+	return func(ctx context.Context, err error) {
+		b.record(ctx, probe, err)
+		b.releaseSlot()
+	}, nil
+}
+
+// admit lets the request through unless the circuit is open, it reports whether the request is a probe
+func (b *CircuitBreaker) admit() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cfg.FailureThreshold <= 0 {
+		b.inFlight++
+		return false, nil
+	}
+
+	if b.state == CircuitStateOpen {
+		retryAt := b.openedAt.Add(b.cfg.OpenTimeout)
+		if b.now().Before(retryAt) {
+			return false, NewCircuitOpenError(b.provider, retryAt)
+		}
+		b.state = CircuitStateHalfOpen
+		b.probes = 0
+		b.logger.Info("llm circuit breaker half-open", zap.String("provider", b.provider))
+	}
+
+	if b.state == CircuitStateHalfOpen {
+		if b.probes >= max(b.cfg.HalfOpenMaxRequests, 1) {
+			return false, NewCircuitOpenError(b.provider, b.now())
+		}
+		b.probes++
+		b.inFlight++
+		return true, nil
+	}
+
+	b.inFlight++
+	return false, nil
+}
+
+// record updates the circuit with the outcome of a request. Only timeouts, connection errors, rate limits
+// and server errors count as failures, other errors show that the provider is answering.
+func (b *CircuitBreaker) record(ctx context.Context, probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight--
+	if probe {
+		b.probes--
+	}
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+
+	if err == nil || !isRetryableLLMError(ctx, err) {
+		if ctx.Err() != nil {
+			return
+		}
+		if b.state != CircuitStateClosed {
+			b.logger.Info("llm circuit breaker closed", zap.String("provider", b.provider))
+		}
+		b.state = CircuitStateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitStateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		if b.state != CircuitStateOpen {
+			b.logger.Warn("llm circuit breaker opened",
+				zap.String("provider", b.provider),
+				zap.Int("failures", b.failures),
+				zap.Duration("open_timeout", b.cfg.OpenTimeout),
+				zap.Error(err))
+		}
+		b.state = CircuitStateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// releaseSlot frees the slot of a request
+func (b *CircuitBreaker) releaseSlot() {
+	if b.slots != nil {
+		<-b.slots
+	}
+}
+
+var _ LLMClient = (*CircuitBreakerLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*CircuitBreakerLLMClient)(nil)
+var _ instrumentedLLMClient = (*CircuitBreakerLLMClient)(nil)
+
+// CircuitBreakerLLMClient sends the completions of an LLM client through the circuit breaker of its provider
+type CircuitBreakerLLMClient struct {
+	client  LLMClient
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerLLMClient wraps an LLM client with the circuit breaker of its provider
+func NewCircuitBreakerLLMClient(client LLMClient, breaker *CircuitBreaker) *CircuitBreakerLLMClient {
+	return &CircuitBreakerLLMClient{
+		client:  client,
+		breaker: breaker,
+	}
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion
+func (c *CircuitBreakerLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	done, err := c.breaker.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.CreateChatCompletion(ctx, messages, tools...)
+	done(ctx, err)
+	return response, err
+}
+
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion,
+// the request holds its slot until the stream ends
+func (c *CircuitBreakerLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+	errorChan := make(chan error, 1)
+
+	done, err := c.breaker.acquire(ctx)
+	if err != nil {
+		errorChan <- err
+		close(responseChan)
+		close(errorChan)
+		return responseChan, errorChan
+	}
+
+	upstreamResponses, upstreamErrors := c.client.CreateStreamingChatCompletion(ctx, messages, tools...)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		for response := range upstreamResponses {
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				done(ctx, ctx.Err())
+				return
+			}
+		}
+
+		err := <-upstreamErrors
+		done(ctx, err)
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// ResolveRequestOptions implements LLMRequestOptionsResolver
+func (c *CircuitBreakerLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	if resolver, ok := c.client.(LLMRequestOptionsResolver); ok {
+		return resolver.ResolveRequestOptions(ctx)
+	}
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	return LLMRequestOptions{}.merge(override)
+}
+
+// SetTelemetry passes the telemetry on to the wrapped client
+func (c *CircuitBreakerLLMClient) SetTelemetry(telemetry otel.OpenTelemetry) {
+	if instrumented, ok := c.client.(instrumentedLLMClient); ok {
+		instrumented.SetTelemetry(telemetry)
+	}
+}
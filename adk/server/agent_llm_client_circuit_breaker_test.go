@@ -0,0 +1,263 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCircuitBreakerLLMClient(t *testing.T) {
+	unavailable := server.NewLLMAPIError(http.StatusServiceUnavailable, "overloaded", "")
+	badRequest := server.NewLLMAPIError(http.StatusBadRequest, "invalid model", "")
+
+	tests := []struct {
+		name          string
+		errors        []error
+		expectedState string
+		expectedCalls int
+	}{
+		{
+			name:          "stays closed below the threshold",
+			errors:        []error{unavailable, nil, unavailable},
+			expectedState: server.CircuitStateClosed,
+			expectedCalls: 4,
+		},
+		{
+			name:          "opens after consecutive failures and fails fast",
+			errors:        []error{unavailable, unavailable},
+			expectedState: server.CircuitStateOpen,
+			expectedCalls: 2,
+		},
+		{
+			name:          "client errors do not open the circuit",
+			errors:        []error{badRequest, badRequest, badRequest},
+			expectedState: server.CircuitStateClosed,
+			expectedCalls: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmClient := &mocks.FakeLLMClient{}
+			for i, err := range tt.errors {
+				if err != nil {
+					llmClient.CreateChatCompletionReturnsOnCall(i, nil, err)
+				} else {
+					llmClient.CreateChatCompletionReturnsOnCall(i, completionResponse("ok"), nil)
+				}
+			}
+			llmClient.CreateChatCompletionReturnsOnCall(len(tt.errors), completionResponse("ok"), nil)
+
+			breaker := server.NewCircuitBreaker("openai", config.CircuitBreakerConfig{
+				FailureThreshold: 2,
+				OpenTimeout:      time.Minute,
+			}, zap.NewNop())
+			client := server.NewCircuitBreakerLLMClient(llmClient, breaker)
+
+			for range tt.errors {
+				_, _ = client.CreateChatCompletion(context.Background(), nil)
+			}
+			_, err := client.CreateChatCompletion(context.Background(), nil)
+
+			assert.Equal(t, tt.expectedCalls, llmClient.CreateChatCompletionCallCount())
+			assert.Equal(t, tt.expectedState, breaker.Status().State)
+			if tt.expectedState == server.CircuitStateOpen {
+				var circuitErr *server.CircuitOpenError
+				require.ErrorAs(t, err, &circuitErr)
+				assert.Equal(t, "openai", circuitErr.Provider)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCircuitBreakerLLMClient_OpensOnConnectionRefused(t *testing.T) {
+	llmClient, err := server.NewOpenAICompatibleLLMClient(&config.AgentConfig{
+		Provider: "openai",
+		Model:    "gpt-4o",
+		BaseURL:  closedPortURL(t),
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	breaker := server.NewCircuitBreaker("openai", config.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	}, zap.NewNop())
+	client := server.NewCircuitBreakerLLMClient(llmClient, breaker)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+	}
+	assert.Equal(t, server.CircuitStateOpen, breaker.Status().State)
+
+	_, err = client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	var circuitErr *server.CircuitOpenError
+	assert.ErrorAs(t, err, &circuitErr, "requests to a provider that is down fail fast")
+}
+
+func TestCircuitBreakerLLMClient_HalfOpenProbe(t *testing.T) {
+	unavailable := server.NewLLMAPIError(http.StatusServiceUnavailable, "overloaded", "")
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, nil, unavailable)
+	llmClient.CreateChatCompletionReturnsOnCall(1, nil, unavailable)
+	llmClient.CreateChatCompletionReturnsOnCall(2, completionResponse("ok"), nil)
+
+	breaker := server.NewCircuitBreaker("openai", config.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      20 * time.Millisecond,
+	}, zap.NewNop())
+	client := server.NewCircuitBreakerLLMClient(llmClient, breaker)
+
+	_, err := client.CreateChatCompletion(context.Background(), nil)
+	require.Error(t, err)
+	require.Equal(t, server.CircuitStateOpen, breaker.Status().State)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, server.CircuitStateHalfOpen, breaker.Status().State)
+
+	_, err = client.CreateChatCompletion(context.Background(), nil)
+	require.Error(t, err)
+	assert.Equal(t, server.CircuitStateOpen, breaker.Status().State, "a failed probe opens the circuit again")
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = client.CreateChatCompletion(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, server.CircuitStateClosed, breaker.Status().State, "a successful probe closes the circuit")
+	assert.Equal(t, 3, llmClient.CreateChatCompletionCallCount())
+}
+
+func TestCircuitBreakerLLMClient_LimitsConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+		<-release
+		return completionResponse("ok"), nil
+	}
+
+	breaker := server.NewCircuitBreaker("openai", config.CircuitBreakerConfig{MaxConcurrentRequests: 1}, zap.NewNop())
+	client := server.NewCircuitBreakerLLMClient(llmClient, breaker)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.CreateChatCompletion(context.Background(), nil)
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return breaker.Status().InFlight == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.CreateChatCompletion(ctx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the second request waits for a slot")
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, llmClient.CreateChatCompletionCallCount())
+	assert.Equal(t, 0, breaker.Status().InFlight)
+}
+
+func TestCompositeLLMClient_FallsBackOnOpenCircuit(t *testing.T) {
+	gateway, requested := newRoutingGateway(t, map[string]int{"openai/gpt-4o": http.StatusServiceUnavailable})
+
+	agentConfig := newCompositeConfig(gateway.URL)
+	agentConfig.MaxRetries = 1
+	cfg := &config.Config{
+		AgentConfig: *agentConfig,
+		CircuitBreakerConfig: config.CircuitBreakerConfig{
+			Enable:           true,
+			FailureThreshold: 1,
+			OpenTimeout:      time.Minute,
+		},
+	}
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithConfig(&cfg.AgentConfig).Build()
+	require.NoError(t, err)
+	a2aServer := server.NewA2AServerWithAgent(cfg, zap.NewNop(), nil, agent)
+
+	for range 2 {
+		task, err := agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Hi"))
+		require.NoError(t, err)
+		assert.Equal(t, "anthropic/claude-3-5-haiku", task.Metadata[server.MetadataLLMModelKey])
+	}
+
+	assert.Equal(t, []string{"openai/gpt-4o", "openai/gpt-4o", "anthropic/claude-3-5-haiku", "anthropic/claude-3-5-haiku"}, *requested,
+		"the open circuit skips the primary model")
+
+	statuses := a2aServer.CircuitBreakerStatuses()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, server.CircuitBreakerStatus{Provider: "openai", State: server.CircuitStateOpen, Failures: 1}, statuses[2])
+}
+
+func TestA2AServer_HealthReportsOpenCircuit(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturns(nil, server.NewLLMAPIError(http.StatusBadGateway, "bad gateway", ""))
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithConfig(&config.AgentConfig{Provider: "openai", Model: "gpt-4o"}).
+		WithLLMClient(llmClient).
+		Build()
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		CircuitBreakerConfig: config.CircuitBreakerConfig{Enable: true, FailureThreshold: 1, OpenTimeout: time.Minute},
+		ServerConfig:         config.ServerConfig{Port: fmt.Sprint(port), DisableStrictMode: true},
+		QueueConfig:          config.QueueConfig{MaxSize: 10, CleanupInterval: time.Minute},
+	}
+	a2aServer := server.NewA2AServerWithAgent(cfg, zap.NewNop(), nil, agent)
+	a2aServer.SetAgentCard(adk.AgentCard{Name: "health-agent", Version: "1.0.0", URL: "http://localhost"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = a2aServer.Start(ctx)
+	}()
+	defer func() {
+		_ = a2aServer.Stop(context.Background())
+	}()
+
+	health := func() map[string]interface{} {
+		var body map[string]interface{}
+		require.Eventually(t, func() bool {
+			response, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/health", port))
+			if err != nil {
+				return false
+			}
+			defer func() {
+				_ = response.Body.Close()
+			}()
+			return response.StatusCode == http.StatusOK && json.NewDecoder(response.Body).Decode(&body) == nil
+		}, 2*time.Second, 10*time.Millisecond)
+		return body
+	}
+
+	assert.Equal(t, adk.HealthStatusHealthy, health()["status"])
+
+	_, err = agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Hi"))
+	require.NoError(t, err)
+
+	body := health()
+	assert.Equal(t, adk.HealthStatusDegraded, body["status"])
+	breakers := body["circuit_breakers"].([]interface{})
+	require.Len(t, breakers, 1)
+	assert.Equal(t, "open", breakers[0].(map[string]interface{})["state"])
+}
@@ -0,0 +1,410 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+var _ LLMClient = (*CompositeLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*CompositeLLMClient)(nil)
+
+// LLMRoutingRule sends the completions it matches to its model, given as a provider/model pair
+type LLMRoutingRule struct {
+	Name  string
+	Model string
+	Match func(messages []sdk.Message, tools []sdk.ChatCompletionTool) bool
+}
+
+// CompositeLLMClient routes completions to a model by its rules and falls back through an ordered
+// list of models when a model fails with a timeout, a connection error, a rate limit or a server error.
+// Streams only fall back before their first chunk. The model that answered is reported
+// as a provider/model pair in the Model of the responses.
+type CompositeLLMClient struct {
+	config    *config.AgentConfig
+	logger    *zap.Logger
+	primary   string
+	fallbacks []string
+	rules     []LLMRoutingRule
+
+	mu        sync.Mutex
+	clients   map[string]LLMClient
+	telemetry otel.OpenTelemetry
+	wrap      func(provider string, client LLMClient) LLMClient
+}
+
+// NewLLMClient creates the LLM client of the configuration, a CompositeLLMClient when
+// fallback models or routing rules are configured and an OpenAICompatibleLLMClient otherwise.
+// The client is wrapped with a CachingLLMClient when the response cache is enabled and with
+// a recording CassetteLLMClient in record mode, in replay mode the cassette answers alone.
+func NewLLMClient(cfg *config.AgentConfig, logger *zap.Logger) (LLMClient, error) {
+	if cfg != nil && cfg.Cassette.Mode == CassetteModeReplay {
+		return NewCassettePlayer(cfg.Cassette.Path, logger)
+	}
+
+	client, err := newLLMClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Cassette.Mode {
+	case "":
+		return client, nil
+	case CassetteModeRecord:
+		return NewCassetteRecorder(client, cfg.Cassette.Path, logger)
+	default:
+		return nil, fmt.Errorf("unsupported cassette mode: %s", cfg.Cassette.Mode)
+	}
+}
+
+// newLLMClient creates the LLM client of the configuration with its response cache
+func newLLMClient(cfg *config.AgentConfig, logger *zap.Logger) (LLMClient, error) {
+	var client LLMClient
+	var err error
+	if cfg != nil && (len(cfg.FallbackModels) > 0 || cfg.Routing.ToolsModel != "" || cfg.Routing.ShortPromptModel != "") {
+		client, err = NewCompositeLLMClient(cfg, logger)
+	} else {
+		client, err = NewOpenAICompatibleLLMClient(cfg, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.Cache.Enable {
+		return client, nil
+	}
+	cache, err := NewLLMResponseCache(cfg.Cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create llm response cache: %w", err)
+	}
+	return NewCachingLLMClient(client, cache, logger), nil
+}
+
+// NewCompositeLLMClient creates a composite LLM client answering with the configured model,
+// its routing rules and its fallback models
+func NewCompositeLLMClient(cfg *config.AgentConfig, logger *zap.Logger) (*CompositeLLMClient, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("llm provider client config is required")
+	}
+
+	c := &CompositeLLMClient{
+		config:  cfg,
+		logger:  logger,
+		primary: qualifyModelName(cfg.Model, cfg.Provider),
+		clients: make(map[string]LLMClient),
+	}
+	if _, err := c.client(c.primary); err != nil {
+		return nil, err
+	}
+
+	for _, model := range cfg.FallbackModels {
+		model = qualifyModelName(strings.TrimSpace(model), cfg.Provider)
+		if _, err := c.client(model); err != nil {
+			return nil, fmt.Errorf("invalid fallback model %s: %w", model, err)
+		}
+		c.fallbacks = append(c.fallbacks, model)
+	}
+
+	if cfg.Routing.ToolsModel != "" {
+		err := c.AddRoutingRule(LLMRoutingRule{
+			Name:  "tools",
+			Model: cfg.Routing.ToolsModel,
+			Match: func(messages []sdk.Message, tools []sdk.ChatCompletionTool) bool {
+				return len(tools) > 0
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Routing.ShortPromptModel != "" {
+		maxChars := cfg.Routing.ShortPromptMaxChars
+		err := c.AddRoutingRule(LLMRoutingRule{
+			Name:  "short-prompt",
+			Model: cfg.Routing.ShortPromptModel,
+			Match: func(messages []sdk.Message, tools []sdk.ChatCompletionTool) bool {
+				return promptLength(messages) <= maxChars
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// AddRoutingRule adds a routing rule, rules are matched in the order they were added
+func (c *CompositeLLMClient) AddRoutingRule(rule LLMRoutingRule) error {
+	if rule.Match == nil {
+		return fmt.Errorf("routing rule %s has no match function", rule.Name)
+	}
+
+	rule.Model = qualifyModelName(strings.TrimSpace(rule.Model), c.config.Provider)
+	if _, err := c.client(rule.Model); err != nil {
+		return fmt.Errorf("invalid model %s of routing rule %s: %w", rule.Model, rule.Name, err)
+	}
+	c.rules = append(c.rules, rule)
+	return nil
+}
+
+// ResolveRequestOptions implements LLMRequestOptionsResolver with the options of the model of the override,
+// the primary model without one. The options of the model that answered a completion are resolved
+// by overriding the model with it.
+func (c *CompositeLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	model := c.primary
+	if override, ok := LLMRequestOptionsFromContext(ctx); ok && override.Model != "" {
+		model = qualifyModelName(override.Model, c.config.Provider)
+	}
+
+	client, err := c.client(model)
+	if err != nil {
+		return LLMRequestOptions{}
+	}
+	resolver, ok := client.(LLMRequestOptionsResolver)
+	if !ok {
+		return LLMRequestOptions{}
+	}
+	return resolver.ResolveRequestOptions(ctx)
+}
+
+// SetTelemetry records the attempts of the requests to each model in the metrics
+func (c *CompositeLLMClient) SetTelemetry(telemetry otel.OpenTelemetry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.telemetry = telemetry
+	for _, client := range c.clients {
+		if instrumented, ok := client.(instrumentedLLMClient); ok {
+			instrumented.SetTelemetry(telemetry)
+		}
+	}
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion
+func (c *CompositeLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	models := c.route(ctx, messages, tools)
+	ctx = withoutModelOverride(ctx)
+
+	var lastErr error
+	for i, model := range models {
+		client, err := c.client(model)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			c.logger.Warn("falling back to next llm model", zap.String("model", model), zap.Error(lastErr))
+		}
+
+		response, err := client.CreateChatCompletion(ctx, messages, tools...)
+		if err == nil {
+			response.Model = model
+			return response, nil
+		}
+		if !isRetryableLLMError(ctx, err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("all llm models failed: %w", lastErr)
+}
+
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion
+func (c *CompositeLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	models := c.route(ctx, messages, tools)
+	ctx = withoutModelOverride(ctx)
+
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		var lastErr error
+		for i, model := range models {
+			client, err := c.client(model)
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			if i > 0 {
+				c.logger.Warn("falling back to next llm model", zap.String("model", model), zap.Error(lastErr))
+			}
+
+			upstreamResponses, upstreamErrors := client.CreateStreamingChatCompletion(ctx, messages, tools...)
+			started := false
+			for response := range upstreamResponses {
+				if response == nil {
+					continue
+				}
+				started = true
+				response.Model = model
+
+				select {
+				case responseChan <- response:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			err = <-upstreamErrors
+			if err == nil {
+				return
+			}
+			if started || !isRetryableLLMError(ctx, err) {
+				errorChan <- err
+				return
+			}
+			lastErr = err
+		}
+
+		errorChan <- fmt.Errorf("all llm models failed: %w", lastErr)
+	}()
+
+	return responseChan, errorChan
+}
+
+// route returns the models to try for the completion in order: the model of the override
+// or of the first matching rule, the primary model and the fallback models
+func (c *CompositeLLMClient) route(ctx context.Context, messages []sdk.Message, tools []sdk.ChatCompletionTool) []string {
+	first := c.primary
+	if override, ok := LLMRequestOptionsFromContext(ctx); ok && override.Model != "" {
+		first = qualifyModelName(override.Model, c.config.Provider)
+	} else {
+		for _, rule := range c.rules {
+			if rule.Match(messages, tools) {
+				first = rule.Model
+				break
+			}
+		}
+	}
+
+	models := []string{first}
+	for _, model := range append([]string{c.primary}, c.fallbacks...) {
+		if model != first {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// client returns the client of a provider/model pair, creating it on first use
+func (c *CompositeLLMClient) client(model string) (LLMClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[model]; ok {
+		return client, nil
+	}
+
+	provider, _, ok := strings.Cut(model, "/")
+	if !ok || provider == "" {
+		return nil, fmt.Errorf("model %s must be given as provider/model", model)
+	}
+
+	cfg := *c.config
+	cfg.Provider = provider
+	cfg.Model = model
+	client, err := NewOpenAICompatibleLLMClient(&cfg, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	if c.telemetry != nil {
+		client.SetTelemetry(c.telemetry)
+	}
+	var wrapped LLMClient = client
+	if c.wrap != nil {
+		wrapped = c.wrap(provider, client)
+	}
+	c.clients[model] = wrapped
+	return wrapped, nil
+}
+
+// wrapClients wraps the client of each model, such as with the circuit breaker of its provider
+func (c *CompositeLLMClient) wrapClients(wrap func(provider string, client LLMClient) LLMClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.wrap = wrap
+	for model, client := range c.clients {
+		provider, _, _ := strings.Cut(model, "/")
+		c.clients[model] = wrap(provider, client)
+	}
+}
+
+// withoutModelOverride removes the model from the override of the context, the composite client picks the model
+func withoutModelOverride(ctx context.Context) context.Context {
+	override, ok := LLMRequestOptionsFromContext(ctx)
+	if !ok || override.Model == "" {
+		return ctx
+	}
+	options := *override
+	options.Model = ""
+	return WithLLMRequestOptions(ctx, &options)
+}
+
+// isRetryableLLMError reports whether another model may succeed where a model failed with err:
+// on timeouts, transport errors, rate limits, server errors and open circuits, unless the request itself was cancelled
+func isRetryableLLMError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return true
+	}
+
+	var apiErr *LLMAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || isTransportError(err)
+}
+
+// isTransportError reports whether err is a failure to reach the provider or to read its response,
+// such as a refused or reset connection, rather than an answer of the provider
+func isTransportError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// qualifyModelName prefixes the model with the provider unless it is already a provider/model pair
+func qualifyModelName(model string, provider string) string {
+	if model == "" || strings.Contains(model, "/") {
+		return model
+	}
+	return strings.ToLower(provider) + "/" + model
+}
+
+// promptLength returns the number of characters of the messages
+func promptLength(messages []sdk.Message) int {
+	length := 0
+	for _, message := range messages {
+		length += len(message.Content)
+	}
+	return length
+}
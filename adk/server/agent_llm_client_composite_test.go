@@ -0,0 +1,302 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newRoutingGateway starts a gateway failing the models of failures with their status code
+// and recording the provider/model pairs it was asked for
+func newRoutingGateway(t *testing.T, failures map[string]int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var requested []string
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		model := r.URL.Query().Get("provider") + "/" + request["model"].(string)
+
+		mu.Lock()
+		requested = append(requested, model)
+		mu.Unlock()
+
+		if status, ok := failures[model]; ok {
+			w.WriteHeader(status)
+			_, _ = fmt.Fprintf(w, `{"error":"%s unavailable"}`, model)
+			return
+		}
+
+		if request["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "data: {\"model\":%q,\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n", request["model"])
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"model":%q,"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`, request["model"])
+	}))
+	t.Cleanup(gateway.Close)
+	return gateway, &requested
+}
+
+func newCompositeConfig(baseURL string) *config.AgentConfig {
+	return &config.AgentConfig{
+		Provider:            "openai",
+		Model:               "gpt-4o",
+		BaseURL:             baseURL,
+		FallbackModels:      []string{"anthropic/claude-3-5-haiku", "groq/llama-3.3-70b"},
+		RetryInitialBackoff: time.Millisecond,
+	}
+}
+
+func TestCompositeLLMClient_Fallback(t *testing.T) {
+	tests := []struct {
+		name              string
+		failures          map[string]int
+		expectedModel     string
+		expectedRequested []string
+		expectedError     string
+	}{
+		{
+			name:              "primary answers",
+			expectedModel:     "openai/gpt-4o",
+			expectedRequested: []string{"openai/gpt-4o"},
+		},
+		{
+			name:              "server error falls back",
+			failures:          map[string]int{"openai/gpt-4o": http.StatusServiceUnavailable},
+			expectedModel:     "anthropic/claude-3-5-haiku",
+			expectedRequested: []string{"openai/gpt-4o", "anthropic/claude-3-5-haiku"},
+		},
+		{
+			name: "rate limits fall back",
+			failures: map[string]int{
+				"openai/gpt-4o":              http.StatusTooManyRequests,
+				"anthropic/claude-3-5-haiku": http.StatusTooManyRequests,
+			},
+			expectedModel:     "groq/llama-3.3-70b",
+			expectedRequested: []string{"openai/gpt-4o", "anthropic/claude-3-5-haiku", "groq/llama-3.3-70b"},
+		},
+		{
+			name:              "client error does not fall back",
+			failures:          map[string]int{"openai/gpt-4o": http.StatusBadRequest},
+			expectedRequested: []string{"openai/gpt-4o"},
+			expectedError:     "API error: openai/gpt-4o unavailable (status code: 400)",
+		},
+		{
+			name: "all models fail",
+			failures: map[string]int{
+				"openai/gpt-4o":              http.StatusBadGateway,
+				"anthropic/claude-3-5-haiku": http.StatusInternalServerError,
+				"groq/llama-3.3-70b":         http.StatusServiceUnavailable,
+			},
+			expectedRequested: []string{"openai/gpt-4o", "anthropic/claude-3-5-haiku", "groq/llama-3.3-70b"},
+			expectedError:     "all llm models failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, requested := newRoutingGateway(t, tt.failures)
+
+			client, err := server.NewCompositeLLMClient(newCompositeConfig(gateway.URL), zap.NewNop())
+			require.NoError(t, err)
+
+			response, err := client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+			assert.Equal(t, tt.expectedRequested, *requested)
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedModel, response.Model)
+		})
+	}
+}
+
+func TestCompositeLLMClient_Routing(t *testing.T) {
+	tools := []sdk.ChatCompletionTool{{Type: "function", Function: sdk.FunctionObject{Name: "search"}}}
+
+	tests := []struct {
+		name          string
+		prompt        string
+		tools         []sdk.ChatCompletionTool
+		override      *server.LLMRequestOptions
+		expectedModel string
+	}{
+		{
+			name:          "tools go to the strong model",
+			prompt:        "Hi",
+			tools:         tools,
+			expectedModel: "openai/gpt-4.1",
+		},
+		{
+			name:          "short prompts go to the cheap model",
+			prompt:        "Hi",
+			expectedModel: "openai/gpt-4o-mini",
+		},
+		{
+			name:          "long prompts go to the primary model",
+			prompt:        strings.Repeat("a", 21),
+			expectedModel: "openai/gpt-4o",
+		},
+		{
+			name:          "requested model wins over the rules",
+			prompt:        "Hi",
+			override:      &server.LLMRequestOptions{Model: "anthropic/claude-3-5-sonnet"},
+			expectedModel: "anthropic/claude-3-5-sonnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, requested := newRoutingGateway(t, nil)
+
+			cfg := newCompositeConfig(gateway.URL)
+			cfg.Routing = config.LLMRoutingConfig{
+				ToolsModel:          "openai/gpt-4.1",
+				ShortPromptModel:    "gpt-4o-mini",
+				ShortPromptMaxChars: 20,
+			}
+			client, err := server.NewLLMClient(cfg, zap.NewNop())
+			require.NoError(t, err)
+			require.IsType(t, &server.CompositeLLMClient{}, client)
+
+			ctx := server.WithLLMRequestOptions(context.Background(), tt.override)
+			messages := []sdk.Message{{Role: sdk.User, Content: tt.prompt}}
+
+			response, err := client.CreateChatCompletion(ctx, messages, tt.tools...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedModel, response.Model)
+
+			streamResponses, streamErrors := client.CreateStreamingChatCompletion(ctx, messages, tt.tools...)
+			for streamResponse := range streamResponses {
+				assert.Equal(t, tt.expectedModel, streamResponse.Model)
+			}
+			require.NoError(t, <-streamErrors)
+
+			assert.Equal(t, []string{tt.expectedModel, tt.expectedModel}, *requested)
+		})
+	}
+}
+
+func TestCompositeLLMClient_StreamFallsBackBeforeFirstChunk(t *testing.T) {
+	gateway, requested := newRoutingGateway(t, map[string]int{"openai/gpt-4o": http.StatusServiceUnavailable})
+
+	client, err := server.NewCompositeLLMClient(newCompositeConfig(gateway.URL), zap.NewNop())
+	require.NoError(t, err)
+
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+
+	var received []*sdk.CreateChatCompletionStreamResponse
+	for streamResponse := range streamResponses {
+		received = append(received, streamResponse)
+	}
+	require.NoError(t, <-streamErrors)
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "anthropic/claude-3-5-haiku", received[0].Model)
+	assert.Equal(t, []string{"openai/gpt-4o", "anthropic/claude-3-5-haiku"}, *requested)
+}
+
+func TestNewLLMClient(t *testing.T) {
+	client, err := server.NewLLMClient(&config.AgentConfig{Provider: "openai", Model: "gpt-4o"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &server.OpenAICompatibleLLMClient{}, client)
+
+	_, err = server.NewLLMClient(&config.AgentConfig{Provider: "openai", Model: "gpt-4o", FallbackModels: []string{"/claude"}}, zap.NewNop())
+	assert.EqualError(t, err, "invalid fallback model /claude: model /claude must be given as provider/model")
+}
+
+func TestDefaultOpenAICompatibleAgent_RecordsAnsweringModel(t *testing.T) {
+	gateway, _ := newRoutingGateway(t, map[string]int{"openai/gpt-4o": http.StatusServiceUnavailable})
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithConfig(newCompositeConfig(gateway.URL)).Build()
+	require.NoError(t, err)
+
+	task, err := agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Hi"))
+	require.NoError(t, err)
+	require.Equal(t, adk.TaskStateCompleted, task.Status.State)
+
+	assert.Equal(t, "anthropic/claude-3-5-haiku", task.Metadata[server.MetadataLLMModelKey])
+	recorded, ok := task.Metadata[server.MetadataLLMRequestKey].(server.LLMRequestOptions)
+	require.True(t, ok)
+	assert.Equal(t, "claude-3-5-haiku", recorded.Model, "the options of the model that answered are recorded")
+}
+
+// closedPortURL returns the URL of a local port nothing listens on
+func closedPortURL(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return url
+}
+
+func TestCompositeLLMClient_FallsBackOnTransportErrors(t *testing.T) {
+	var requested []string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("provider"))
+		if r.URL.Query().Get("provider") == "openai" {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`)
+	}))
+	t.Cleanup(gateway.Close)
+
+	cfg := newCompositeConfig(gateway.URL)
+	cfg.FallbackModels = []string{"anthropic/claude-3-5-haiku"}
+	client, err := server.NewCompositeLLMClient(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	response, err := client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "anthropic/claude-3-5-haiku", response.Model, "a dropped connection falls back to the next model")
+	assert.Equal(t, []string{"openai", "anthropic"}, requested)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.CreateChatCompletion(ctx, []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.Error(t, err)
+	assert.Len(t, requested, 2, "a cancelled request does not fall back")
+}
+
+func TestOpenAICompatibleLLMClient_RetriesConnectionRefused(t *testing.T) {
+	client, err := server.NewOpenAICompatibleLLMClient(&config.AgentConfig{
+		Provider:            "openai",
+		Model:               "gpt-4o",
+		BaseURL:             closedPortURL(t),
+		MaxRetries:          2,
+		RetryInitialBackoff: time.Millisecond,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "llm request failed after 3 attempts")
+	assert.Contains(t, err.Error(), "connection refused")
+}
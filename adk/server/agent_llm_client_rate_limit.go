@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+
+	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
+	sdk "github.com/inference-gateway/sdk"
+)
+
+var _ LLMClient = (*RateLimitedLLMClient)(nil)
+var _ LLMRequestOptionsResolver = (*RateLimitedLLMClient)(nil)
+
+// RateLimitedLLMClient charges the LLM tokens used to the client of the request and
+// refuses new completions once the client has exhausted its token limit
+type RateLimitedLLMClient struct {
+	client  LLMClient
+	limiter *middlewares.RateLimiter
+}
+
+// NewRateLimitedLLMClient wraps an LLM client with per-client token rate limiting
+func NewRateLimitedLLMClient(client LLMClient, limiter *middlewares.RateLimiter) *RateLimitedLLMClient {
+	return &RateLimitedLLMClient{
+		client:  client,
+		limiter: limiter,
+	}
+}
+
+// CreateChatCompletion implements LLMClient.CreateChatCompletion
+func (c *RateLimitedLLMClient) CreateChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+	key, limited := middlewares.RateLimitKeyFromContext(ctx)
+	if !limited {
+		return c.client.CreateChatCompletion(ctx, messages, tools...)
+	}
+
+	if err := c.limiter.AllowTokens(key); err != nil {
+		return nil, err
+	}
+
+	response, err := c.client.CreateChatCompletion(ctx, messages, tools...)
+	if response != nil && response.Usage != nil {
+		c.limiter.ConsumeTokens(key, response.Usage.TotalTokens)
+	}
+	return response, err
+}
+
+// CreateStreamingChatCompletion implements LLMClient.CreateStreamingChatCompletion
+func (c *RateLimitedLLMClient) CreateStreamingChatCompletion(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+	key, limited := middlewares.RateLimitKeyFromContext(ctx)
+	if !limited {
+		return c.client.CreateStreamingChatCompletion(ctx, messages, tools...)
+	}
+
+	if err := c.limiter.AllowTokens(key); err != nil {
+		responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+		errorChan := make(chan error, 1)
+		errorChan <- err
+		close(responseChan)
+		close(errorChan)
+		return responseChan, errorChan
+	}
+
+	upstreamResponses, upstreamErrors := c.client.CreateStreamingChatCompletion(ctx, messages, tools...)
+	responseChan := make(chan *sdk.CreateChatCompletionStreamResponse)
+
+	go func() {
+		defer close(responseChan)
+
+		for response := range upstreamResponses {
+			if response != nil && response.Usage != nil {
+				c.limiter.ConsumeTokens(key, response.Usage.TotalTokens)
+			}
+
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return responseChan, upstreamErrors
+}
+
+// ResolveRequestOptions implements LLMRequestOptionsResolver for the wrapped client
+func (c *RateLimitedLLMClient) ResolveRequestOptions(ctx context.Context) LLMRequestOptions {
+	if resolver, ok := c.client.(LLMRequestOptionsResolver); ok {
+		return resolver.ResolveRequestOptions(ctx)
+	}
+
+	override, _ := LLMRequestOptionsFromContext(ctx)
+	return LLMRequestOptions{}.merge(override)
+}
@@ -0,0 +1,223 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newFlakyGateway starts a gateway answering its first requests with the given status codes
+func newFlakyGateway(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *int32) {
+	var requests int32
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(atomic.AddInt32(&requests, 1))
+		if attempt <= len(statuses) {
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(statuses[attempt-1])
+			_, _ = fmt.Fprint(w, `{"error":"try again"}`)
+			return
+		}
+
+		var request struct {
+			Stream bool `json:"stream"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`)
+	}))
+	t.Cleanup(gateway.Close)
+	return gateway, &requests
+}
+
+func newRetryConfig(baseURL string) *config.AgentConfig {
+	return &config.AgentConfig{
+		Provider:            "openai",
+		Model:               "gpt-4o",
+		BaseURL:             baseURL,
+		MaxRetries:          2,
+		RetryInitialBackoff: time.Millisecond,
+		RetryMaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestOpenAICompatibleLLMClient_Retries(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []int
+		expectedRequests int32
+		expectedError    string
+	}{
+		{
+			name:             "succeeds without retries",
+			expectedRequests: 1,
+		},
+		{
+			name:             "retries server errors",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			expectedRequests: 3,
+		},
+		{
+			name:             "retries rate limits",
+			statuses:         []int{http.StatusTooManyRequests},
+			expectedRequests: 2,
+		},
+		{
+			name:             "does not retry bad requests",
+			statuses:         []int{http.StatusBadRequest},
+			expectedRequests: 1,
+			expectedError:    "llm request failed: API error: try again (status code: 400)",
+		},
+		{
+			name:             "gives up after the maximum retries",
+			statuses:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedRequests: 3,
+			expectedError:    "llm request failed after 3 attempts: API error: try again (status code: 500)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, requests := newFlakyGateway(t, tt.statuses, nil)
+
+			client, err := server.NewOpenAICompatibleLLMClient(newRetryConfig(gateway.URL), zap.NewNop())
+			require.NoError(t, err)
+			telemetry := &mocks.FakeOpenTelemetry{}
+			client.SetTelemetry(telemetry)
+
+			response, err := client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+			assert.Equal(t, tt.expectedRequests, atomic.LoadInt32(requests))
+
+			require.Equal(t, 1, telemetry.RecordLLMRequestAttemptsCallCount())
+			_, attrs, attempts, success := telemetry.RecordLLMRequestAttemptsArgsForCall(0)
+			assert.Equal(t, "openai", attrs.Provider)
+			assert.Equal(t, "gpt-4o", attrs.Model)
+			assert.Equal(t, int(tt.expectedRequests), attempts)
+			assert.Equal(t, tt.expectedError == "", success)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Hello", response.Choices[0].Message.Content)
+		})
+	}
+}
+
+func TestOpenAICompatibleLLMClient_RespectsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxBackoff       time.Duration
+		expectedRequests int32
+		expectedWait     time.Duration
+		expectedError    bool
+	}{
+		{
+			name:             "retry after within the maximum backoff",
+			maxBackoff:       2 * time.Second,
+			expectedRequests: 2,
+			expectedWait:     time.Second,
+		},
+		{
+			name:             "retry after beyond the maximum backoff",
+			maxBackoff:       5 * time.Millisecond,
+			expectedRequests: 1,
+			expectedError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, requests := newFlakyGateway(t, []int{http.StatusTooManyRequests}, http.Header{"Retry-After": []string{"1"}})
+
+			cfg := newRetryConfig(gateway.URL)
+			cfg.RetryMaxBackoff = tt.maxBackoff
+			client, err := server.NewOpenAICompatibleLLMClient(cfg, zap.NewNop())
+			require.NoError(t, err)
+
+			start := time.Now()
+			_, err = client.CreateChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+
+			assert.Equal(t, tt.expectedRequests, atomic.LoadInt32(requests))
+			if tt.expectedError {
+				assert.Error(t, err, "the client gives up instead of waiting longer than the maximum backoff")
+				assert.Less(t, time.Since(start), time.Second)
+				return
+			}
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, time.Since(start), tt.expectedWait, "the retry waits as long as the gateway asked")
+		})
+	}
+}
+
+func TestOpenAICompatibleLLMClient_StreamCancelled(t *testing.T) {
+	release := make(chan struct{})
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	t.Cleanup(gateway.Close)
+	t.Cleanup(func() { close(release) })
+
+	client, err := server.NewOpenAICompatibleLLMClient(newRetryConfig(gateway.URL), zap.NewNop())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(ctx, []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+
+	first := <-streamResponses
+	require.NotNil(t, first)
+	assert.Equal(t, "Hel", first.Choices[0].Delta.Content)
+	cancel()
+
+	for range streamResponses {
+	}
+	assert.ErrorIs(t, <-streamErrors, context.Canceled, "a stream cut short by the cancellation is not reported as complete")
+}
+
+func TestOpenAICompatibleLLMClient_RetriesStreamBeforeFirstChunk(t *testing.T) {
+	gateway, requests := newFlakyGateway(t, []int{http.StatusServiceUnavailable}, nil)
+
+	client, err := server.NewOpenAICompatibleLLMClient(newRetryConfig(gateway.URL), zap.NewNop())
+	require.NoError(t, err)
+	telemetry := &mocks.FakeOpenTelemetry{}
+	client.SetTelemetry(telemetry)
+
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+
+	var streamed string
+	for streamResponse := range streamResponses {
+		streamed += streamResponse.Choices[0].Delta.Content
+	}
+	require.NoError(t, <-streamErrors)
+
+	assert.Equal(t, "Hello", streamed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+	require.Equal(t, 1, telemetry.RecordLLMRequestAttemptsCallCount())
+	_, _, attempts, success := telemetry.RecordLLMRequestAttemptsArgsForCall(0)
+	assert.Equal(t, 2, attempts)
+	assert.True(t, success)
+}
@@ -1,11 +1,16 @@
 package server_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/inference-gateway/a2a/adk/server"
 	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -141,3 +146,40 @@ func TestLLMClient_WithMockSDK(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, client)
 }
+
+func TestRateLimitedLLMClient_ChargesTokensPerClient(t *testing.T) {
+	limiter, err := middlewares.NewRateLimiter(zap.NewNop(), config.Config{
+		RateLimitConfig: config.RateLimitConfig{
+			Enable:          true,
+			KeyBy:           "principal",
+			TokensPerMinute: 100,
+		},
+	})
+	require.NoError(t, err)
+
+	fakeClient := &mocks.FakeLLMClient{}
+	fakeClient.CreateChatCompletionReturns(&sdk.CreateChatCompletionResponse{
+		Usage: &sdk.CompletionUsage{TotalTokens: 150},
+	}, nil)
+
+	client := server.NewRateLimitedLLMClient(fakeClient, limiter)
+	messages := []sdk.Message{{Role: sdk.User, Content: "hello"}}
+
+	aliceCtx := middlewares.WithRateLimitKey(context.Background(), "principal:alice")
+	_, err = client.CreateChatCompletion(aliceCtx, messages)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(aliceCtx, messages)
+	var rateLimitErr *middlewares.RateLimitExceededError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, "token", rateLimitErr.Limit)
+	assert.Equal(t, 1, fakeClient.CreateChatCompletionCallCount(), "exhausted clients do not reach the llm")
+
+	bobCtx := middlewares.WithRateLimitKey(context.Background(), "principal:bob")
+	_, err = client.CreateChatCompletion(bobCtx, messages)
+	assert.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), messages)
+	assert.NoError(t, err, "requests without a rate limit key are not limited")
+	assert.Equal(t, 3, fakeClient.CreateChatCompletionCallCount())
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+)
+
+// MetadataLLMOptionsKey is the message metadata key overriding the LLM options of the request
+const MetadataLLMOptionsKey = "llmOptions"
+
+// MetadataLLMRequestKey is the task metadata key recording the LLM options the task was processed with
+const MetadataLLMRequestKey = "llmRequest"
+
+// MetadataLLMModelKey is the task metadata key recording the model that answered the last LLM completion of the task
+const MetadataLLMModelKey = "llmModel"
+
+// maxStopSequences is the number of stop sequences accepted by OpenAI-compatible APIs
+const maxStopSequences = 4
+
+// Tool choices accepted in the LLM options besides forcing a function
+const (
+	ToolChoiceNone     = "none"
+	ToolChoiceAuto     = "auto"
+	ToolChoiceRequired = "required"
+)
+
+// LLMRequestOptions are the sampling and tool options sent with a chat completion request.
+// Messages can override the model, temperature, max tokens, stop sequences and tool choice
+// with an object under the llmOptions metadata key, for example
+// {"llmOptions": {"temperature": 0.2, "stop": ["END"], "tool_choice": "none"}}.
+type LLMRequestOptions struct {
+	Model            string      `json:"model,omitempty"`
+	Temperature      *float64    `json:"temperature,omitempty"`
+	TopP             *float64    `json:"top_p,omitempty"`
+	FrequencyPenalty *float64    `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64    `json:"presence_penalty,omitempty"`
+	MaxTokens        *int        `json:"max_tokens,omitempty"`
+	Stop             []string    `json:"stop,omitempty"`
+	ToolChoice       interface{} `json:"tool_choice,omitempty"`
+}
+
+// LLMRequestOptionsResolver is implemented by LLM clients that can report the options they send for a request
+type LLMRequestOptionsResolver interface {
+	// ResolveRequestOptions returns the options of a request made with the context, including its overrides
+	ResolveRequestOptions(ctx context.Context) LLMRequestOptions
+}
+
+// merge returns the options with the values set in the override replacing their own
+func (o LLMRequestOptions) merge(override *LLMRequestOptions) LLMRequestOptions {
+	if override == nil {
+		return o
+	}
+
+	if override.Model != "" {
+		o.Model = override.Model
+	}
+	if override.Temperature != nil {
+		o.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		o.TopP = override.TopP
+	}
+	if override.FrequencyPenalty != nil {
+		o.FrequencyPenalty = override.FrequencyPenalty
+	}
+	if override.PresencePenalty != nil {
+		o.PresencePenalty = override.PresencePenalty
+	}
+	if override.MaxTokens != nil {
+		o.MaxTokens = override.MaxTokens
+	}
+	if len(override.Stop) > 0 {
+		o.Stop = override.Stop
+	}
+	if override.ToolChoice != nil {
+		o.ToolChoice = override.ToolChoice
+	}
+	return o
+}
+
+// ParseLLMRequestOptions validates the LLM options override of the message metadata.
+// A model override must be one of the models the configuration allows, see AllowedLLMModels.
+// It returns nil if the metadata does not override any option.
+func ParseLLMRequestOptions(metadata map[string]interface{}, cfg *config.AgentConfig) (*LLMRequestOptions, error) {
+	value, ok := metadata[MetadataLLMOptionsKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, NewInvalidLLMOptionsError(MetadataLLMOptionsKey, err.Error())
+	}
+
+	var override struct {
+		Model       *string         `json:"model"`
+		Temperature *float64        `json:"temperature"`
+		MaxTokens   *int            `json:"max_tokens"`
+		Stop        json.RawMessage `json:"stop"`
+		ToolChoice  json.RawMessage `json:"tool_choice"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&override); err != nil {
+		return nil, llmOptionsDecodeError(err)
+	}
+
+	options := &LLMRequestOptions{}
+
+	if override.Model != nil {
+		if strings.TrimSpace(*override.Model) == "" {
+			return nil, NewInvalidLLMOptionsError("model", "must not be empty")
+		}
+		if !llmModelAllowed(cfg, *override.Model) {
+			return nil, NewInvalidLLMOptionsError("model", fmt.Sprintf("%s is not an allowed model", *override.Model))
+		}
+		options.Model = *override.Model
+	}
+
+	if override.Temperature != nil {
+		if *override.Temperature < 0 || *override.Temperature > 2 {
+			return nil, NewInvalidLLMOptionsError("temperature", "must be between 0 and 2")
+		}
+		options.Temperature = override.Temperature
+	}
+
+	if override.MaxTokens != nil {
+		if *override.MaxTokens <= 0 {
+			return nil, NewInvalidLLMOptionsError("max_tokens", "must be greater than 0")
+		}
+		options.MaxTokens = override.MaxTokens
+	}
+
+	if len(override.Stop) > 0 {
+		stop, err := parseStopSequences(override.Stop)
+		if err != nil {
+			return nil, err
+		}
+		options.Stop = stop
+	}
+
+	if len(override.ToolChoice) > 0 {
+		toolChoice, err := parseToolChoice(override.ToolChoice)
+		if err != nil {
+			return nil, err
+		}
+		options.ToolChoice = toolChoice
+	}
+
+	return options, nil
+}
+
+// AllowedLLMModels returns the models messages may select in their llmOptions: the configured model,
+// the fallback and routing models and the additional allowed models
+func AllowedLLMModels(cfg *config.AgentConfig) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	candidates := append([]string{cfg.Model}, cfg.FallbackModels...)
+	candidates = append(candidates, cfg.Routing.ToolsModel, cfg.Routing.ShortPromptModel)
+	candidates = append(candidates, cfg.AllowedModels...)
+
+	models := make([]string, 0, len(candidates))
+	for _, model := range candidates {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, qualifyModelName(model, cfg.Provider))
+		}
+	}
+	return models
+}
+
+// llmModelAllowed reports whether the model, qualified with the configured provider, is an allowed model
+func llmModelAllowed(cfg *config.AgentConfig, model string) bool {
+	if cfg == nil {
+		return false
+	}
+
+	model = qualifyModelName(strings.TrimSpace(model), cfg.Provider)
+	for _, allowed := range AllowedLLMModels(cfg) {
+		if allowed == model {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStopSequences accepts a single stop sequence or a list of up to four
+func parseStopSequences(raw json.RawMessage) ([]string, error) {
+	var stop []string
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		stop = []string{single}
+	} else if err := json.Unmarshal(raw, &stop); err != nil {
+		return nil, NewInvalidLLMOptionsError("stop", "must be a string or a list of strings")
+	}
+
+	if len(stop) == 0 || len(stop) > maxStopSequences {
+		return nil, NewInvalidLLMOptionsError("stop", fmt.Sprintf("must contain between 1 and %d sequences", maxStopSequences))
+	}
+	for _, sequence := range stop {
+		if sequence == "" {
+			return nil, NewInvalidLLMOptionsError("stop", "must not contain empty sequences")
+		}
+	}
+	return stop, nil
+}
+
+// parseToolChoice accepts none, auto, required or a function the LLM must call
+func parseToolChoice(raw json.RawMessage) (interface{}, error) {
+	var mode string
+	if err := json.Unmarshal(raw, &mode); err == nil {
+		switch mode {
+		case ToolChoiceNone, ToolChoiceAuto, ToolChoiceRequired:
+			return mode, nil
+		}
+		return nil, NewInvalidLLMOptionsError("tool_choice", fmt.Sprintf("must be %s, %s, %s or a function", ToolChoiceNone, ToolChoiceAuto, ToolChoiceRequired))
+	}
+
+	var function struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &function); err != nil || function.Type != "function" || function.Function.Name == "" {
+		return nil, NewInvalidLLMOptionsError("tool_choice", `functions must be given as {"type": "function", "function": {"name": "..."}}`)
+	}
+
+	return map[string]interface{}{
+		"type":     "function",
+		"function": map[string]interface{}{"name": function.Function.Name},
+	}, nil
+}
+
+// llmOptionsDecodeError reports the option that could not be decoded
+func llmOptionsDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return NewInvalidLLMOptionsError(MetadataLLMOptionsKey, "must be an object")
+		}
+		return NewInvalidLLMOptionsError(typeErr.Field, "must be of type "+typeErr.Type.String())
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return NewInvalidLLMOptionsError(strings.Trim(field, `"`), "is not supported")
+	}
+	return NewInvalidLLMOptionsError(MetadataLLMOptionsKey, err.Error())
+}
+
+// llmRequestOptionsContextKey is the context key of the LLM options override of the request
+type llmRequestOptionsContextKey struct{}
+
+// WithLLMRequestOptions returns a copy of ctx carrying the LLM options overriding the configuration of the LLM client
+func WithLLMRequestOptions(ctx context.Context, options *LLMRequestOptions) context.Context {
+	if options == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, llmRequestOptionsContextKey{}, options)
+}
+
+// LLMRequestOptionsFromContext returns the LLM options override of the request, if any
+func LLMRequestOptionsFromContext(ctx context.Context) (*LLMRequestOptions, bool) {
+	options, ok := ctx.Value(llmRequestOptionsContextKey{}).(*LLMRequestOptions)
+	return options, ok
+}
+
+// applyLLMRequestOptions passes the LLM options override of the message on to the LLM client
+// and records the options the task is processed with in the task metadata
+func applyLLMRequestOptions(ctx context.Context, task *adk.Task, message *adk.Message, llmClient LLMClient, cfg *config.AgentConfig) (context.Context, error) {
+	var override *LLMRequestOptions
+	if message != nil {
+		var err error
+		override, err = ParseLLMRequestOptions(message.Metadata, cfg)
+		if err != nil {
+			return ctx, err
+		}
+	}
+	ctx = WithLLMRequestOptions(ctx, override)
+
+	effective := override
+	if resolver, ok := llmClient.(LLMRequestOptionsResolver); ok {
+		resolved := resolver.ResolveRequestOptions(ctx)
+		effective = &resolved
+	}
+	if effective == nil {
+		return ctx, nil
+	}
+
+	SetTaskMetadata(ctx, task, MetadataLLMRequestKey, *effective)
+	return ctx, nil
+}
+
+// recordLLMModel records the model that answered a completion of the task, as reported in the response,
+// and the options of the LLM client resolved for that model, which differ from the requested ones
+// when a routing rule or a fallback model answered
+func recordLLMModel(ctx context.Context, task *adk.Task, llmClient LLMClient, model string) {
+	if task == nil || model == "" {
+		return
+	}
+	SetTaskMetadata(ctx, task, MetadataLLMModelKey, model)
+
+	resolver, ok := llmClient.(LLMRequestOptionsResolver)
+	if !ok {
+		return
+	}
+	answered := LLMRequestOptions{}
+	if override, ok := LLMRequestOptionsFromContext(ctx); ok {
+		answered = *override
+	}
+	answered.Model = model
+	SetTaskMetadata(ctx, task, MetadataLLMRequestKey, resolver.ResolveRequestOptions(WithLLMRequestOptions(ctx, &answered)))
+}
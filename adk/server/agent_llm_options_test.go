@@ -0,0 +1,336 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseLLMRequestOptions(t *testing.T) {
+	temperature := 0.2
+	maxTokens := 256
+	cfg := &config.AgentConfig{
+		Provider:       "openai",
+		Model:          "gpt-4o",
+		FallbackModels: []string{"anthropic/claude-3-5-haiku"},
+		AllowedModels:  []string{"openai/gpt-4o-mini"},
+	}
+
+	tests := []struct {
+		name            string
+		options         interface{}
+		expected        *server.LLMRequestOptions
+		expectedInvalid string
+	}{
+		{
+			name:     "no override",
+			expected: nil,
+		},
+		{
+			name: "all options",
+			options: map[string]interface{}{
+				"model":       "openai/gpt-4o-mini",
+				"temperature": 0.2,
+				"max_tokens":  256,
+				"stop":        []interface{}{"END", "STOP"},
+				"tool_choice": map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "search"}},
+			},
+			expected: &server.LLMRequestOptions{
+				Model:       "openai/gpt-4o-mini",
+				Temperature: &temperature,
+				MaxTokens:   &maxTokens,
+				Stop:        []string{"END", "STOP"},
+				ToolChoice:  map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "search"}},
+			},
+		},
+		{
+			name:     "single stop sequence",
+			options:  map[string]interface{}{"stop": "END", "tool_choice": "none"},
+			expected: &server.LLMRequestOptions{Stop: []string{"END"}, ToolChoice: "none"},
+		},
+		{
+			name:            "not an object",
+			options:         "creative",
+			expectedInvalid: "invalid llm option llmOptions: must be an object",
+		},
+		{
+			name:            "unknown option",
+			options:         map[string]interface{}{"top_k": 5},
+			expectedInvalid: "invalid llm option top_k: is not supported",
+		},
+		{
+			name:            "temperature out of range",
+			options:         map[string]interface{}{"temperature": 2.5},
+			expectedInvalid: "invalid llm option temperature: must be between 0 and 2",
+		},
+		{
+			name:            "temperature of the wrong type",
+			options:         map[string]interface{}{"temperature": "hot"},
+			expectedInvalid: "invalid llm option temperature: must be of type float64",
+		},
+		{
+			name:            "max tokens not positive",
+			options:         map[string]interface{}{"max_tokens": 0},
+			expectedInvalid: "invalid llm option max_tokens: must be greater than 0",
+		},
+		{
+			name:            "too many stop sequences",
+			options:         map[string]interface{}{"stop": []string{"a", "b", "c", "d", "e"}},
+			expectedInvalid: "invalid llm option stop: must contain between 1 and 4 sequences",
+		},
+		{
+			name:            "empty model",
+			options:         map[string]interface{}{"model": " "},
+			expectedInvalid: "invalid llm option model: must not be empty",
+		},
+		{
+			name:     "configured model without provider",
+			options:  map[string]interface{}{"model": "gpt-4o"},
+			expected: &server.LLMRequestOptions{Model: "gpt-4o"},
+		},
+		{
+			name:     "fallback model",
+			options:  map[string]interface{}{"model": "anthropic/claude-3-5-haiku"},
+			expected: &server.LLMRequestOptions{Model: "anthropic/claude-3-5-haiku"},
+		},
+		{
+			name:            "model not allowed",
+			options:         map[string]interface{}{"model": "anthropic/claude-3-opus"},
+			expectedInvalid: "invalid llm option model: anthropic/claude-3-opus is not an allowed model",
+		},
+		{
+			name:            "model of another provider",
+			options:         map[string]interface{}{"model": "groq/gpt-4o"},
+			expectedInvalid: "invalid llm option model: groq/gpt-4o is not an allowed model",
+		},
+		{
+			name:            "unknown tool choice",
+			options:         map[string]interface{}{"tool_choice": "always"},
+			expectedInvalid: "invalid llm option tool_choice: must be none, auto, required or a function",
+		},
+		{
+			name:            "function without name",
+			options:         map[string]interface{}{"tool_choice": map[string]interface{}{"type": "function"}},
+			expectedInvalid: `invalid llm option tool_choice: functions must be given as {"type": "function", "function": {"name": "..."}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := map[string]interface{}{}
+			if tt.options != nil {
+				metadata[server.MetadataLLMOptionsKey] = tt.options
+			}
+
+			options, err := server.ParseLLMRequestOptions(metadata, cfg)
+			if tt.expectedInvalid != "" {
+				require.Error(t, err)
+				assert.IsType(t, &server.InvalidLLMOptionsError{}, err)
+				assert.Equal(t, tt.expectedInvalid, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, options)
+		})
+	}
+}
+
+// newLLMGateway starts a gateway recording the chat completion requests it receives
+func newLLMGateway(t *testing.T, requests *[]map[string]interface{}) *httptest.Server {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "openai", r.URL.Query().Get("provider"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "true", r.Header.Get("X-A2A-Bypass"))
+
+		var request map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		*requests = append(*requests, request)
+
+		if request["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+			_, _ = fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n")
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id":"1","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hello"}}]}`)
+	}))
+	t.Cleanup(gateway.Close)
+	return gateway
+}
+
+func newLLMGatewayConfig(baseURL string) *config.AgentConfig {
+	return &config.AgentConfig{
+		Provider:         "openai",
+		Model:            "openai/gpt-4o",
+		BaseURL:          baseURL + "/v1",
+		APIKey:           "secret",
+		MaxTokens:        1024,
+		Temperature:      0.7,
+		TopP:             0.9,
+		FrequencyPenalty: 0.5,
+		PresencePenalty:  0.25,
+	}
+}
+
+func TestOpenAICompatibleLLMClient_RequestOptions(t *testing.T) {
+	maxTokens := 64
+	temperature := 0.0
+	tools := []sdk.ChatCompletionTool{{Type: "function", Function: sdk.FunctionObject{Name: "search"}}}
+
+	tests := []struct {
+		name     string
+		override *server.LLMRequestOptions
+		tools    []sdk.ChatCompletionTool
+		expected map[string]interface{}
+	}{
+		{
+			name: "configured sampling parameters",
+			expected: map[string]interface{}{
+				"model":             "gpt-4o",
+				"temperature":       0.7,
+				"top_p":             0.9,
+				"frequency_penalty": 0.5,
+				"presence_penalty":  0.25,
+				"max_tokens":        float64(1024),
+			},
+		},
+		{
+			name: "per-request override",
+			override: &server.LLMRequestOptions{
+				Model:       "openai/gpt-4o-mini",
+				Temperature: &temperature,
+				MaxTokens:   &maxTokens,
+				Stop:        []string{"END"},
+				ToolChoice:  server.ToolChoiceRequired,
+			},
+			tools: tools,
+			expected: map[string]interface{}{
+				"model":             "gpt-4o-mini",
+				"temperature":       0.0,
+				"top_p":             0.9,
+				"frequency_penalty": 0.5,
+				"presence_penalty":  0.25,
+				"max_tokens":        float64(64),
+				"stop":              []interface{}{"END"},
+				"tool_choice":       "required",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]interface{}
+			gateway := newLLMGateway(t, &requests)
+
+			client, err := server.NewOpenAICompatibleLLMClient(newLLMGatewayConfig(gateway.URL), zap.NewNop())
+			require.NoError(t, err)
+
+			ctx := server.WithLLMRequestOptions(context.Background(), tt.override)
+			messages := []sdk.Message{{Role: sdk.User, Content: "Hi"}}
+
+			response, err := client.CreateChatCompletion(ctx, messages, tt.tools...)
+			require.NoError(t, err)
+			assert.Equal(t, "Hello", response.Choices[0].Message.Content)
+
+			var streamed string
+			streamResponses, streamErrors := client.CreateStreamingChatCompletion(ctx, messages, tt.tools...)
+			for streamResponse := range streamResponses {
+				streamed += streamResponse.Choices[0].Delta.Content
+			}
+			require.NoError(t, <-streamErrors)
+			assert.Equal(t, "Hello", streamed)
+
+			require.Len(t, requests, 2)
+			for _, request := range requests {
+				for key, value := range tt.expected {
+					assert.Equal(t, value, request[key], key)
+				}
+			}
+			assert.Equal(t, map[string]interface{}{"include_usage": true}, requests[1]["stream_options"])
+		})
+	}
+}
+
+func TestDefaultOpenAICompatibleAgent_RecordsLLMRequestOptions(t *testing.T) {
+	var requests []map[string]interface{}
+	gateway := newLLMGateway(t, &requests)
+
+	client, err := server.NewOpenAICompatibleLLMClient(newLLMGatewayConfig(gateway.URL), zap.NewNop())
+	require.NoError(t, err)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(client).Build()
+	require.NoError(t, err)
+
+	message := textMessage("user", "Write a haiku")
+	message.Metadata = map[string]interface{}{
+		server.MetadataLLMOptionsKey: map[string]interface{}{"temperature": 1.2, "stop": "\n\n"},
+	}
+
+	task, err := agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, message)
+	require.NoError(t, err)
+	require.Equal(t, adk.TaskStateCompleted, task.Status.State)
+
+	recorded, ok := task.Metadata[server.MetadataLLMRequestKey].(server.LLMRequestOptions)
+	require.True(t, ok)
+	assert.Equal(t, "gpt-4o", recorded.Model)
+	assert.Equal(t, 1.2, *recorded.Temperature)
+	assert.Equal(t, 0.9, *recorded.TopP)
+	assert.Equal(t, 1024, *recorded.MaxTokens)
+	assert.Equal(t, []string{"\n\n"}, recorded.Stop)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, 1.2, requests[0]["temperature"])
+}
+
+func TestMessageHandler_RejectsInvalidLLMOptions(t *testing.T) {
+	messageHandler := server.NewDefaultMessageHandler(zap.NewNop(), server.NewDefaultTaskManager(zap.NewNop(), 10), &config.Config{})
+
+	message := textMessage("user", "Hi")
+	message.Metadata = map[string]interface{}{
+		server.MetadataLLMOptionsKey: map[string]interface{}{"temperature": -1},
+	}
+
+	_, err := messageHandler.HandleMessageSend(context.Background(), adk.MessageSendParams{Message: *message})
+	var invalid *server.InvalidLLMOptionsError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "temperature", invalid.Option)
+
+	err = messageHandler.HandleMessageStream(context.Background(), adk.MessageSendParams{Message: *message}, make(chan adk.SendStreamingMessageResponse, 1))
+	require.ErrorAs(t, err, &invalid)
+}
+
+func TestMessageHandler_RejectsModelsNotAllowed(t *testing.T) {
+	cfg := &config.Config{AgentConfig: config.AgentConfig{Provider: "openai", Model: "gpt-4o", AllowedModels: []string{"openai/gpt-4o-mini"}}}
+	messageHandler := server.NewDefaultMessageHandler(zap.NewNop(), server.NewDefaultTaskManager(zap.NewNop(), 10), cfg)
+
+	send := func(model string) error {
+		message := textMessage("user", "Hi")
+		message.Metadata = map[string]interface{}{
+			server.MetadataLLMOptionsKey: map[string]interface{}{"model": model},
+		}
+		_, err := messageHandler.HandleMessageSend(context.Background(), adk.MessageSendParams{Message: *message})
+		return err
+	}
+
+	assert.NoError(t, send("gpt-4o-mini"))
+
+	var invalid *server.InvalidLLMOptionsError
+	require.ErrorAs(t, send("openai/o1"), &invalid)
+	assert.Equal(t, "model", invalid.Option)
+}
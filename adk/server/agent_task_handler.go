@@ -4,20 +4,35 @@ import (
 	"context"
 
 	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	utils "github.com/inference-gateway/a2a/adk/server/utils"
 	zap "go.uber.org/zap"
 )
 
-// AgentTaskHandler is a TaskHandler that delegates to an OpenAICompatibleAgent
+var _ StreamingTaskHandler = (*AgentTaskHandler)(nil)
+
+// AgentTaskHandler is a StreamingTaskHandler that delegates to an OpenAICompatibleAgent
 type AgentTaskHandler struct {
-	logger *zap.Logger
-	agent  OpenAICompatibleAgent
+	logger    *zap.Logger
+	agent     OpenAICompatibleAgent
+	config    *config.AgentConfig
+	converter *utils.OptimizedMessageConverter
 }
 
 // NewAgentTaskHandler creates a new task handler that uses an OpenAI-compatible agent
+// Streamed tasks are limited by the configuration of the agent
 func NewAgentTaskHandler(logger *zap.Logger, agent OpenAICompatibleAgent) *AgentTaskHandler {
+	return NewAgentTaskHandlerWithConfig(logger, agent, nil)
+}
+
+// NewAgentTaskHandlerWithConfig creates a new task handler that uses an OpenAI-compatible agent
+// Streamed tasks are limited by the iterations and parallel tool calls of cfg instead of those of the agent
+func NewAgentTaskHandlerWithConfig(logger *zap.Logger, agent OpenAICompatibleAgent, cfg *config.AgentConfig) *AgentTaskHandler {
 	return &AgentTaskHandler{
-		logger: logger,
-		agent:  agent,
+		logger:    logger,
+		agent:     agent,
+		config:    cfg,
+		converter: utils.NewOptimizedMessageConverter(logger),
 	}
 }
 
@@ -35,8 +50,84 @@ func (h *AgentTaskHandler) HandleTask(ctx context.Context, task *adk.Task, messa
 	return h.agent.ProcessTask(ctx, task, message)
 }
 
+// HandleStreamingTask streams the task with the agent, or with the agent a skill router selects for it
+// LLM completions are emitted as chunks and tool executions as status updates,
+// workflow agents emit the status updates of their steps
+func (h *AgentTaskHandler) HandleStreamingTask(ctx context.Context, task *adk.Task, message *adk.Message, emitter StreamEventEmitter) (*adk.Task, error) {
+	h.logger.Info("streaming task with openai-compatible agent",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID))
+
+	if h.agent == nil {
+		h.logger.Error("agent not configured")
+		return h.handleError(task, "Agent not configured"), nil
+	}
+
+	agent, err := h.streamingAgent(ctx, task, message)
+	if err != nil {
+		return h.handleError(task, err.Error()), nil
+	}
+	if _, ok := agent.(workflowAgent); ok || agent.GetLLMClient() == nil {
+		routed := &AgentTaskHandler{logger: h.logger, agent: agent}
+		return taskHandlerStreamer{TaskHandler: routed}.HandleStreamingTask(ctx, task, message, emitter)
+	}
+
+	cfg := h.streamingConfig(agent)
+	streamer := &agentStreamer{
+		logger:               h.logger,
+		agent:                agent,
+		llmClient:            agent.GetLLMClient(),
+		toolBox:              agent.GetToolBox(),
+		converter:            h.converter,
+		config:               cfg,
+		maxIterations:        cfg.MaxChatCompletionIterations,
+		maxParallelToolCalls: cfg.MaxParallelToolCalls,
+		emitter:              emitter,
+	}
+	return streamer.stream(ctx, task, message), nil
+}
+
+// streamingAgent returns the agent streaming the task, the agent selected by a skill router if the agent is one
+func (h *AgentTaskHandler) streamingAgent(ctx context.Context, task *adk.Task, message *adk.Message) (OpenAICompatibleAgent, error) {
+	router, ok := h.agent.(*SkillRouter)
+	if !ok {
+		return h.agent, nil
+	}
+
+	agent, err := router.RouteAgent(ctx, task, message)
+	if err != nil {
+		return nil, err
+	}
+	if agent == nil {
+		return h.agent, nil
+	}
+	if _, ok := agent.(workflowAgent); !ok && agent.GetLLMClient() == nil {
+		return h.agent, nil
+	}
+	return agent, nil
+}
+
+// streamingConfig returns the configuration limiting the streaming of the agent
+func (h *AgentTaskHandler) streamingConfig(agent OpenAICompatibleAgent) *config.AgentConfig {
+	if h.config != nil {
+		return h.config
+	}
+	if defaultAgent, ok := agent.(*DefaultOpenAICompatibleAgent); ok && defaultAgent.config != nil {
+		return defaultAgent.config
+	}
+	return &config.AgentConfig{
+		MaxChatCompletionIterations: 10,
+		MaxParallelToolCalls:        DefaultMaxParallelToolCalls,
+	}
+}
+
 // handleError creates an error response task
 func (h *AgentTaskHandler) handleError(task *adk.Task, errorMsg string) *adk.Task {
+	return failedTask(task, errorMsg)
+}
+
+// failedTask moves the task to the failed state with the error message as its status message
+func failedTask(task *adk.Task, errorMsg string) *adk.Task {
 	task.Status.State = adk.TaskStateFailed
 
 	errorMessage := &adk.Message{
@@ -0,0 +1,484 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	utils "github.com/inference-gateway/a2a/adk/server/utils"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+// agentStreamer streams one task with the LLM and the tools of an agent
+type agentStreamer struct {
+	logger               *zap.Logger
+	agent                OpenAICompatibleAgent
+	llmClient            LLMClient
+	toolBox              ToolBox
+	converter            *utils.OptimizedMessageConverter
+	config               *config.AgentConfig
+	maxIterations        int
+	maxParallelToolCalls int
+	emitter              StreamEventEmitter
+}
+
+// stream handles the iterative streaming process with tool calling support
+func (s *agentStreamer) stream(ctx context.Context, task *adk.Task, message *adk.Message) *adk.Task {
+	ctx, err := applyLLMRequestOptions(ctx, task, message, s.llmClient, s.config)
+	if err != nil {
+		s.logger.Error("invalid llm options", zap.Error(err))
+		s.emitError(ctx, task, err.Error())
+		return task
+	}
+
+	if approval, ok := pendingToolApproval(task); ok && s.toolBox != nil {
+		if !s.resumeToolApproval(ctx, task, message, approval) {
+			return task
+		}
+	}
+
+	messages := make([]adk.Message, 0)
+
+	systemMessage := adk.Message{
+		Kind:      "message",
+		MessageID: "system-prompt",
+		Role:      "system",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": s.agent.GetSystemPrompt(),
+			},
+		},
+	}
+	messages = append(messages, systemMessage)
+
+	if message != nil {
+		messages = append(messages, *message)
+	}
+
+	messages = append(messages, task.History...)
+
+	var tools []sdk.ChatCompletionTool
+	if s.toolBox != nil {
+		tools = s.toolBox.GetTools()
+	}
+
+	for iteration := 1; iteration <= s.maxIterations; iteration++ {
+		s.logger.Debug("starting streaming iteration",
+			zap.Int("iteration", iteration),
+			zap.String("task_id", task.ID))
+
+		sdkMessages, err := s.converter.ConvertToSDK(messages)
+		if err != nil {
+			s.logger.Error("failed to convert messages", zap.Error(err))
+			s.emitError(ctx, task, fmt.Sprintf("Message conversion failed: %v", err))
+			return task
+		}
+
+		if err := checkTokenBudget(ctx, task); err != nil {
+			s.logger.Warn("llm budget exhausted", zap.Error(err), zap.String("task_id", task.ID))
+			task.Status.State = adk.TaskStateFailed
+			s.emitError(ctx, task, budgetExhaustedMessage(err))
+			return task
+		}
+
+		streamResponseChan, streamErrorChan := s.llmClient.CreateStreamingChatCompletion(ctx, sdkMessages, tools...)
+		toolCallsExecuted, assistantMessage, toolResultMessages, inputErr, approval := s.processStream(ctx, task, iteration, streamResponseChan, streamErrorChan)
+
+		if approval != nil {
+			s.emitToolApprovalRequired(ctx, task, approval)
+			return task
+		}
+
+		if assistantMessage != nil {
+			messages = append(messages, *assistantMessage)
+			task.History = append(task.History, *assistantMessage)
+		}
+
+		for _, toolResultMsg := range toolResultMessages {
+			messages = append(messages, toolResultMsg)
+			task.History = append(task.History, toolResultMsg)
+		}
+
+		if inputErr != nil {
+			s.emitInputRequired(ctx, task, inputErr.Message)
+			return task
+		}
+
+		if !toolCallsExecuted {
+			finalMessage := assistantMessage
+			if finalMessage != nil {
+				task.Status.Message = finalMessage
+			}
+
+			s.logger.Info("streaming task completed successfully",
+				zap.String("task_id", task.ID),
+				zap.Int("iterations", iteration))
+
+			_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+				State:   adk.TaskStateCompleted,
+				Message: finalMessage,
+			}, true)
+			return task
+		}
+
+		s.logger.Debug("tool calls executed, continuing to next iteration",
+			zap.Int("iteration", iteration),
+			zap.String("task_id", task.ID))
+	}
+
+	s.logger.Warn("max streaming iterations reached",
+		zap.String("task_id", task.ID),
+		zap.Int("max_iterations", s.maxIterations))
+	s.emitError(ctx, task, fmt.Sprintf("Maximum iterations (%d) reached without completion", s.maxIterations))
+	return task
+}
+
+// processStream handles the streaming response and tool execution
+func (s *agentStreamer) processStream(
+	ctx context.Context,
+	task *adk.Task,
+	iteration int,
+	streamResponseChan <-chan *sdk.CreateChatCompletionStreamResponse,
+	streamErrorChan <-chan error,
+) (toolCallsExecuted bool, assistantMessage *adk.Message, toolResultMessages []adk.Message, inputErr *InputRequiredError, approval *toolApproval) {
+	var fullContent string
+	toolCallAccumulator := make(map[int]*sdk.ChatCompletionMessageToolCall)
+	toolResultMessages = make([]adk.Message, 0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil, nil, nil, nil
+		case streamErr := <-streamErrorChan:
+			if streamErr != nil {
+				s.logger.Error("streaming failed", zap.Error(streamErr))
+				s.emitError(ctx, task, fmt.Sprintf("Streaming failed: %v", streamErr))
+				return false, nil, nil, nil, nil
+			}
+		case streamResp, ok := <-streamResponseChan:
+			if !ok {
+				break
+			}
+
+			if streamResp != nil {
+				recordLLMModel(ctx, task, s.llmClient, streamResp.Model)
+				RecordTokenUsage(ctx, task, streamResp.Usage)
+			}
+
+			if streamResp == nil || len(streamResp.Choices) == 0 {
+				continue
+			}
+
+			choice := streamResp.Choices[0]
+
+			if choice.Delta.Content != "" {
+				fullContent += choice.Delta.Content
+				_ = s.emitter.EmitChunk(ctx, choice.Delta.Content)
+			}
+
+			for _, toolCallChunk := range choice.Delta.ToolCalls {
+				if toolCallAccumulator[toolCallChunk.Index] == nil {
+					toolCallAccumulator[toolCallChunk.Index] = &sdk.ChatCompletionMessageToolCall{
+						Type:     "function",
+						Function: sdk.ChatCompletionMessageToolCallFunction{},
+					}
+				}
+
+				toolCall := toolCallAccumulator[toolCallChunk.Index]
+				if toolCallChunk.ID != "" {
+					toolCall.Id = toolCallChunk.ID
+				}
+				if toolCallChunk.Function.Name != "" {
+					toolCall.Function.Name = toolCallChunk.Function.Name
+				}
+				if toolCallChunk.Function.Arguments != "" {
+					toolCall.Function.Arguments += toolCallChunk.Function.Arguments
+				}
+			}
+
+			if choice.FinishReason == "" {
+				continue
+			}
+
+			assistantMessage = &adk.Message{
+				Kind:      "message",
+				MessageID: fmt.Sprintf("assistant-%s-%d", task.ID, iteration),
+				Role:      "assistant",
+				Parts:     make([]adk.Part, 0),
+				TaskID:    &task.ID,
+				ContextID: &task.ContextID,
+			}
+
+			if fullContent != "" {
+				assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
+					"kind": "text",
+					"text": fullContent,
+				})
+			}
+
+			if len(toolCallAccumulator) == 0 {
+				return false, assistantMessage, toolResultMessages, nil, nil
+			}
+
+			toolCalls := orderedToolCalls(toolCallAccumulator)
+			assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
+				"kind": "data",
+				"data": map[string]interface{}{
+					"tool_calls": toolCalls,
+				},
+			})
+
+			if approval := newToolApproval(s.toolBox, fullContent, toolCalls); approval != nil {
+				return false, nil, nil, nil, approval
+			}
+
+			toolResultMessages, inputErr = s.executeToolCalls(ctx, task, toolCalls, nil)
+			return true, assistantMessage, toolResultMessages, inputErr, nil
+		}
+	}
+}
+
+// executeToolCalls executes the tool calls of one turn and streams their results in the order of the tool calls
+// Calls the user rejected are not executed, the decisions of the user are recorded in the tool results
+func (s *agentStreamer) executeToolCalls(
+	ctx context.Context,
+	task *adk.Task,
+	toolCalls []sdk.ChatCompletionMessageToolCall,
+	decisions map[string]toolApprovalDecision,
+) ([]adk.Message, *InputRequiredError) {
+	results := make([]*adk.Message, len(toolCalls))
+	errs := make([]error, len(toolCalls))
+	ctx = withContextID(withTaskID(ctx, task.ID), task.ContextID)
+	runToolCalls(ctx, s.toolBox, toolCalls, s.maxParallelToolCalls, func(ctx context.Context, index int, toolCall sdk.ChatCompletionMessageToolCall) {
+		if decision, exists := decisions[toolCall.Id]; exists && !decision.approved {
+			s.logger.Info("tool call rejected by the user", zap.String("function", toolCall.Function.Name))
+			s.emitToolExecution(ctx, task, toolCall.Function.Name, "rejected")
+			results[index] = s.toolResultMessage(task, toolCall, toolRejectedResult(toolCall.Function.Name))
+		} else {
+			results[index], errs[index] = s.executeToolCall(ctx, task, toolCall)
+		}
+		recordToolApproval(results[index], decisions, toolCall.Id)
+	})
+
+	toolResultMessages := make([]adk.Message, 0, len(results))
+	for _, toolResultMessage := range results {
+		if toolResultMessage == nil {
+			continue
+		}
+
+		_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+			State:   adk.TaskStateWorking,
+			Message: toolResultMessage,
+		}, false)
+
+		toolResultMessages = append(toolResultMessages, *toolResultMessage)
+	}
+
+	return toolResultMessages, firstInputRequired(errs)
+}
+
+// executeToolCall executes a single streamed tool call and reports its progress, it returns the tool result of every call with an ID,
+// failed calls included, so that the LLM gets an answer to each of its tool calls
+// The returned error is the error of the tool execution, if any
+func (s *agentStreamer) executeToolCall(ctx context.Context, task *adk.Task, toolCall sdk.ChatCompletionMessageToolCall) (*adk.Message, error) {
+	if toolCall.Id == "" {
+		return nil, nil
+	}
+
+	if err := validateToolCall(toolCall); err != nil {
+		s.logger.Error("invalid tool call", zap.Error(err), zap.String("tool_call_id", toolCall.Id))
+		return s.toolResultMessage(task, toolCall, toolErrorResult(err)), err
+	}
+
+	s.emitToolExecution(ctx, task, toolCall.Function.Name, "started")
+
+	if s.toolBox == nil {
+		err := fmt.Errorf("no toolbox available to execute tool %s", toolCall.Function.Name)
+		s.logger.Error("no toolbox available for tool execution", zap.String("function", toolCall.Function.Name))
+		s.emitToolExecution(ctx, task, toolCall.Function.Name, "failed")
+		return s.toolResultMessage(task, toolCall, toolErrorResult(err)), err
+	}
+
+	argsMap, err := ParseToolArguments(toolCall.Function.Name, toolCall.Function.Arguments)
+	if err != nil {
+		s.logger.Error("failed to parse tool arguments", zap.Error(err), zap.String("function", toolCall.Function.Name))
+		recordToolCallFailure(ctx, s.toolBox, toolCall.Function.Name, err)
+		s.emitToolExecution(ctx, task, toolCall.Function.Name, "failed")
+		return s.toolResultMessage(task, toolCall, toolErrorResult(err)), err
+	}
+
+	toolResult, err := s.toolBox.ExecuteTool(ctx, toolCall.Function.Name, argsMap)
+	if err != nil {
+		s.logger.Error("tool execution failed", zap.Error(err), zap.String("function", toolCall.Function.Name))
+		s.emitToolExecution(ctx, task, toolCall.Function.Name, "failed")
+		return s.toolResultMessage(task, toolCall, toolErrorResult(err)), err
+	}
+
+	s.logger.Info("tool executed successfully", zap.String("function", toolCall.Function.Name))
+
+	s.emitToolExecution(ctx, task, toolCall.Function.Name, "completed")
+
+	return s.toolResultMessage(task, toolCall, toolResult), nil
+}
+
+// toolResultMessage creates the message returning the result of a tool call to the LLM
+func (s *agentStreamer) toolResultMessage(task *adk.Task, toolCall sdk.ChatCompletionMessageToolCall, toolResult string) *adk.Message {
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: fmt.Sprintf("tool-result-%s", toolCall.Id),
+		Role:      "tool",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "data",
+				"data": map[string]interface{}{
+					"tool_call_id": toolCall.Id,
+					"result":       toolResult,
+				},
+			},
+		},
+		TaskID:    &task.ID,
+		ContextID: &task.ContextID,
+	}
+}
+
+// emitToolExecution emits the execution status of a tool
+func (s *agentStreamer) emitToolExecution(ctx context.Context, task *adk.Task, toolName string, status string) {
+	_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+		State: adk.TaskStateWorking,
+		Message: &adk.Message{
+			Kind:      "message",
+			MessageID: fmt.Sprintf("tool-status-%s-%s", task.ID, status),
+			Role:      "assistant",
+			Parts: []adk.Part{
+				map[string]interface{}{
+					"kind": "data",
+					"data": map[string]interface{}{
+						"tool_name": toolName,
+						"status":    status,
+					},
+				},
+			},
+			TaskID:    &task.ID,
+			ContextID: &task.ContextID,
+		},
+	}, false)
+}
+
+// emitInputRequired moves the task to the input-required state and ends the stream asking the user for input
+func (s *agentStreamer) emitInputRequired(ctx context.Context, task *adk.Task, prompt string) {
+	message := &adk.Message{
+		Kind:      "message",
+		MessageID: fmt.Sprintf("input-required-%s", task.ID),
+		Role:      "assistant",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": prompt,
+			},
+		},
+		TaskID:    &task.ID,
+		ContextID: &task.ContextID,
+	}
+
+	task.History = append(task.History, *message)
+	task.Status.State = adk.TaskStateInputRequired
+	task.Status.Message = message
+
+	s.logger.Info("streaming task requires user input",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID))
+
+	_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+		State:   adk.TaskStateInputRequired,
+		Message: message,
+	}, true)
+}
+
+// resumeToolApproval executes the tool calls that waited for the approval of the user and records them in the task history.
+// It returns false if the task stops, either to ask again for a decision or because a tool requires input.
+func (s *agentStreamer) resumeToolApproval(ctx context.Context, task *adk.Task, message *adk.Message, approval *toolApproval) bool {
+	decisions, ok := approval.decide(message)
+	if !ok {
+		s.logger.Info("reply does not decide on the pending tool calls, asking again",
+			zap.String("task_id", task.ID),
+			zap.String("context_id", task.ContextID))
+		s.emitToolApprovalRequired(ctx, task, approval)
+		return false
+	}
+	approval.resolve(ctx, task)
+
+	s.logger.Info("resuming tool calls after approval",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID),
+		zap.Int("count", len(approval.ToolCalls)))
+
+	toolCalls := approval.apply(decisions)
+	assistantMessage := adk.Message{
+		Kind:      "message",
+		MessageID: "assistant-" + task.ID + "-approved",
+		Role:      "assistant",
+		Parts:     make([]adk.Part, 0, 2),
+		TaskID:    &task.ID,
+		ContextID: &task.ContextID,
+	}
+	if approval.Content != "" {
+		assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
+			"kind": "text",
+			"text": approval.Content,
+		})
+	}
+	assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
+		"kind": "data",
+		"data": map[string]interface{}{
+			"tool_calls": toolCalls,
+		},
+	})
+	task.History = append(task.History, assistantMessage)
+
+	toolResultMessages, inputErr := s.executeToolCalls(ctx, task, toolCalls, decisions)
+	task.History = append(task.History, toolResultMessages...)
+
+	if inputErr != nil {
+		s.emitInputRequired(ctx, task, inputErr.Message)
+		return false
+	}
+	return true
+}
+
+// emitToolApprovalRequired pauses the task until the user decides on the pending tool calls
+func (s *agentStreamer) emitToolApprovalRequired(ctx context.Context, task *adk.Task, approval *toolApproval) {
+	message := approval.request(ctx, task)
+
+	s.logger.Info("streaming task tool calls require approval",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID),
+		zap.Strings("tool_call_ids", approval.Pending))
+
+	_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+		State:   adk.TaskStateInputRequired,
+		Message: message,
+	}, true)
+}
+
+// emitError ends the stream with a failed status carrying the error message
+func (s *agentStreamer) emitError(ctx context.Context, task *adk.Task, errorMsg string) {
+	_ = s.emitter.EmitStatus(ctx, adk.TaskStatus{
+		State: adk.TaskStateFailed,
+		Message: &adk.Message{
+			Kind:      "message",
+			MessageID: fmt.Sprintf("error-%s", task.ID),
+			Role:      "assistant",
+			Parts: []adk.Part{
+				map[string]interface{}{
+					"kind": "text",
+					"text": errorMsg,
+				},
+			},
+			TaskID:    &task.ID,
+			ContextID: &task.ContextID,
+		},
+	}, true)
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	sdk "github.com/inference-gateway/sdk"
+)
+
+// MetadataPendingToolApprovalKey is the task metadata key holding the tool calls waiting for the approval of the user
+const MetadataPendingToolApprovalKey = "pendingToolApproval"
+
+// ToolApprovalDataType is the type of the data part describing the tool calls waiting for approval
+const ToolApprovalDataType = "tool_approval"
+
+// Decisions the user can reply with to a tool approval request
+const (
+	ToolApprovalApprove = "approve"
+	ToolApprovalReject  = "reject"
+)
+
+// ApprovalRequiredTool is implemented by tools that must be approved by the user before they are executed
+type ApprovalRequiredTool interface {
+	// RequiresApproval checks if the user must approve calls to the tool
+	RequiresApproval() bool
+}
+
+// approvalToolBox is implemented by toolboxes that know which of their tools must be approved by the user
+type approvalToolBox interface {
+	RequiresApproval(toolName string) bool
+}
+
+// toolApproval holds the tool calls of an assistant turn while some of them wait for the approval of the user
+type toolApproval struct {
+	Content   string                              `json:"content,omitempty"`
+	ToolCalls []sdk.ChatCompletionMessageToolCall `json:"tool_calls"`
+	Pending   []string                            `json:"pending"`
+}
+
+// toolApprovalDecision is the decision of the user on a tool call, approved calls may carry edited arguments
+type toolApprovalDecision struct {
+	approved  bool
+	arguments map[string]interface{}
+}
+
+// String returns the decision as recorded in the tool result
+func (d toolApprovalDecision) String() string {
+	switch {
+	case !d.approved:
+		return "rejected"
+	case d.arguments != nil:
+		return "edited"
+	default:
+		return "approved"
+	}
+}
+
+// newToolApproval returns the approval request for the tool calls, or nil if none of them requires approval
+func newToolApproval(toolBox ToolBox, content string, toolCalls []sdk.ChatCompletionMessageToolCall) *toolApproval {
+	approvals, ok := toolBox.(approvalToolBox)
+	if !ok {
+		return nil
+	}
+
+	var pending []string
+	for _, toolCall := range toolCalls {
+		if approvals.RequiresApproval(toolCall.Function.Name) {
+			pending = append(pending, toolCall.Id)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return &toolApproval{Content: content, ToolCalls: toolCalls, Pending: pending}
+}
+
+// pendingToolApproval returns the tool approval the task is waiting for, if any
+func pendingToolApproval(task *adk.Task) (*toolApproval, bool) {
+	value, exists := task.Metadata[MetadataPendingToolApprovalKey]
+	if !exists || value == nil {
+		return nil, false
+	}
+	if approval, ok := value.(*toolApproval); ok {
+		return approval, true
+	}
+
+	// metadata restored from storage holds the decoded JSON of the approval
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var approval toolApproval
+	if err := json.Unmarshal(data, &approval); err != nil || len(approval.Pending) == 0 {
+		return nil, false
+	}
+	return &approval, true
+}
+
+// request moves the task to the input-required state until the user decides on the pending tool calls
+func (a *toolApproval) request(ctx context.Context, task *adk.Task) *adk.Message {
+	SetTaskMetadata(ctx, task, MetadataPendingToolApprovalKey, a)
+
+	message := &adk.Message{
+		Kind:      "message",
+		MessageID: "tool-approval-" + task.ID,
+		Role:      "assistant",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": a.prompt(),
+			},
+			map[string]interface{}{
+				"kind": "data",
+				"data": map[string]interface{}{
+					"type":       ToolApprovalDataType,
+					"tool_calls": a.pendingCalls(),
+				},
+			},
+		},
+	}
+
+	task.History = append(task.History, *message)
+	task.Status.State = adk.TaskStateInputRequired
+	task.Status.Message = message
+	return message
+}
+
+// resolve clears the pending approval of the task
+func (a *toolApproval) resolve(ctx context.Context, task *adk.Task) {
+	SetTaskMetadata(ctx, task, MetadataPendingToolApprovalKey, nil)
+}
+
+// prompt returns the question asked to the user
+func (a *toolApproval) prompt() string {
+	names := make([]string, 0, len(a.Pending))
+	for _, toolCall := range a.ToolCalls {
+		if a.isPending(toolCall.Id) {
+			names = append(names, toolCall.Function.Name)
+		}
+	}
+	return fmt.Sprintf("Approval required to call %s. Reply with %s or %s, or send edited arguments.",
+		strings.Join(names, ", "), ToolApprovalApprove, ToolApprovalReject)
+}
+
+// pendingCalls describes the tool calls waiting for approval
+func (a *toolApproval) pendingCalls() []interface{} {
+	calls := make([]interface{}, 0, len(a.Pending))
+	for _, toolCall := range a.ToolCalls {
+		if !a.isPending(toolCall.Id) {
+			continue
+		}
+
+		var arguments interface{} = toolCall.Function.Arguments
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &parsed); err == nil {
+			arguments = parsed
+		}
+
+		calls = append(calls, map[string]interface{}{
+			"tool_call_id": toolCall.Id,
+			"tool_name":    toolCall.Function.Name,
+			"arguments":    arguments,
+		})
+	}
+	return calls
+}
+
+func (a *toolApproval) isPending(toolCallID string) bool {
+	for _, id := range a.Pending {
+		if id == toolCallID {
+			return true
+		}
+	}
+	return false
+}
+
+// decide reads the decisions of the user from the reply, it returns false unless every pending call has a decision.
+// A data part decides on the call with its tool_call_id, or on all undecided calls without one.
+// A text reply approves or rejects all calls.
+func (a *toolApproval) decide(message *adk.Message) (map[string]toolApprovalDecision, bool) {
+	if message == nil {
+		return nil, false
+	}
+
+	decisions := make(map[string]toolApprovalDecision)
+	var fallback *toolApprovalDecision
+	for _, part := range message.Parts {
+		partMap, ok := part.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch partMap["kind"] {
+		case "text":
+			text, _ := partMap["text"].(string)
+			if decision, ok := parseToolApprovalText(text); ok && fallback == nil {
+				fallback = &decision
+			}
+		case "data":
+			data, _ := partMap["data"].(map[string]interface{})
+			decision, ok := parseToolApprovalData(data)
+			if !ok {
+				continue
+			}
+			if id, _ := data["tool_call_id"].(string); id != "" {
+				if a.isPending(id) {
+					decisions[id] = decision
+				}
+				continue
+			}
+			fallback = &decision
+		}
+	}
+
+	for _, id := range a.Pending {
+		if _, decided := decisions[id]; decided {
+			continue
+		}
+		if fallback == nil {
+			return nil, false
+		}
+		decisions[id] = *fallback
+	}
+	return decisions, true
+}
+
+// apply returns the tool calls with the arguments edited by the user
+func (a *toolApproval) apply(decisions map[string]toolApprovalDecision) []sdk.ChatCompletionMessageToolCall {
+	toolCalls := make([]sdk.ChatCompletionMessageToolCall, len(a.ToolCalls))
+	copy(toolCalls, a.ToolCalls)
+
+	for i, toolCall := range toolCalls {
+		decision, exists := decisions[toolCall.Id]
+		if !exists || decision.arguments == nil {
+			continue
+		}
+		arguments, err := json.Marshal(decision.arguments)
+		if err != nil {
+			continue
+		}
+		toolCalls[i].Function.Arguments = string(arguments)
+	}
+	return toolCalls
+}
+
+// parseToolApprovalText reads a decision from a text reply such as "approve" or "no"
+func parseToolApprovalText(text string) (toolApprovalDecision, bool) {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(text), ".!")) {
+	case ToolApprovalApprove, "approved", "yes", "y", "ok":
+		return toolApprovalDecision{approved: true}, true
+	case ToolApprovalReject, "rejected", "deny", "no", "n":
+		return toolApprovalDecision{approved: false}, true
+	}
+	return toolApprovalDecision{}, false
+}
+
+// parseToolApprovalData reads a decision from a data part, edited arguments imply approval
+func parseToolApprovalData(data map[string]interface{}) (toolApprovalDecision, bool) {
+	if data == nil {
+		return toolApprovalDecision{}, false
+	}
+
+	arguments, hasArguments := data["arguments"].(map[string]interface{})
+	decision, _ := data["decision"].(string)
+	switch {
+	case decision == ToolApprovalReject:
+		return toolApprovalDecision{approved: false}, true
+	case hasArguments:
+		return toolApprovalDecision{approved: true, arguments: arguments}, true
+	case decision == ToolApprovalApprove:
+		return toolApprovalDecision{approved: true}, true
+	}
+	return toolApprovalDecision{}, false
+}
+
+// toolRejectedResult returns the tool result reported to the LLM for a call the user rejected
+func toolRejectedResult(toolName string) string {
+	return fmt.Sprintf("The user rejected the call to %s", toolName)
+}
+
+// recordToolApproval adds the decision of the user on the tool call to its result message
+func recordToolApproval(message *adk.Message, decisions map[string]toolApprovalDecision, toolCallID string) {
+	decision, exists := decisions[toolCallID]
+	if message == nil || !exists {
+		return
+	}
+	for _, part := range message.Parts {
+		partMap, ok := part.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if data, ok := partMap["data"].(map[string]interface{}); ok {
+			data["approval"] = decision.String()
+		}
+	}
+}
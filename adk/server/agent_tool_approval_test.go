@@ -0,0 +1,177 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func sendEmailResponse() *sdk.CreateChatCompletionResponse {
+	toolCalls := []sdk.ChatCompletionMessageToolCall{
+		{
+			Id:       "call-email",
+			Type:     "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{Name: "send_email", Arguments: `{"to":"alice@example.com"}`},
+		},
+		{
+			Id:       "call-lookup",
+			Type:     "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{Name: "lookup_contact", Arguments: `{}`},
+		},
+	}
+	return &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{
+			{Message: sdk.Message{Role: sdk.Assistant, Content: "Sending the email.", ToolCalls: &toolCalls}},
+		},
+	}
+}
+
+func toolResultData(task *adk.Task, toolCallID string) map[string]interface{} {
+	for _, message := range task.History {
+		if message.Role != "tool" {
+			continue
+		}
+		data := message.Parts[0].(map[string]interface{})["data"].(map[string]interface{})
+		if data["tool_call_id"] == toolCallID {
+			return data
+		}
+	}
+	return nil
+}
+
+func TestDefaultOpenAICompatibleAgent_ToolApproval(t *testing.T) {
+	tests := []struct {
+		name               string
+		reply              adk.Part
+		expectedState      adk.TaskState
+		expectedRecipient  string
+		expectedApproval   string
+		expectedResult     string
+		expectedLLMCalls   int
+		expectedEmailsSent int
+	}{
+		{
+			name:               "approved by text",
+			reply:              map[string]interface{}{"kind": "text", "text": "Approve"},
+			expectedState:      adk.TaskStateCompleted,
+			expectedRecipient:  "alice@example.com",
+			expectedApproval:   "approved",
+			expectedResult:     "sent to alice@example.com",
+			expectedLLMCalls:   2,
+			expectedEmailsSent: 1,
+		},
+		{
+			name:               "edited arguments",
+			reply:              map[string]interface{}{"kind": "data", "data": map[string]interface{}{"tool_call_id": "call-email", "arguments": map[string]interface{}{"to": "bob@example.com"}}},
+			expectedState:      adk.TaskStateCompleted,
+			expectedRecipient:  "bob@example.com",
+			expectedApproval:   "edited",
+			expectedResult:     "sent to bob@example.com",
+			expectedLLMCalls:   2,
+			expectedEmailsSent: 1,
+		},
+		{
+			name:             "rejected",
+			reply:            map[string]interface{}{"kind": "data", "data": map[string]interface{}{"decision": server.ToolApprovalReject}},
+			expectedState:    adk.TaskStateCompleted,
+			expectedApproval: "rejected",
+			expectedResult:   "The user rejected the call to send_email",
+			expectedLLMCalls: 2,
+		},
+		{
+			name:             "unclear reply asks again",
+			reply:            map[string]interface{}{"kind": "text", "text": "maybe later"},
+			expectedState:    adk.TaskStateInputRequired,
+			expectedLLMCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recipients []string
+			toolBox := server.NewDefaultToolBox()
+			toolBox.AddTool(server.NewBasicTool("send_email", "Send an email", map[string]interface{}{"type": "object"},
+				func(ctx context.Context, args map[string]interface{}) (string, error) {
+					recipient, _ := args["to"].(string)
+					recipients = append(recipients, recipient)
+					return "sent to " + recipient, nil
+				},
+				server.WithApprovalRequired()))
+			toolBox.AddTool(server.NewBasicTool("lookup_contact", "Look up a contact", map[string]interface{}{"type": "object"},
+				func(ctx context.Context, args map[string]interface{}) (string, error) {
+					return "alice@example.com", nil
+				}))
+
+			llmClient := &mocks.FakeLLMClient{}
+			llmClient.CreateChatCompletionReturnsOnCall(0, sendEmailResponse(), nil)
+			llmClient.CreateChatCompletionReturnsOnCall(1, completionResponse("Done."), nil)
+
+			agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithToolBox(toolBox).Build()
+			require.NoError(t, err)
+
+			taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+			messageHandler := server.NewDefaultMessageHandlerWithAgent(zap.NewNop(), taskManager, agent, &config.Config{})
+
+			task, err := messageHandler.HandleMessageSend(context.Background(), adk.MessageSendParams{
+				Message: *routerMessage("Email Alice", nil),
+			})
+			require.NoError(t, err)
+
+			task, err = agent.ProcessTask(context.Background(), task, task.Status.Message)
+			require.NoError(t, err)
+			require.Equal(t, adk.TaskStateInputRequired, task.Status.State)
+			assert.Empty(t, recipients, "the tool waits for approval")
+			assert.Nil(t, toolResultData(task, "call-lookup"), "the whole turn waits for approval")
+
+			request := task.Status.Message.Parts[1].(map[string]interface{})["data"].(map[string]interface{})
+			assert.Equal(t, server.ToolApprovalDataType, request["type"])
+			pending := request["tool_calls"].([]interface{})
+			require.Len(t, pending, 1)
+			assert.Equal(t, "send_email", pending[0].(map[string]interface{})["tool_name"])
+			assert.Equal(t, map[string]interface{}{"to": "alice@example.com"}, pending[0].(map[string]interface{})["arguments"])
+
+			reply := routerMessage("", nil)
+			reply.Parts = []adk.Part{tt.reply}
+			reply.TaskID = &task.ID
+			resumed, err := messageHandler.HandleMessageSend(context.Background(), adk.MessageSendParams{Message: *reply})
+			require.NoError(t, err)
+			require.Equal(t, task.ID, resumed.ID, "the reply resumes the task")
+
+			result, err := agent.ProcessTask(context.Background(), resumed, resumed.Status.Message)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedState, result.Status.State)
+			assert.Equal(t, tt.expectedLLMCalls, llmClient.CreateChatCompletionCallCount())
+			assert.Len(t, recipients, tt.expectedEmailsSent)
+			if tt.expectedRecipient != "" {
+				assert.Equal(t, tt.expectedRecipient, recipients[0])
+			}
+			if tt.expectedState == adk.TaskStateInputRequired {
+				assert.Contains(t, result.Metadata, server.MetadataPendingToolApprovalKey)
+				return
+			}
+
+			assert.NotContains(t, result.Metadata, server.MetadataPendingToolApprovalKey)
+			assert.Contains(t, result.History, *reply, "the decision is recorded in the history")
+
+			emailResult := toolResultData(result, "call-email")
+			require.NotNil(t, emailResult)
+			assert.Equal(t, tt.expectedApproval, emailResult["approval"])
+			assert.Equal(t, tt.expectedResult, emailResult["result"])
+			assert.Equal(t, "alice@example.com", toolResultData(result, "call-lookup")["result"])
+
+			_, messages, _ := llmClient.CreateChatCompletionArgsForCall(1)
+			last := messages[len(messages)-1]
+			assert.Equal(t, sdk.Tool, last.Role, "the tool results follow the assistant tool calls")
+			assert.Equal(t, sdk.Assistant, messages[len(messages)-3].Role)
+		})
+	}
+}
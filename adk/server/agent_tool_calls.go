@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	sdk "github.com/inference-gateway/sdk"
+)
+
+// DefaultMaxParallelToolCalls is the number of tool calls of one LLM turn executed concurrently by default,
+// tool calls run one after another unless a higher limit is configured
+const DefaultMaxParallelToolCalls = 1
+
+// SequentialTool is implemented by tools with side effects that must not run concurrently with other tool calls
+type SequentialTool interface {
+	// IsSequential checks if the tool must run on its own
+	IsSequential() bool
+}
+
+// sequentialToolBox is implemented by toolboxes that know which of their tools must run on their own
+type sequentialToolBox interface {
+	IsSequential(toolName string) bool
+}
+
+// isSequentialToolCall checks if the tool call must run on its own
+func isSequentialToolCall(toolBox ToolBox, toolCall sdk.ChatCompletionMessageToolCall) bool {
+	if sequential, ok := toolBox.(sequentialToolBox); ok {
+		return sequential.IsSequential(toolCall.Function.Name)
+	}
+	return false
+}
+
+// runToolCalls calls execute for every tool call, running up to maxParallel calls concurrently, or the default if not set.
+// A call to a sequential tool waits for all earlier calls and runs alone before later calls start.
+// execute receives the index of the call so callers can collect results in the original order.
+func runToolCalls(ctx context.Context, toolBox ToolBox, toolCalls []sdk.ChatCompletionMessageToolCall, maxParallel int, execute func(ctx context.Context, index int, toolCall sdk.ChatCompletionMessageToolCall)) {
+	if maxParallel < 1 {
+		maxParallel = DefaultMaxParallelToolCalls
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxParallel)
+
+	for i, toolCall := range toolCalls {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if maxParallel == 1 || isSequentialToolCall(toolBox, toolCall) {
+			wg.Wait()
+			execute(ctx, i, toolCall)
+			continue
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(index int, toolCall sdk.ChatCompletionMessageToolCall) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			execute(ctx, index, toolCall)
+		}(i, toolCall)
+	}
+
+	wg.Wait()
+}
+
+// validateToolCall checks that the tool call names a function the toolbox can execute
+func validateToolCall(toolCall sdk.ChatCompletionMessageToolCall) error {
+	if toolCall.Type != "function" {
+		return fmt.Errorf("unsupported tool call type %q", toolCall.Type)
+	}
+	if toolCall.Function.Name == "" {
+		return fmt.Errorf("tool call has no function name")
+	}
+	return nil
+}
+
+// toolCallFailureRecorder is implemented by toolboxes that count failed tool calls
+type toolCallFailureRecorder interface {
+	recordToolCallFailure(ctx context.Context, toolName string, err error)
+}
+
+// recordToolCallFailure counts a tool call that failed before reaching the toolbox, if the toolbox counts failures
+func recordToolCallFailure(ctx context.Context, toolBox ToolBox, toolName string, err error) {
+	if recorder, ok := toolBox.(toolCallFailureRecorder); ok {
+		recorder.recordToolCallFailure(ctx, toolName, err)
+	}
+}
+
+// toolErrorResult returns the tool result reported to the LLM for a failed tool call
+func toolErrorResult(err error) string {
+	var validationErr *ToolArgumentsValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.ToolResult()
+	}
+	var inputErr *InputRequiredError
+	if errors.As(err, &inputErr) {
+		return fmt.Sprintf("The user was asked for more input: %s", inputErr.Message)
+	}
+	return fmt.Sprintf("Error executing tool: %v", err)
+}
+
+// firstInputRequired returns the first input request among the errors of the tool calls, in tool call order
+func firstInputRequired(errs []error) *InputRequiredError {
+	for _, err := range errs {
+		var inputErr *InputRequiredError
+		if errors.As(err, &inputErr) {
+			return inputErr
+		}
+	}
+	return nil
+}
+
+// orderedToolCalls returns the tool calls accumulated from a stream in the order the LLM emitted them
+func orderedToolCalls(accumulator map[int]*sdk.ChatCompletionMessageToolCall) []sdk.ChatCompletionMessageToolCall {
+	indexes := make([]int, 0, len(accumulator))
+	for index := range accumulator {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	toolCalls := make([]sdk.ChatCompletionMessageToolCall, 0, len(indexes))
+	for _, index := range indexes {
+		toolCalls = append(toolCalls, *accumulator[index])
+	}
+	return toolCalls
+}
@@ -0,0 +1,258 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// concurrencyTracker records how many tools run at the same time
+type concurrencyTracker struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (c *concurrencyTracker) enter() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+}
+
+func (c *concurrencyTracker) leave() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running--
+}
+
+func newTrackedTool(name string, tracker *concurrencyTracker, delay time.Duration, opts ...server.BasicToolOption) server.Tool {
+	return server.NewBasicTool(
+		name,
+		"A tracked test tool",
+		map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			tracker.enter()
+			defer tracker.leave()
+			time.Sleep(delay)
+			return "result of " + name, nil
+		},
+		opts...,
+	)
+}
+
+func newToolCallResponse(names ...string) *sdk.CreateChatCompletionResponse {
+	toolCalls := make([]sdk.ChatCompletionMessageToolCall, 0, len(names))
+	for i, name := range names {
+		toolCalls = append(toolCalls, sdk.ChatCompletionMessageToolCall{
+			Id:   fmt.Sprintf("call-%d", i),
+			Type: "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{
+				Name:      name,
+				Arguments: "{}",
+			},
+		})
+	}
+
+	return &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{
+			{Message: sdk.Message{Role: sdk.Assistant, ToolCalls: &toolCalls}},
+		},
+	}
+}
+
+// toolResultCallIDs returns the tool call IDs answered by the tool result messages
+func toolResultCallIDs(messages []adk.Message) []string {
+	var ids []string
+	for _, message := range messages {
+		if message.Role != "tool" {
+			continue
+		}
+		part := message.Parts[0].(map[string]interface{})
+		data := part["data"].(map[string]interface{})
+		ids = append(ids, data["tool_call_id"].(string))
+	}
+	return ids
+}
+
+// toolResultsOf returns the results of the tool result messages
+func toolResultsOf(messages []adk.Message) []string {
+	var results []string
+	for _, message := range messages {
+		if message.Role != "tool" {
+			continue
+		}
+		part := message.Parts[0].(map[string]interface{})
+		data := part["data"].(map[string]interface{})
+		results = append(results, data["result"].(string))
+	}
+	return results
+}
+
+func toolResultNames(task *adk.Task) []string {
+	var names []string
+	for _, message := range task.History {
+		if message.Role != "tool" {
+			continue
+		}
+		part := message.Parts[0].(map[string]interface{})
+		data := part["data"].(map[string]interface{})
+		names = append(names, data["tool_name"].(string))
+	}
+	return names
+}
+
+func TestDefaultOpenAICompatibleAgent_ExecutesToolCallsInParallel(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxParallel  int
+		sequential   []string
+		expectedPeak int
+	}{
+		{name: "runs independent calls concurrently", maxParallel: 4, expectedPeak: 4},
+		{name: "respects the parallelism limit", maxParallel: 2, expectedPeak: 2},
+		{name: "runs calls one after another with a limit of one", maxParallel: 1, expectedPeak: 1},
+		{name: "runs calls one after another by default", expectedPeak: 1},
+		{name: "runs sequential tools on their own", maxParallel: 4, sequential: []string{"tool_b", "tool_c"}, expectedPeak: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &concurrencyTracker{}
+			toolBox := server.NewDefaultToolBox()
+			names := []string{"tool_a", "tool_b", "tool_c", "tool_d"}
+			for _, name := range names {
+				var opts []server.BasicToolOption
+				for _, sequential := range tt.sequential {
+					if sequential == name {
+						opts = append(opts, server.WithSequential())
+					}
+				}
+				toolBox.AddTool(newTrackedTool(name, tracker, 50*time.Millisecond, opts...))
+			}
+
+			llmClient := &mocks.FakeLLMClient{}
+			llmClient.CreateChatCompletionReturnsOnCall(0, newToolCallResponse("tool_d", "tool_b", "tool_a", "tool_c"), nil)
+			llmClient.CreateChatCompletionReturnsOnCall(1, &sdk.CreateChatCompletionResponse{
+				Choices: []sdk.ChatCompletionChoice{
+					{Message: sdk.Message{Role: sdk.Assistant, Content: "done"}},
+				},
+			}, nil)
+
+			agent, err := server.NewAgentBuilder(zap.NewNop()).
+				WithLLMClient(llmClient).
+				WithToolBox(toolBox).
+				WithMaxParallelToolCalls(tt.maxParallel).
+				Build()
+			require.NoError(t, err)
+
+			task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+			message := &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"}
+
+			result, err := agent.ProcessTask(context.Background(), task, message)
+			require.NoError(t, err)
+
+			assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+			assert.Equal(t, []string{"tool_d", "tool_b", "tool_a", "tool_c"}, toolResultNames(result), "results keep the tool call order")
+			assert.Equal(t, tt.expectedPeak, tracker.peak)
+		})
+	}
+}
+
+func TestDefaultOpenAICompatibleAgent_SequentialToolRunsAlone(t *testing.T) {
+	var running atomic.Int32
+	var overlapped atomic.Bool
+
+	toolBox := server.NewDefaultToolBox()
+	for _, name := range []string{"read_a", "read_b"} {
+		toolBox.AddTool(server.NewBasicTool(name, "A read-only tool", map[string]interface{}{"type": "object"},
+			func(ctx context.Context, args map[string]interface{}) (string, error) {
+				running.Add(1)
+				defer running.Add(-1)
+				time.Sleep(30 * time.Millisecond)
+				return "ok", nil
+			}))
+	}
+	toolBox.AddTool(server.NewBasicTool("write", "A tool with side effects", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			if running.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+			defer running.Add(-1)
+			time.Sleep(30 * time.Millisecond)
+			return "written", nil
+		}, server.WithSequential()))
+
+	assert.True(t, toolBox.IsSequential("write"))
+	assert.False(t, toolBox.IsSequential("read_a"))
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, newToolCallResponse("read_a", "write", "read_b"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{
+			{Message: sdk.Message{Role: sdk.Assistant, Content: "done"}},
+		},
+	}, nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(llmClient).
+		WithToolBox(toolBox).
+		WithMaxParallelToolCalls(4).
+		Build()
+	require.NoError(t, err)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	result, err := agent.ProcessTask(context.Background(), task, &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"})
+	require.NoError(t, err)
+
+	assert.False(t, overlapped.Load(), "the sequential tool must not overlap with other calls")
+	assert.Equal(t, []string{"read_a", "write", "read_b"}, toolResultNames(result))
+}
+
+func TestDefaultOpenAICompatibleAgent_AnswersInvalidToolCalls(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(newTrackedTool("tool_a", &concurrencyTracker{}, 0))
+
+	toolCalls := []sdk.ChatCompletionMessageToolCall{
+		{Id: "call-0", Type: "function", Function: sdk.ChatCompletionMessageToolCallFunction{Name: "tool_a", Arguments: "{}"}},
+		{Id: "call-1", Type: "custom", Function: sdk.ChatCompletionMessageToolCallFunction{Name: "tool_a", Arguments: "{}"}},
+		{Id: "call-2", Type: "function", Function: sdk.ChatCompletionMessageToolCallFunction{Arguments: "{}"}},
+	}
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{
+			{Message: sdk.Message{Role: sdk.Assistant, ToolCalls: &toolCalls}},
+		},
+	}, nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{
+			{Message: sdk.Message{Role: sdk.Assistant, Content: "done"}},
+		},
+	}, nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(llmClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	result, err := agent.ProcessTask(context.Background(), task, &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"})
+	require.NoError(t, err)
+
+	assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+	assert.Equal(t, []string{"call-0", "call-1", "call-2"}, toolResultCallIDs(result.History), "every tool call is answered")
+	assert.Equal(t, []string{"tool_a", "tool_a", ""}, toolResultNames(result))
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+)
+
+// RedactedValue replaces the values of redacted tool arguments in logs
+const RedactedValue = "[REDACTED]"
+
+// ToolCallHandler executes a tool call
+type ToolCallHandler func(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error)
+
+// ToolInterceptor wraps the execution of a tool call, it calls next to continue the chain or returns early to skip the tool.
+// Interceptors can change the context and arguments passed to next, or the result and error returned by it.
+type ToolInterceptor func(ctx context.Context, toolName string, arguments map[string]interface{}, next ToolCallHandler) (string, error)
+
+// chainToolInterceptors wraps the handler with the interceptors, the first interceptor runs first
+func chainToolInterceptors(interceptors []ToolInterceptor, handler ToolCallHandler) ToolCallHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
+			return interceptor(ctx, toolName, arguments, next)
+		}
+	}
+	return handler
+}
+
+// NewToolLoggingInterceptor logs the arguments, result size and duration of every tool call.
+// The values of arguments named like one of the redacted keys are replaced, at any depth and ignoring case.
+func NewToolLoggingInterceptor(logger *zap.Logger, redactedKeys ...string) ToolInterceptor {
+	redacted := make(map[string]struct{}, len(redactedKeys))
+	for _, key := range redactedKeys {
+		redacted[strings.ToLower(key)] = struct{}{}
+	}
+
+	return func(ctx context.Context, toolName string, arguments map[string]interface{}, next ToolCallHandler) (string, error) {
+		start := time.Now()
+		result, err := next(ctx, toolName, arguments)
+
+		fields := []zap.Field{
+			zap.String("tool", toolName),
+			zap.String("task_id", taskIDFromContext(ctx)),
+			zap.Any("arguments", redactArguments(arguments, redacted)),
+			zap.Int("result_bytes", len(result)),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			logger.Error("tool call failed", append(fields, zap.Error(err))...)
+			return result, err
+		}
+
+		logger.Info("tool call completed", fields...)
+		return result, nil
+	}
+}
+
+// redactArguments returns a copy of the arguments with the values of the redacted keys replaced
+func redactArguments(arguments map[string]interface{}, redacted map[string]struct{}) map[string]interface{} {
+	if arguments == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		if _, ok := redacted[strings.ToLower(key)]; ok {
+			copied[key] = RedactedValue
+			continue
+		}
+		copied[key] = redactValue(value, redacted)
+	}
+	return copied
+}
+
+func redactValue(value interface{}, redacted map[string]struct{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return redactArguments(typed, redacted)
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for i, item := range typed {
+			copied[i] = redactValue(item, redacted)
+		}
+		return copied
+	default:
+		return value
+	}
+}
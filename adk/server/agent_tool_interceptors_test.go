@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type contextKey string
+
+func TestDefaultToolBox_Interceptors(t *testing.T) {
+	var calls []string
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("search", "Search", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			calls = append(calls, "tool")
+			return "results for " + args["query"].(string) + " as " + ctx.Value(contextKey("user")).(string), nil
+		}))
+
+	trace := func(name string) server.ToolInterceptor {
+		return func(ctx context.Context, toolName string, arguments map[string]interface{}, next server.ToolCallHandler) (string, error) {
+			calls = append(calls, name+" before")
+			result, err := next(ctx, toolName, arguments)
+			calls = append(calls, name+" after")
+			return result, err
+		}
+	}
+	inject := func(ctx context.Context, toolName string, arguments map[string]interface{}, next server.ToolCallHandler) (string, error) {
+		arguments["query"] = "normalized"
+		return next(context.WithValue(ctx, contextKey("user"), "alice"), toolName, arguments)
+	}
+
+	toolBox.Use(trace("outer"), trace("inner"))
+	toolBox.Use(inject)
+
+	result, err := toolBox.ExecuteTool(context.Background(), "search", map[string]interface{}{"query": "raw"})
+	require.NoError(t, err)
+	assert.Equal(t, "results for normalized as alice", result, "interceptors can change the context and arguments")
+	assert.Equal(t, []string{"outer before", "inner before", "tool", "inner after", "outer after"}, calls)
+}
+
+func TestDefaultToolBox_InterceptorShortCircuits(t *testing.T) {
+	executed := false
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("search", "Search", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			executed = true
+			return "fresh", nil
+		}))
+
+	cache := map[string]string{"search": "cached"}
+	toolBox.Use(func(ctx context.Context, toolName string, arguments map[string]interface{}, next server.ToolCallHandler) (string, error) {
+		if result, ok := cache[toolName]; ok {
+			return result, nil
+		}
+		return next(ctx, toolName, arguments)
+	})
+
+	result, err := toolBox.ExecuteTool(context.Background(), "search", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "cached", result)
+	assert.False(t, executed)
+}
+
+func TestToolLoggingInterceptor(t *testing.T) {
+	tests := []struct {
+		name              string
+		executor          func(ctx context.Context, args map[string]interface{}) (string, error)
+		expectedMessage   string
+		expectedArguments map[string]interface{}
+	}{
+		{
+			name: "redacts arguments at any depth",
+			executor: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return "sent", nil
+			},
+			expectedMessage: "tool call completed",
+			expectedArguments: map[string]interface{}{
+				"to":      "bob@example.com",
+				"API_KEY": server.RedactedValue,
+				"auth":    map[string]interface{}{"user": "bob", "password": server.RedactedValue},
+				"headers": []interface{}{map[string]interface{}{"api_key": server.RedactedValue}},
+			},
+		},
+		{
+			name: "logs failures",
+			executor: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				return "", errors.New("smtp unavailable")
+			},
+			expectedMessage: "tool call failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			toolBox := server.NewDefaultToolBox()
+			toolBox.AddTool(server.NewBasicTool("send_email", "Send an email", map[string]interface{}{"type": "object"}, tt.executor))
+			toolBox.Use(server.NewToolLoggingInterceptor(zap.New(core), "api_key", "password"))
+
+			arguments := map[string]interface{}{
+				"to":      "bob@example.com",
+				"API_KEY": "secret",
+				"auth":    map[string]interface{}{"user": "bob", "password": "hunter2"},
+				"headers": []interface{}{map[string]interface{}{"api_key": "secret"}},
+			}
+			_, _ = toolBox.ExecuteTool(context.Background(), "send_email", arguments)
+
+			entries := logs.FilterMessage(tt.expectedMessage).All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, "send_email", entries[0].ContextMap()["tool"])
+			if tt.expectedArguments != nil {
+				assert.Equal(t, tt.expectedArguments, entries[0].ContextMap()["arguments"])
+			}
+			assert.Equal(t, "secret", arguments["API_KEY"], "the arguments passed to the tool are not redacted")
+		})
+	}
+}
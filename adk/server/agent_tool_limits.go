@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+// DefaultToolTimeout is the time a tool call may take before it is stopped by default
+const DefaultToolTimeout = 60 * time.Second
+
+// DefaultMaxToolOutputBytes is the size of the tool output passed to the LLM by default, larger outputs are truncated
+const DefaultMaxToolOutputBytes = 64 * 1024
+
+// Safeguards applied to tool calls, as recorded in the a2a.tool_call_safeguards.total metric
+const (
+	ToolSafeguardTimeout    = "timeout"
+	ToolSafeguardPanic      = "panic"
+	ToolSafeguardTruncated  = "truncated"
+	ToolSafeguardSummarized = "summarized"
+)
+
+// ToolLimits configures the safeguards of tool calls, a zero timeout or output size disables the limit
+type ToolLimits struct {
+	// Timeout is the time a tool call may take, tools implementing TimeoutTool can override it
+	Timeout time.Duration
+
+	// MaxOutputBytes is the size of the tool output passed to the LLM
+	MaxOutputBytes int
+
+	// Summarizer shortens outputs exceeding MaxOutputBytes, outputs are truncated if it is nil or fails
+	Summarizer ToolOutputSummarizer
+}
+
+// DefaultToolLimits returns the limits applied by a new DefaultToolBox
+func DefaultToolLimits() ToolLimits {
+	return ToolLimits{
+		Timeout:        DefaultToolTimeout,
+		MaxOutputBytes: DefaultMaxToolOutputBytes,
+	}
+}
+
+// TimeoutTool is implemented by tools that need a different timeout than the default of the toolbox
+type TimeoutTool interface {
+	// GetTimeout returns the timeout of the tool, zero uses the default of the toolbox
+	GetTimeout() time.Duration
+}
+
+// ToolOutputSummarizer shortens tool outputs that are too large to pass to the LLM
+type ToolOutputSummarizer interface {
+	// SummarizeToolOutput returns a summary of the output of the tool of at most maxBytes
+	SummarizeToolOutput(ctx context.Context, toolName string, output string, maxBytes int) (string, error)
+}
+
+// executeWithLimits executes the tool, stopping it at its timeout and recovering from panics
+// A tool that ignores the cancellation of its context keeps running in the background after its timeout
+func (tb *DefaultToolBox) executeWithLimits(ctx context.Context, tool Tool, arguments map[string]interface{}) (string, error) {
+	toolName := tool.GetName()
+
+	timeout := tb.limits.Timeout
+	if timeoutTool, ok := tool.(TimeoutTool); ok && timeoutTool.GetTimeout() > 0 {
+		timeout = timeoutTool.GetTimeout()
+	}
+
+	execCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				done <- outcome{err: NewToolPanicError(toolName, value)}
+			}
+		}()
+
+		result, err := tool.Execute(execCtx, arguments)
+		done <- outcome{result: result, err: err}
+	}()
+
+	timedOut := func() bool {
+		return ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded)
+	}
+
+	select {
+	case result := <-done:
+		var panicErr *ToolPanicError
+		if errors.As(result.err, &panicErr) {
+			tb.recordToolCallSafeguard(ctx, toolName, ToolSafeguardPanic)
+			return "", result.err
+		}
+		if result.err != nil && timedOut() {
+			tb.recordToolCallSafeguard(ctx, toolName, ToolSafeguardTimeout)
+			return "", NewToolTimeoutError(toolName, timeout)
+		}
+		if result.err != nil {
+			return result.result, result.err
+		}
+		return tb.limitOutput(ctx, toolName, result.result), nil
+	case <-execCtx.Done():
+		if !timedOut() {
+			return "", ctx.Err()
+		}
+		tb.recordToolCallSafeguard(ctx, toolName, ToolSafeguardTimeout)
+		return "", NewToolTimeoutError(toolName, timeout)
+	}
+}
+
+// limitOutput summarizes or truncates outputs exceeding the output limit
+func (tb *DefaultToolBox) limitOutput(ctx context.Context, toolName string, output string) string {
+	maxBytes := tb.limits.MaxOutputBytes
+	if maxBytes <= 0 || len(output) <= maxBytes {
+		return output
+	}
+
+	if tb.limits.Summarizer != nil {
+		summary, err := tb.limits.Summarizer.SummarizeToolOutput(ctx, toolName, output, maxBytes)
+		if err == nil && summary != "" && len(summary) <= maxBytes {
+			tb.recordToolCallSafeguard(ctx, toolName, ToolSafeguardSummarized)
+			return summary
+		}
+	}
+
+	tb.recordToolCallSafeguard(ctx, toolName, ToolSafeguardTruncated)
+	return truncateToolOutput(output, maxBytes)
+}
+
+// recordToolCallSafeguard counts a tool call stopped or shortened by a safeguard
+func (tb *DefaultToolBox) recordToolCallSafeguard(ctx context.Context, toolName string, safeguard string) {
+	if tb.telemetry == nil {
+		return
+	}
+
+	attrs := tb.telemetryAttrs
+	attrs.TaskID = taskIDFromContext(ctx)
+	tb.telemetry.RecordToolCallSafeguard(ctx, attrs, toolName, safeguard)
+}
+
+// truncateToolOutput cuts the output at maxBytes without splitting a character and notes how much was left out
+func truncateToolOutput(output string, maxBytes int) string {
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n[output truncated: %d of %d bytes shown]", output[:cut], cut, len(output))
+}
+
+// LLMToolOutputSummarizer summarizes large tool outputs with an LLM
+type LLMToolOutputSummarizer struct {
+	logger    *zap.Logger
+	llmClient LLMClient
+}
+
+// NewLLMToolOutputSummarizer creates a tool output summarizer using the LLM client
+func NewLLMToolOutputSummarizer(logger *zap.Logger, llmClient LLMClient) *LLMToolOutputSummarizer {
+	return &LLMToolOutputSummarizer{
+		logger:    logger,
+		llmClient: llmClient,
+	}
+}
+
+// SummarizeToolOutput asks the LLM to summarize the output, keeping the details needed to answer the user
+func (s *LLMToolOutputSummarizer) SummarizeToolOutput(ctx context.Context, toolName string, output string, maxBytes int) (string, error) {
+	prompt := fmt.Sprintf("Summarize the following output of the %s tool in at most %d bytes. "+
+		"Keep identifiers, numbers and error messages needed to answer the user. Answer with the summary only.", toolName, maxBytes)
+
+	response, err := s.llmClient.CreateChatCompletion(ctx, []sdk.Message{
+		{Role: sdk.System, Content: prompt},
+		{Role: sdk.User, Content: output},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize tool output: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("failed to summarize tool output: no response received from llm")
+	}
+
+	summary := response.Choices[0].Message.Content
+	s.logger.Debug("tool output summarized",
+		zap.String("tool", toolName),
+		zap.Int("output_bytes", len(output)),
+		zap.Int("summary_bytes", len(summary)))
+	return summary, nil
+}
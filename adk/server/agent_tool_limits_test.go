@@ -0,0 +1,185 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	"github.com/inference-gateway/a2a/adk/server/otel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeToolOutputSummarizer struct {
+	summary string
+	err     error
+}
+
+func (s *fakeToolOutputSummarizer) SummarizeToolOutput(ctx context.Context, toolName string, output string, maxBytes int) (string, error) {
+	return s.summary, s.err
+}
+
+func TestDefaultToolBox_ExecuteToolLimits(t *testing.T) {
+	waitForCancel := func(ctx context.Context, args map[string]interface{}) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	sleep := func(ctx context.Context, args map[string]interface{}) (string, error) {
+		time.Sleep(30 * time.Millisecond)
+		return "done", nil
+	}
+	output := func(text string) func(ctx context.Context, args map[string]interface{}) (string, error) {
+		return func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return text, nil
+		}
+	}
+
+	tests := []struct {
+		name              string
+		limits            server.ToolLimits
+		executor          func(ctx context.Context, args map[string]interface{}) (string, error)
+		opts              []server.BasicToolOption
+		expectedResult    string
+		expectedError     interface{}
+		expectedSafeguard string
+	}{
+		{
+			name:              "default timeout",
+			limits:            server.ToolLimits{Timeout: 10 * time.Millisecond},
+			executor:          waitForCancel,
+			expectedError:     &server.ToolTimeoutError{},
+			expectedSafeguard: server.ToolSafeguardTimeout,
+		},
+		{
+			name:           "per-tool timeout overrides the default",
+			limits:         server.ToolLimits{Timeout: 10 * time.Millisecond},
+			executor:       sleep,
+			opts:           []server.BasicToolOption{server.WithTimeout(time.Second)},
+			expectedResult: "done",
+		},
+		{
+			name:   "panic is recovered",
+			limits: server.DefaultToolLimits(),
+			executor: func(ctx context.Context, args map[string]interface{}) (string, error) {
+				var records map[string]int
+				records["id"]++
+				return "", nil
+			},
+			expectedError:     &server.ToolPanicError{},
+			expectedSafeguard: server.ToolSafeguardPanic,
+		},
+		{
+			name:              "output is truncated at a character boundary",
+			limits:            server.ToolLimits{MaxOutputBytes: 5},
+			executor:          output("abcdéfgh"),
+			expectedResult:    "abcd\n[output truncated: 4 of 9 bytes shown]",
+			expectedSafeguard: server.ToolSafeguardTruncated,
+		},
+		{
+			name:              "output is summarized",
+			limits:            server.ToolLimits{MaxOutputBytes: 5, Summarizer: &fakeToolOutputSummarizer{summary: "short"}},
+			executor:          output("a long output"),
+			expectedResult:    "short",
+			expectedSafeguard: server.ToolSafeguardSummarized,
+		},
+		{
+			name:              "output is truncated when summarizing fails",
+			limits:            server.ToolLimits{MaxOutputBytes: 5, Summarizer: &fakeToolOutputSummarizer{err: errors.New("llm unavailable")}},
+			executor:          output("a long output"),
+			expectedResult:    "a lon\n[output truncated: 5 of 13 bytes shown]",
+			expectedSafeguard: server.ToolSafeguardTruncated,
+		},
+		{
+			name:           "output within the limit",
+			limits:         server.DefaultToolLimits(),
+			executor:       output("small"),
+			expectedResult: "small",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolBox := server.NewDefaultToolBox()
+			toolBox.SetLimits(tt.limits)
+			toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"}, tt.executor, tt.opts...))
+
+			telemetry := &mocks.FakeOpenTelemetry{}
+			toolBox.SetTelemetry(telemetry, otel.TelemetryAttributes{Provider: "openai"})
+
+			result, err := toolBox.ExecuteTool(context.Background(), "lookup", map[string]interface{}{})
+			if tt.expectedError != nil {
+				require.Error(t, err)
+				assert.IsType(t, tt.expectedError, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			if tt.expectedSafeguard == "" {
+				assert.Equal(t, 0, telemetry.RecordToolCallSafeguardCallCount())
+				return
+			}
+			require.Equal(t, 1, telemetry.RecordToolCallSafeguardCallCount())
+			_, attrs, toolName, safeguard := telemetry.RecordToolCallSafeguardArgsForCall(0)
+			assert.Equal(t, "openai", attrs.Provider)
+			assert.Equal(t, "lookup", toolName)
+			assert.Equal(t, tt.expectedSafeguard, safeguard)
+		})
+	}
+}
+
+func TestDefaultToolBox_ExecuteToolCanceled(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := toolBox.ExecuteTool(ctx, "lookup", map[string]interface{}{})
+	assert.ErrorIs(t, err, context.Canceled, "a canceled task is not reported as a timeout")
+}
+
+func TestDefaultOpenAICompatibleAgent_ReturnsToolPanicToLLM(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			panic("index out of range")
+		}))
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, newToolCallResponse("lookup"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, completionResponse("The lookup failed."), nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithToolBox(toolBox).Build()
+	require.NoError(t, err)
+
+	task, err := agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Find record 7"))
+	require.NoError(t, err)
+	assert.Equal(t, adk.TaskStateCompleted, task.Status.State, "the worker survives the panic")
+
+	_, messages, _ := llmClient.CreateChatCompletionArgsForCall(1)
+	assert.Contains(t, messages[len(messages)-1].Content, "tool lookup panicked: index out of range")
+}
+
+func TestLLMToolOutputSummarizer(t *testing.T) {
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturns(completionResponse("3 records found"), nil)
+
+	summarizer := server.NewLLMToolOutputSummarizer(zap.NewNop(), llmClient)
+	summary, err := summarizer.SummarizeToolOutput(context.Background(), "lookup", "record 1\nrecord 2\nrecord 3", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "3 records found", summary)
+
+	_, messages, _ := llmClient.CreateChatCompletionArgsForCall(0)
+	assert.Contains(t, messages[0].Content, "output of the lookup tool in at most 100 bytes")
+	assert.Equal(t, "record 1\nrecord 2\nrecord 3", messages[1].Content)
+}
@@ -0,0 +1,573 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	adk "github.com/inference-gateway/a2a/adk"
+	client "github.com/inference-gateway/a2a/adk/client"
+	zap "go.uber.org/zap"
+)
+
+var _ Tool = (*RemoteAgentTool)(nil)
+
+// DefaultRemoteAgentPollInterval is the interval at which remote tasks are polled until they finish
+const DefaultRemoteAgentPollInterval = 500 * time.Millisecond
+
+// DefaultRemoteAgentMaxSessions is the number of local contexts whose remote conversations are kept
+const DefaultRemoteAgentMaxSessions = 1000
+
+// remoteAgentToolParameters is the parameters schema of remote agent tools
+var remoteAgentToolParameters = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"message": map[string]interface{}{
+			"type":        "string",
+			"description": "The request to send to the agent, including all the context it needs",
+		},
+	},
+	"required":             []string{"message"},
+	"additionalProperties": false,
+}
+
+// invalidToolNameChars matches the characters not allowed in tool names
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// RemoteAgentTaskError represents a remote task that did not complete successfully
+type RemoteAgentTaskError struct {
+	AgentName string
+	TaskID    string
+	State     adk.TaskState
+	Message   string
+}
+
+func (e *RemoteAgentTaskError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("remote agent %s task %s ended in state %s", e.AgentName, e.TaskID, e.State)
+	}
+	return fmt.Sprintf("remote agent %s task %s ended in state %s: %s", e.AgentName, e.TaskID, e.State, e.Message)
+}
+
+// remoteAgentSession is the state of the conversation with a remote agent for one local context
+type remoteAgentSession struct {
+	contextID string
+	// pendingTaskID is the remote task waiting for input, the next message continues it
+	pendingTaskID string
+}
+
+// remoteAgentSessions keeps the remote conversations of the most recently used local contexts
+// It is shared by all tools of the same remote agent so they continue the same remote context
+type remoteAgentSessions struct {
+	mu          sync.Mutex
+	maxSessions int
+	order       *list.List
+	sessions    map[string]*list.Element
+}
+
+type remoteAgentSessionEntry struct {
+	localContextID string
+	session        remoteAgentSession
+}
+
+// newRemoteAgentSessions creates the sessions of a remote agent, keeping DefaultRemoteAgentMaxSessions sessions
+func newRemoteAgentSessions() *remoteAgentSessions {
+	return &remoteAgentSessions{
+		maxSessions: DefaultRemoteAgentMaxSessions,
+		order:       list.New(),
+		sessions:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the session of the local context, calls without a local context always start a new session
+func (s *remoteAgentSessions) get(localContextID string) remoteAgentSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.sessions[localContextID]
+	if !ok {
+		return remoteAgentSession{}
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*remoteAgentSessionEntry).session
+}
+
+// set stores the session of the local context, evicting the least recently used session once the maximum is reached
+// Sessions of calls without a local context are not stored, so that unrelated callers never share a remote conversation
+func (s *remoteAgentSessions) set(localContextID string, session remoteAgentSession) {
+	if localContextID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.sessions[localContextID]; ok {
+		element.Value.(*remoteAgentSessionEntry).session = session
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.sessions[localContextID] = s.order.PushFront(&remoteAgentSessionEntry{localContextID: localContextID, session: session})
+	s.evict()
+}
+
+// setMaxSessions changes the number of sessions kept, a maxSessions of zero keeps all sessions
+func (s *remoteAgentSessions) setMaxSessions(maxSessions int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxSessions = maxSessions
+	s.evict()
+}
+
+// evict removes the least recently used sessions above the maximum, the caller must hold mu
+func (s *remoteAgentSessions) evict() {
+	for s.maxSessions > 0 && s.order.Len() > s.maxSessions {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.sessions, oldest.Value.(*remoteAgentSessionEntry).localContextID)
+	}
+}
+
+// RemoteAgentToolOption configures a RemoteAgentTool
+type RemoteAgentToolOption func(*RemoteAgentTool)
+
+// WithRemoteAgentToolName overrides the tool name derived from the agent card
+// For skill tools the skill ID is appended to the name
+func WithRemoteAgentToolName(name string) RemoteAgentToolOption {
+	return func(t *RemoteAgentTool) {
+		t.name = name
+	}
+}
+
+// WithRemoteAgentPollInterval sets the interval at which remote tasks are polled until they finish
+func WithRemoteAgentPollInterval(interval time.Duration) RemoteAgentToolOption {
+	return func(t *RemoteAgentTool) {
+		t.pollInterval = interval
+	}
+}
+
+// WithRemoteAgentMaxSessions sets the number of local contexts whose remote conversations are kept,
+// the least recently used conversation is forgotten first. A maxSessions of zero keeps all conversations.
+func WithRemoteAgentMaxSessions(maxSessions int) RemoteAgentToolOption {
+	return func(t *RemoteAgentTool) {
+		t.sessions.setMaxSessions(maxSessions)
+	}
+}
+
+// WithRemoteAgentStreaming overrides whether the remote agent is called with message/stream
+// By default streaming is used when the agent card advertises it
+func WithRemoteAgentStreaming(streaming bool) RemoteAgentToolOption {
+	return func(t *RemoteAgentTool) {
+		t.streaming = streaming
+	}
+}
+
+// RemoteAgentTool is a tool delegating to another A2A agent
+// The remote task is followed until it finishes and its artifacts and messages are returned as the tool result.
+// The remote context ID is kept per local context so follow-up calls continue the same remote conversation,
+// and a remote input-required state moves the local task to input-required as well.
+// Calls made outside of a local context start a new remote conversation each time.
+type RemoteAgentTool struct {
+	logger       *zap.Logger
+	client       client.A2AClient
+	agentName    string
+	name         string
+	description  string
+	skillID      string
+	streaming    bool
+	pollInterval time.Duration
+	sessions     *remoteAgentSessions
+}
+
+// NewRemoteAgentTool discovers the remote agent through its agent card and wraps it as a single tool
+func NewRemoteAgentTool(ctx context.Context, logger *zap.Logger, a2aClient client.A2AClient, opts ...RemoteAgentToolOption) (*RemoteAgentTool, error) {
+	card, err := a2aClient.GetAgentCard(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent card of remote agent at %s: %w", a2aClient.GetBaseURL(), err)
+	}
+
+	tool := newRemoteAgentTool(logger, a2aClient, card, newRemoteAgentSessions(), opts...)
+	tool.description = remoteAgentDescription(card)
+	return tool, nil
+}
+
+// NewRemoteAgentSkillTools discovers the remote agent through its agent card and wraps each of its skills as a tool
+// The tools send the skill ID in the message metadata and share the remote conversation of each local context
+func NewRemoteAgentSkillTools(ctx context.Context, logger *zap.Logger, a2aClient client.A2AClient, opts ...RemoteAgentToolOption) ([]*RemoteAgentTool, error) {
+	card, err := a2aClient.GetAgentCard(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent card of remote agent at %s: %w", a2aClient.GetBaseURL(), err)
+	}
+
+	sessions := newRemoteAgentSessions()
+	tools := make([]*RemoteAgentTool, 0, len(card.Skills))
+	for _, skill := range card.Skills {
+		tool := newRemoteAgentTool(logger, a2aClient, card, sessions, opts...)
+		tool.name = remoteAgentToolName(tool.name + "_" + skill.ID)
+		tool.description = remoteAgentSkillDescription(card, skill)
+		tool.skillID = skill.ID
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// newRemoteAgentTool creates a tool for the remote agent with the options applied
+func newRemoteAgentTool(logger *zap.Logger, a2aClient client.A2AClient, card *adk.AgentCard, sessions *remoteAgentSessions, opts ...RemoteAgentToolOption) *RemoteAgentTool {
+	tool := &RemoteAgentTool{
+		logger:       logger,
+		client:       a2aClient,
+		agentName:    card.Name,
+		name:         remoteAgentToolName(card.Name),
+		streaming:    card.Capabilities.Streaming != nil && *card.Capabilities.Streaming,
+		pollInterval: DefaultRemoteAgentPollInterval,
+		sessions:     sessions,
+	}
+	for _, opt := range opts {
+		opt(tool)
+	}
+	return tool
+}
+
+// GetName returns the name of the tool
+func (t *RemoteAgentTool) GetName() string {
+	return t.name
+}
+
+// GetDescription returns the description of the tool
+func (t *RemoteAgentTool) GetDescription() string {
+	return t.description
+}
+
+// GetParameters returns the parameters schema for the tool
+func (t *RemoteAgentTool) GetParameters() map[string]interface{} {
+	return remoteAgentToolParameters
+}
+
+// Execute sends the message to the remote agent and waits for the remote task to finish
+func (t *RemoteAgentTool) Execute(ctx context.Context, arguments map[string]interface{}) (string, error) {
+	text, _ := arguments["message"].(string)
+	localContextID := contextIDFromContext(ctx)
+	session := t.sessions.get(localContextID)
+
+	message := adk.Message{
+		Kind:      "message",
+		MessageID: uuid.New().String(),
+		Role:      "user",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": text,
+			},
+		},
+	}
+	if session.contextID != "" {
+		message.ContextID = StringPtr(session.contextID)
+	}
+	if session.pendingTaskID != "" {
+		message.TaskID = StringPtr(session.pendingTaskID)
+	}
+	if t.skillID != "" {
+		message.Metadata = map[string]interface{}{MetadataSkillIDKey: t.skillID}
+	}
+
+	params := adk.MessageSendParams{Message: message}
+
+	var task *adk.Task
+	var err error
+	if t.streaming {
+		task, err = t.stream(ctx, params)
+	} else {
+		task, err = t.send(ctx, params)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	session.contextID = task.ContextID
+	session.pendingTaskID = ""
+	if task.Status.State == adk.TaskStateInputRequired {
+		session.pendingTaskID = task.ID
+	}
+	t.sessions.set(localContextID, session)
+
+	t.logger.Debug("remote agent task finished",
+		zap.String("agent", t.agentName),
+		zap.String("remote_task_id", task.ID),
+		zap.String("remote_context_id", task.ContextID),
+		zap.String("state", string(task.Status.State)))
+
+	switch task.Status.State {
+	case adk.TaskStateCompleted:
+		return remoteTaskResult(task), nil
+	case adk.TaskStateInputRequired:
+		prompt := messageText(task.Status.Message)
+		if prompt == "" {
+			prompt = fmt.Sprintf("The agent %s needs more input to continue", t.agentName)
+		}
+		return "", NewInputRequiredError(prompt)
+	default:
+		return "", &RemoteAgentTaskError{
+			AgentName: t.agentName,
+			TaskID:    task.ID,
+			State:     task.Status.State,
+			Message:   messageText(task.Status.Message),
+		}
+	}
+}
+
+// send sends the message with message/send and polls the remote task until it finishes
+func (t *RemoteAgentTool) send(ctx context.Context, params adk.MessageSendParams) (*adk.Task, error) {
+	response, err := t.client.SendTask(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message to remote agent %s: %w", t.agentName, err)
+	}
+
+	task, err := remoteTaskFromResult(response.Result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response from remote agent %s: %w", t.agentName, err)
+	}
+	return t.poll(ctx, task)
+}
+
+// stream sends the message with message/stream and applies the events to the remote task
+// If the stream ends before the task finishes, the task is polled instead
+func (t *RemoteAgentTool) stream(ctx context.Context, params adk.MessageSendParams) (*adk.Task, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan interface{})
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- t.client.SendTaskStreaming(ctx, params, events)
+	}()
+
+	task := &adk.Task{}
+	for {
+		select {
+		case event := <-events:
+			if err := applyRemoteEvent(task, event); err != nil {
+				return nil, fmt.Errorf("invalid event from remote agent %s: %w", t.agentName, err)
+			}
+			if IsFinalTaskState(task.Status.State) {
+				return task, nil
+			}
+		case err := <-streamErr:
+			if err != nil {
+				return nil, fmt.Errorf("failed to stream message to remote agent %s: %w", t.agentName, err)
+			}
+			if task.ID == "" {
+				return nil, fmt.Errorf("remote agent %s ended the stream without a task", t.agentName)
+			}
+			return t.poll(ctx, task)
+		}
+	}
+}
+
+// poll gets the remote task until it reaches a final state
+func (t *RemoteAgentTool) poll(ctx context.Context, task *adk.Task) (*adk.Task, error) {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for !IsFinalTaskState(task.Status.State) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		response, err := t.client.GetTask(ctx, adk.TaskQueryParams{ID: task.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task %s of remote agent %s: %w", task.ID, t.agentName, err)
+		}
+		task, err = remoteTaskFromResult(response.Result)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response from remote agent %s: %w", t.agentName, err)
+		}
+	}
+	return task, nil
+}
+
+// remoteTaskFromResult converts the result of message/send or tasks/get to a task
+// A direct message reply is treated as a completed task
+func remoteTaskFromResult(result interface{}) (*adk.Task, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var kind struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+
+	if kind.Kind == "message" {
+		var message adk.Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, err
+		}
+		task := &adk.Task{Status: adk.TaskStatus{State: adk.TaskStateCompleted, Message: &message}}
+		if message.TaskID != nil {
+			task.ID = *message.TaskID
+		}
+		if message.ContextID != nil {
+			task.ContextID = *message.ContextID
+		}
+		return task, nil
+	}
+
+	var task adk.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, err
+	}
+	if task.ID == "" {
+		return nil, fmt.Errorf("result is neither a task nor a message")
+	}
+	return &task, nil
+}
+
+// applyRemoteEvent applies a streaming event of the remote agent to the task
+func applyRemoteEvent(task *adk.Task, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var kind struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return err
+	}
+
+	switch kind.Kind {
+	case "task", "message":
+		received, err := remoteTaskFromResult(event)
+		if err != nil {
+			return err
+		}
+		received.Artifacts = append(task.Artifacts, received.Artifacts...)
+		*task = *received
+	case "status-update":
+		var update adk.TaskStatusUpdateEvent
+		if err := json.Unmarshal(data, &update); err != nil {
+			return err
+		}
+		task.ID = update.TaskID
+		task.ContextID = update.ContextID
+		task.Status = update.Status
+	case "artifact-update":
+		var update adk.TaskArtifactUpdateEvent
+		if err := json.Unmarshal(data, &update); err != nil {
+			return err
+		}
+		task.ID = update.TaskID
+		task.ContextID = update.ContextID
+		appendRemoteArtifact(task, update)
+	}
+	return nil
+}
+
+// appendRemoteArtifact adds an artifact update to the task, appending parts to an existing artifact if requested
+func appendRemoteArtifact(task *adk.Task, update adk.TaskArtifactUpdateEvent) {
+	if update.Append != nil && *update.Append {
+		for i := range task.Artifacts {
+			if task.Artifacts[i].ArtifactID == update.Artifact.ArtifactID {
+				task.Artifacts[i].Parts = append(task.Artifacts[i].Parts, update.Artifact.Parts...)
+				return
+			}
+		}
+	}
+	task.Artifacts = append(task.Artifacts, update.Artifact)
+}
+
+// remoteTaskResult returns the text of the final status message and the artifacts of a completed remote task
+func remoteTaskResult(task *adk.Task) string {
+	sections := make([]string, 0, len(task.Artifacts)+1)
+	if text := messageText(task.Status.Message); text != "" {
+		sections = append(sections, text)
+	}
+
+	for _, artifact := range task.Artifacts {
+		text := partsText(artifact.Parts)
+		if text == "" {
+			continue
+		}
+		if artifact.Name != nil && *artifact.Name != "" {
+			text = fmt.Sprintf("Artifact %s:\n%s", *artifact.Name, text)
+		}
+		sections = append(sections, text)
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// messageText returns the content of the message parts as text
+func messageText(message *adk.Message) string {
+	if message == nil {
+		return ""
+	}
+	return partsText(message.Parts)
+}
+
+// partsText joins the parts as text, text parts are used as is and other parts are encoded as JSON
+func partsText(parts []adk.Part) string {
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		partMap, ok := part.(map[string]interface{})
+		if ok && partMap["kind"] == "text" {
+			if text, ok := partMap["text"].(string); ok && text != "" {
+				texts = append(texts, text)
+			}
+			continue
+		}
+		if data, err := json.Marshal(part); err == nil {
+			texts = append(texts, string(data))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// remoteAgentToolName converts a remote agent or skill name to a valid tool name
+func remoteAgentToolName(name string) string {
+	name = strings.Trim(invalidToolNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		name = "remote_agent"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// remoteAgentDescription describes a remote agent and its skills to the LLM
+func remoteAgentDescription(card *adk.AgentCard) string {
+	description := fmt.Sprintf("Delegate a request to the %s agent: %s", card.Name, card.Description)
+	if len(card.Skills) == 0 {
+		return description
+	}
+
+	skills := make([]string, 0, len(card.Skills))
+	for _, skill := range card.Skills {
+		skills = append(skills, fmt.Sprintf("%s (%s)", skill.Name, skill.Description))
+	}
+	return description + "\nSkills: " + strings.Join(skills, "; ")
+}
+
+// remoteAgentSkillDescription describes a skill of a remote agent to the LLM
+func remoteAgentSkillDescription(card *adk.AgentCard, skill adk.AgentSkill) string {
+	description := fmt.Sprintf("Use the %s skill of the %s agent: %s", skill.Name, card.Name, skill.Description)
+	if len(skill.Examples) > 0 {
+		description += "\nExamples: " + strings.Join(skill.Examples, "; ")
+	}
+	return description
+}
@@ -0,0 +1,263 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	clientmocks "github.com/inference-gateway/a2a/adk/client/mocks"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newRemoteAgentCard(streaming bool) *adk.AgentCard {
+	return &adk.AgentCard{
+		Name:         "Weather Agent",
+		Description:  "Answers weather questions",
+		Capabilities: adk.AgentCapabilities{Streaming: &streaming},
+		Skills: []adk.AgentSkill{
+			{ID: "forecast", Name: "Forecast", Description: "Forecast the weather", Examples: []string{"Weather in Berlin tomorrow?"}},
+			{ID: "alerts", Name: "Alerts", Description: "List weather alerts"},
+		},
+	}
+}
+
+func remoteTask(id string, state adk.TaskState, text string, artifacts ...adk.Artifact) *adk.JSONRPCSuccessResponse {
+	task := adk.Task{
+		ID:        id,
+		ContextID: "remote-context",
+		Kind:      "task",
+		Status:    adk.TaskStatus{State: state},
+		Artifacts: artifacts,
+	}
+	if text != "" {
+		task.Status.Message = &adk.Message{
+			Kind:  "message",
+			Role:  "agent",
+			Parts: []adk.Part{map[string]interface{}{"kind": "text", "text": text}},
+		}
+	}
+	return &adk.JSONRPCSuccessResponse{JSONRPC: "2.0", Result: task}
+}
+
+func TestNewRemoteAgentTool(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(false), nil)
+	a2aClient.SendTaskReturns(remoteTask("remote-1", adk.TaskStateWorking, ""), nil)
+	a2aClient.GetTaskReturnsOnCall(0, remoteTask("remote-1", adk.TaskStateWorking, ""), nil)
+	a2aClient.GetTaskReturnsOnCall(1, remoteTask("remote-1", adk.TaskStateCompleted, "Sunny, 24°C", adk.Artifact{
+		ArtifactID: "forecast",
+		Name:       server.StringPtr("forecast"),
+		Parts:      []adk.Part{map[string]interface{}{"kind": "text", "text": "Mon: sunny"}},
+	}), nil)
+
+	tool, err := server.NewRemoteAgentTool(context.Background(), zap.NewNop(), a2aClient,
+		server.WithRemoteAgentPollInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Equal(t, "weather_agent", tool.GetName())
+	assert.Contains(t, tool.GetDescription(), "Answers weather questions")
+	assert.Contains(t, tool.GetDescription(), "Forecast (Forecast the weather)")
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(tool)
+
+	result, err := toolBox.ExecuteTool(context.Background(), "weather_agent", map[string]interface{}{"message": "Weather in Berlin?"})
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny, 24°C\n\nArtifact forecast:\nMon: sunny", result)
+	assert.Equal(t, 2, a2aClient.GetTaskCallCount())
+
+	_, params := a2aClient.SendTaskArgsForCall(0)
+	assert.Nil(t, params.Message.ContextID, "the first call starts a new remote context")
+	assert.Equal(t, "Weather in Berlin?", params.Message.Parts[0].(map[string]interface{})["text"])
+
+	a2aClient.SendTaskReturns(remoteTask("remote-2", adk.TaskStateCompleted, "Rainy"), nil)
+	result, err = toolBox.ExecuteTool(context.Background(), "weather_agent", map[string]interface{}{"message": "And tomorrow?"})
+	require.NoError(t, err)
+	assert.Equal(t, "Rainy", result)
+
+	_, params = a2aClient.SendTaskArgsForCall(1)
+	assert.Nil(t, params.Message.ContextID, "calls outside of a local context do not share a remote context")
+	assert.Nil(t, params.Message.TaskID)
+}
+
+func TestRemoteAgentTool_Sessions(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(false), nil)
+	a2aClient.SendTaskReturns(remoteTask("remote-1", adk.TaskStateCompleted, "Sunny"), nil)
+
+	tool, err := server.NewRemoteAgentTool(context.Background(), zap.NewNop(), a2aClient,
+		server.WithRemoteAgentMaxSessions(1))
+	require.NoError(t, err)
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(tool)
+
+	toolCalls := []sdk.ChatCompletionMessageToolCall{{
+		Id:       "call-1",
+		Type:     "function",
+		Function: sdk.ChatCompletionMessageToolCallFunction{Name: "weather_agent", Arguments: `{"message":"Weather?"}`},
+	}}
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (*sdk.CreateChatCompletionResponse, error) {
+		if messages[len(messages)-1].Role == sdk.Tool {
+			return completionResponse("Sunny"), nil
+		}
+		return &sdk.CreateChatCompletionResponse{
+			Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, ToolCalls: &toolCalls}}},
+		}, nil
+	}
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(llmClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	remoteContexts := make([]*string, 0, 4)
+	for _, localContextID := range []string{"context-1", "context-1", "context-2", "context-1"} {
+		task := &adk.Task{ID: "task-" + localContextID, ContextID: localContextID}
+		_, err := agent.ProcessTask(context.Background(), task, textMessage("user", "Weather?"))
+		require.NoError(t, err)
+
+		_, params := a2aClient.SendTaskArgsForCall(a2aClient.SendTaskCallCount() - 1)
+		remoteContexts = append(remoteContexts, params.Message.ContextID)
+	}
+
+	assert.Nil(t, remoteContexts[0], "the first call of a context starts a new remote context")
+	require.NotNil(t, remoteContexts[1])
+	assert.Equal(t, "remote-context", *remoteContexts[1], "follow-up calls continue the remote context")
+	assert.Nil(t, remoteContexts[2], "another context does not continue the remote context")
+	assert.Nil(t, remoteContexts[3], "the least recently used session is evicted")
+}
+
+func TestNewRemoteAgentSkillTools(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(false), nil)
+	a2aClient.SendTaskReturns(remoteTask("remote-1", adk.TaskStateCompleted, "No alerts"), nil)
+
+	tools, err := server.NewRemoteAgentSkillTools(context.Background(), zap.NewNop(), a2aClient,
+		server.WithRemoteAgentToolName("weather"))
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	assert.Equal(t, "weather_forecast", tools[0].GetName())
+	assert.Contains(t, tools[0].GetDescription(), "Examples: Weather in Berlin tomorrow?")
+	assert.Equal(t, "weather_alerts", tools[1].GetName())
+
+	result, err := tools[1].Execute(context.Background(), map[string]interface{}{"message": "Any alerts?"})
+	require.NoError(t, err)
+	assert.Equal(t, "No alerts", result)
+
+	_, params := a2aClient.SendTaskArgsForCall(0)
+	assert.Equal(t, "alerts", params.Message.Metadata[server.MetadataSkillIDKey])
+}
+
+func TestRemoteAgentTool_Streaming(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(true), nil)
+	a2aClient.SendTaskStreamingStub = func(ctx context.Context, params adk.MessageSendParams, events chan<- interface{}) error {
+		appendParts := true
+		for _, event := range []interface{}{
+			map[string]interface{}{"kind": "status-update", "taskId": "remote-1", "contextId": "remote-context", "status": map[string]interface{}{"state": "working"}},
+			map[string]interface{}{"kind": "artifact-update", "taskId": "remote-1", "contextId": "remote-context", "artifact": map[string]interface{}{
+				"artifactId": "report", "parts": []interface{}{map[string]interface{}{"kind": "text", "text": "part one"}},
+			}},
+			adk.TaskArtifactUpdateEvent{Kind: "artifact-update", TaskID: "remote-1", ContextID: "remote-context", Append: &appendParts, Artifact: adk.Artifact{
+				ArtifactID: "report", Parts: []adk.Part{map[string]interface{}{"kind": "text", "text": "part two"}},
+			}},
+			map[string]interface{}{"kind": "status-update", "taskId": "remote-1", "contextId": "remote-context", "final": true, "status": map[string]interface{}{"state": "completed"}},
+		} {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	tool, err := server.NewRemoteAgentTool(context.Background(), zap.NewNop(), a2aClient)
+	require.NoError(t, err)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"message": "Write a report"})
+	require.NoError(t, err)
+	assert.Equal(t, "part one\npart two", result)
+	assert.Zero(t, a2aClient.SendTaskCallCount())
+	assert.Zero(t, a2aClient.GetTaskCallCount())
+}
+
+func TestRemoteAgentTool_RemoteFailure(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(false), nil)
+	a2aClient.SendTaskReturns(remoteTask("remote-1", adk.TaskStateFailed, "service unavailable"), nil)
+
+	tool, err := server.NewRemoteAgentTool(context.Background(), zap.NewNop(), a2aClient)
+	require.NoError(t, err)
+
+	_, err = tool.Execute(context.Background(), map[string]interface{}{"message": "Weather?"})
+	var taskErr *server.RemoteAgentTaskError
+	require.ErrorAs(t, err, &taskErr)
+	assert.Equal(t, adk.TaskStateFailed, taskErr.State)
+	assert.Equal(t, "service unavailable", taskErr.Message)
+}
+
+func TestRemoteAgentTool_InputRequired(t *testing.T) {
+	a2aClient := &clientmocks.FakeA2AClient{}
+	a2aClient.GetAgentCardReturns(newRemoteAgentCard(false), nil)
+	a2aClient.SendTaskReturnsOnCall(0, remoteTask("remote-1", adk.TaskStateInputRequired, "Which city?"), nil)
+	a2aClient.SendTaskReturnsOnCall(1, remoteTask("remote-1", adk.TaskStateCompleted, "Sunny in Berlin"), nil)
+
+	tool, err := server.NewRemoteAgentTool(context.Background(), zap.NewNop(), a2aClient)
+	require.NoError(t, err)
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(tool)
+
+	toolCall := func(message string) *sdk.CreateChatCompletionResponse {
+		toolCalls := []sdk.ChatCompletionMessageToolCall{{
+			Id:       "call-1",
+			Type:     "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{Name: "weather_agent", Arguments: `{"message":"` + message + `"}`},
+		}}
+		return &sdk.CreateChatCompletionResponse{
+			Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, ToolCalls: &toolCalls}}},
+		}
+	}
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, toolCall("What is the weather?"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, toolCall("Berlin"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(2, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, Content: "It is sunny in Berlin"}}},
+	}, nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(llmClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	result, err := agent.ProcessTask(context.Background(), task, &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"})
+	require.NoError(t, err)
+
+	assert.Equal(t, adk.TaskStateInputRequired, result.Status.State, "remote input-required is surfaced to the local task")
+	require.NotNil(t, result.Status.Message)
+	assert.Equal(t, "Which city?", result.Status.Message.Parts[0].(map[string]interface{})["text"])
+
+	result, err = agent.ProcessTask(context.Background(), result, &adk.Message{Kind: "message", MessageID: "msg-2", Role: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+
+	_, params := a2aClient.SendTaskArgsForCall(1)
+	require.NotNil(t, params.Message.TaskID)
+	assert.Equal(t, "remote-1", *params.Message.TaskID, "the reply continues the remote task waiting for input")
+	require.NotNil(t, params.Message.ContextID)
+	assert.Equal(t, "remote-context", *params.Message.ContextID)
+}
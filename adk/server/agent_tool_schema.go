@@ -0,0 +1,349 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ToolArgumentViolation describes a single argument that does not match the tool schema
+type ToolArgumentViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ToolArgumentsValidationError represents tool arguments produced by the LLM that do not match the tool schema
+type ToolArgumentsValidationError struct {
+	ToolName   string                  `json:"tool"`
+	Violations []ToolArgumentViolation `json:"violations"`
+}
+
+func (e *ToolArgumentsValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Path+": "+violation.Message)
+	}
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.ToolName, strings.Join(messages, "; "))
+}
+
+// ToolResult returns the error as a JSON tool result so the LLM can correct its arguments
+func (e *ToolArgumentsValidationError) ToolResult() string {
+	result, err := json.Marshal(map[string]interface{}{
+		"error":      "invalid_arguments",
+		"tool":       e.ToolName,
+		"violations": e.Violations,
+		"hint":       "correct the arguments according to the tool parameters schema and call the tool again",
+	})
+	if err != nil {
+		return e.Error()
+	}
+	return string(result)
+}
+
+// NewToolArgumentsValidationError creates a new tool arguments validation error
+func NewToolArgumentsValidationError(toolName string, violations ...ToolArgumentViolation) *ToolArgumentsValidationError {
+	return &ToolArgumentsValidationError{ToolName: toolName, Violations: violations}
+}
+
+// ParseToolArguments decodes the JSON arguments of a tool call, empty arguments decode to an empty object
+func ParseToolArguments(toolName string, arguments string) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	if strings.TrimSpace(arguments) == "" {
+		return args, nil
+	}
+
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, NewToolArgumentsValidationError(toolName, ToolArgumentViolation{
+			Path:    "$",
+			Message: fmt.Sprintf("arguments must be a JSON object: %v", err),
+		})
+	}
+	return args, nil
+}
+
+// ValidateToolArguments validates the arguments of a tool call against the JSON schema of its parameters.
+// The commonly used subset of JSON Schema is supported: type, properties, required, additionalProperties,
+// items, enum, const, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+// pattern, minItems and maxItems. Unknown keywords are ignored.
+// Arguments built in Go, such as ints or typed slices, are validated by their JSON form.
+func ValidateToolArguments(toolName string, schema map[string]interface{}, arguments map[string]interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	value, err := jsonArguments(arguments)
+	if err != nil {
+		return NewToolArgumentsValidationError(toolName, ToolArgumentViolation{
+			Path:    "$",
+			Message: fmt.Sprintf("arguments must be JSON encodable: %v", err),
+		})
+	}
+
+	var violations []ToolArgumentViolation
+	validateSchemaValue(schema, value, "$", &violations)
+	if len(violations) > 0 {
+		return NewToolArgumentsValidationError(toolName, violations...)
+	}
+	return nil
+}
+
+// jsonArguments converts the arguments to the values encoding/json decodes to, nil arguments convert to an empty object
+func jsonArguments(arguments map[string]interface{}) (interface{}, error) {
+	if arguments == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// validateSchemaValue appends the violations of value against schema at path
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string, violations *[]ToolArgumentViolation) {
+	violate := func(format string, args ...interface{}) {
+		*violations = append(*violations, ToolArgumentViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if types := schemaStrings(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, schemaType := range types {
+			if matchesSchemaType(schemaType, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			violate("expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		allowed := schemaValues(enum)
+		if !containsJSONValue(allowed, value) {
+			violate("must be one of %s", formatJSONValues(allowed))
+		}
+	}
+
+	if constant, ok := schema["const"]; ok && !equalJSONValues(constant, value) {
+		violate("must be %s", formatJSONValues([]interface{}{constant}))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(schema, v, path, violations)
+	case []interface{}:
+		if minItems, ok := schemaNumber(schema["minItems"]); ok && float64(len(v)) < minItems {
+			violate("must contain at least %v items", minItems)
+		}
+		if maxItems, ok := schemaNumber(schema["maxItems"]); ok && float64(len(v)) > maxItems {
+			violate("must contain at most %v items", maxItems)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := schemaNumber(schema["minLength"]); ok && length < minLength {
+			violate("must be at least %v characters long", minLength)
+		}
+		if maxLength, ok := schemaNumber(schema["maxLength"]); ok && length > maxLength {
+			violate("must be at most %v characters long", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				violate("must match pattern %q", pattern)
+			}
+		}
+	case float64:
+		if minimum, ok := schemaNumber(schema["minimum"]); ok && v < minimum {
+			violate("must be greater than or equal to %v", minimum)
+		}
+		if maximum, ok := schemaNumber(schema["maximum"]); ok && v > maximum {
+			violate("must be less than or equal to %v", maximum)
+		}
+		if minimum, ok := schemaNumber(schema["exclusiveMinimum"]); ok && v <= minimum {
+			violate("must be greater than %v", minimum)
+		}
+		if maximum, ok := schemaNumber(schema["exclusiveMaximum"]); ok && v >= maximum {
+			violate("must be less than %v", maximum)
+		}
+	}
+}
+
+// validateSchemaObject appends the violations of the properties of an object
+func validateSchemaObject(schema map[string]interface{}, object map[string]interface{}, path string, violations *[]ToolArgumentViolation) {
+	for _, name := range schemaStrings(schema["required"]) {
+		if _, exists := object[name]; !exists {
+			*violations = append(*violations, ToolArgumentViolation{
+				Path:    path + "." + name,
+				Message: "is required",
+			})
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+			validateSchemaValue(propertySchema, object[name], propertyPath, violations)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*violations = append(*violations, ToolArgumentViolation{Path: propertyPath, Message: "is not an allowed property"})
+			}
+		case map[string]interface{}:
+			validateSchemaValue(additional, object[name], propertyPath, violations)
+		}
+	}
+}
+
+// matchesSchemaType checks if a decoded JSON value is of the given JSON schema type
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// jsonTypeName returns the JSON type name of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaStrings reads a schema keyword that holds a string or a list of strings
+func schemaStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// schemaValues reads a schema keyword that holds a list of values, schemas built in Go may use typed slices
+func schemaValues(value interface{}) []interface{} {
+	if values, ok := value.([]interface{}); ok {
+		return values
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var values []interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// schemaNumber reads a numeric schema keyword, schemas built in Go may use any numeric type
+func schemaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equalJSONValues compares two values by their JSON representation
+func equalJSONValues(a, b interface{}) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+	return errLeft == nil && errRight == nil && string(left) == string(right)
+}
+
+// containsJSONValue checks if value is one of values
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	for _, allowed := range values {
+		if equalJSONValues(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJSONValues formats values as a comma separated list of JSON literals
+func formatJSONValues(values []interface{}) string {
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		data, _ := json.Marshal(value)
+		formatted = append(formatted, string(data))
+	}
+	return strings.Join(formatted, ", ")
+}
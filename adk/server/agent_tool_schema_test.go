@@ -0,0 +1,294 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	"github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var weatherSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"location": map[string]interface{}{
+			"type":      "string",
+			"minLength": 2,
+		},
+		"unit": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"celsius", "fahrenheit"},
+		},
+		"days": map[string]interface{}{
+			"type":    "integer",
+			"minimum": 1,
+			"maximum": 7,
+		},
+		"tags": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string"},
+		},
+	},
+	"required":             []string{"location"},
+	"additionalProperties": false,
+}
+
+func TestValidateToolArguments(t *testing.T) {
+	tests := []struct {
+		name               string
+		schema             map[string]interface{}
+		arguments          string
+		expectedViolations []string
+	}{
+		{
+			name:      "valid arguments",
+			schema:    weatherSchema,
+			arguments: `{"location": "Berlin", "unit": "celsius", "days": 3, "tags": ["rain"]}`,
+		},
+		{
+			name:               "missing required property",
+			schema:             weatherSchema,
+			arguments:          `{"unit": "celsius"}`,
+			expectedViolations: []string{"$.location: is required"},
+		},
+		{
+			name:               "wrong type",
+			schema:             weatherSchema,
+			arguments:          `{"location": 42}`,
+			expectedViolations: []string{"$.location: expected string, got number"},
+		},
+		{
+			name:               "value not in enum",
+			schema:             weatherSchema,
+			arguments:          `{"location": "Berlin", "unit": "kelvin"}`,
+			expectedViolations: []string{`$.unit: must be one of "celsius", "fahrenheit"`},
+		},
+		{
+			name:      "integer out of range",
+			schema:    weatherSchema,
+			arguments: `{"location": "Berlin", "days": 8}`,
+			expectedViolations: []string{
+				"$.days: must be less than or equal to 7",
+			},
+		},
+		{
+			name:               "fractional number for integer",
+			schema:             weatherSchema,
+			arguments:          `{"location": "Berlin", "days": 1.5}`,
+			expectedViolations: []string{"$.days: expected integer, got number"},
+		},
+		{
+			name:               "invalid array item",
+			schema:             weatherSchema,
+			arguments:          `{"location": "Berlin", "tags": ["rain", true]}`,
+			expectedViolations: []string{"$.tags[1]: expected string, got boolean"},
+		},
+		{
+			name:               "unknown property",
+			schema:             weatherSchema,
+			arguments:          `{"location": "Berlin", "country": "DE"}`,
+			expectedViolations: []string{"$.country: is not an allowed property"},
+		},
+		{
+			name:               "string too short",
+			schema:             weatherSchema,
+			arguments:          `{"location": "B"}`,
+			expectedViolations: []string{"$.location: must be at least 2 characters long"},
+		},
+		{
+			name:      "empty schema accepts anything",
+			schema:    map[string]interface{}{},
+			arguments: `{"anything": [1, 2, 3]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments, err := server.ParseToolArguments("get_weather", tt.arguments)
+			require.NoError(t, err)
+
+			err = server.ValidateToolArguments("get_weather", tt.schema, arguments)
+			if len(tt.expectedViolations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *server.ToolArgumentsValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "get_weather", validationErr.ToolName)
+
+			violations := make([]string, 0, len(validationErr.Violations))
+			for _, violation := range validationErr.Violations {
+				violations = append(violations, violation.Path+": "+violation.Message)
+			}
+			assert.Equal(t, tt.expectedViolations, violations)
+		})
+	}
+}
+
+func TestValidateToolArguments_GoValues(t *testing.T) {
+	tests := []struct {
+		name               string
+		arguments          map[string]interface{}
+		expectedViolations []string
+	}{
+		{
+			name:      "int for integer",
+			arguments: map[string]interface{}{"location": "Berlin", "days": 3},
+		},
+		{
+			name:      "typed slice for array",
+			arguments: map[string]interface{}{"location": "Berlin", "tags": []string{"rain"}},
+		},
+		{
+			name:      "float for integer",
+			arguments: map[string]interface{}{"location": "Berlin", "days": 3.0},
+		},
+		{
+			name:               "int out of range",
+			arguments:          map[string]interface{}{"location": "Berlin", "days": int64(8)},
+			expectedViolations: []string{"$.days: must be less than or equal to 7"},
+		},
+		{
+			name:               "typed slice with wrong items",
+			arguments:          map[string]interface{}{"location": "Berlin", "tags": []int{1}},
+			expectedViolations: []string{"$.tags[0]: expected string, got number"},
+		},
+		{
+			name:               "value without a JSON form",
+			arguments:          map[string]interface{}{"location": make(chan int)},
+			expectedViolations: []string{"$: arguments must be JSON encodable: json: unsupported type: chan int"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := server.ValidateToolArguments("get_weather", weatherSchema, tt.arguments)
+			if len(tt.expectedViolations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *server.ToolArgumentsValidationError
+			require.ErrorAs(t, err, &validationErr)
+
+			violations := make([]string, 0, len(validationErr.Violations))
+			for _, violation := range validationErr.Violations {
+				violations = append(violations, violation.Path+": "+violation.Message)
+			}
+			assert.Equal(t, tt.expectedViolations, violations)
+		})
+	}
+}
+
+func TestParseToolArguments(t *testing.T) {
+	arguments, err := server.ParseToolArguments("get_weather", "")
+	require.NoError(t, err)
+	assert.Empty(t, arguments)
+
+	_, err = server.ParseToolArguments("get_weather", `{"location": `)
+	var validationErr *server.ToolArgumentsValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "$", validationErr.Violations[0].Path)
+}
+
+func TestDefaultToolBox_ExecuteTool_ValidatesArguments(t *testing.T) {
+	executed := false
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("get_weather", "Get the weather", weatherSchema,
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			executed = true
+			return "sunny", nil
+		}))
+
+	telemetry := &mocks.FakeOpenTelemetry{}
+	toolBox.SetTelemetry(telemetry, otel.TelemetryAttributes{Provider: "openai", Model: "gpt-4"})
+
+	_, err := toolBox.ExecuteTool(context.Background(), "get_weather", map[string]interface{}{"unit": "celsius"})
+	var validationErr *server.ToolArgumentsValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.False(t, executed, "tools are not executed with invalid arguments")
+
+	require.Equal(t, 1, telemetry.RecordToolCallFailureCallCount())
+	_, attrs, toolName, errorMessage := telemetry.RecordToolCallFailureArgsForCall(0)
+	assert.Equal(t, "openai", attrs.Provider)
+	assert.Equal(t, "gpt-4", attrs.Model)
+	assert.Equal(t, "get_weather", toolName)
+	assert.Contains(t, errorMessage, "$.location: is required")
+
+	var toolResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(validationErr.ToolResult()), &toolResult))
+	assert.Equal(t, "invalid_arguments", toolResult["error"])
+	assert.Equal(t, "get_weather", toolResult["tool"])
+
+	result, err := toolBox.ExecuteTool(context.Background(), "get_weather", map[string]interface{}{"location": "Berlin"})
+	require.NoError(t, err)
+	assert.Equal(t, "sunny", result)
+	assert.Equal(t, 1, telemetry.RecordToolCallFailureCallCount())
+}
+
+func TestDefaultOpenAICompatibleAgent_ReturnsValidationErrorsToLLM(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("get_weather", "Get the weather", weatherSchema,
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "sunny", nil
+		}))
+
+	telemetry := &mocks.FakeOpenTelemetry{}
+	toolBox.SetTelemetry(telemetry, otel.TelemetryAttributes{})
+
+	toolCalls := []sdk.ChatCompletionMessageToolCall{
+		{
+			Id:       "call-0",
+			Type:     "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{Name: "get_weather", Arguments: `{"location": 1}`},
+		},
+		{
+			Id:       "call-1",
+			Type:     "function",
+			Function: sdk.ChatCompletionMessageToolCallFunction{Name: "get_weather", Arguments: `{"location": `},
+		},
+	}
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, ToolCalls: &toolCalls}}},
+	}, nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, Content: "done"}}},
+	}, nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(llmClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	result, err := agent.ProcessTask(context.Background(), task, &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"})
+	require.NoError(t, err)
+	assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+
+	var toolResults []string
+	for _, message := range result.History {
+		if message.Role != "tool" {
+			continue
+		}
+		data := message.Parts[0].(map[string]interface{})["data"].(map[string]interface{})
+		toolResults = append(toolResults, data["result"].(string))
+	}
+	require.Len(t, toolResults, 2, "invalid calls still produce a tool result for the llm")
+	assert.Contains(t, toolResults[0], "expected string, got number")
+	assert.Contains(t, toolResults[1], "arguments must be a JSON object")
+
+	assert.Equal(t, 2, telemetry.RecordToolCallFailureCallCount())
+	_, attrs, _, _ := telemetry.RecordToolCallFailureArgsForCall(0)
+	assert.Equal(t, "task-1", attrs.TaskID)
+}
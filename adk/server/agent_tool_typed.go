@@ -0,0 +1,302 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// NewTypedTool creates a tool whose parameters schema is derived from the In struct.
+// Arguments are decoded into In, rejecting unknown fields, and the Out value returned by the
+// executor is marshaled with JSONTool.
+//
+// The schema is read from the struct fields and their tags:
+//   - json: the property name, fields tagged "-" are skipped and omitempty fields are optional
+//   - description: the property description
+//   - enum: comma separated allowed values
+//   - default: the value used when the argument is missing, it also makes the property optional
+//   - required: "true" or "false" to override whether the property is required
+//
+// Fields without omitempty, default or a pointer type are required.
+func NewTypedTool[In any, Out any](
+	name string,
+	description string,
+	executor func(ctx context.Context, input In) (Out, error),
+	opts ...BasicToolOption,
+) (*BasicTool, error) {
+	inputType := reflect.TypeOf((*In)(nil)).Elem()
+	parameters, err := ToolParametersFromType(inputType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive parameters of tool %s: %w", name, err)
+	}
+
+	execute := func(ctx context.Context, arguments map[string]interface{}) (string, error) {
+		input, err := decodeToolArguments[In](name, arguments)
+		if err != nil {
+			return "", err
+		}
+
+		output, err := executor(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		return JSONTool(output)
+	}
+
+	return NewBasicTool(name, description, parameters, execute, opts...), nil
+}
+
+// ToolParametersFromType derives the JSON schema of tool parameters from a struct type
+func ToolParametersFromType(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("tool parameters must be a struct, got %s", t)
+	}
+
+	return schemaForType(t, make(map[reflect.Type]bool))
+}
+
+// decodeToolArguments decodes tool arguments into In after applying the defaults declared on its fields
+func decodeToolArguments[In any](toolName string, arguments map[string]interface{}) (In, error) {
+	var input In
+
+	if err := applyFieldDefaults(reflect.ValueOf(&input).Elem()); err != nil {
+		return input, err
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return input, fmt.Errorf("failed to encode arguments of tool %s: %w", toolName, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		return input, NewToolArgumentsValidationError(toolName, ToolArgumentViolation{
+			Path:    "$",
+			Message: err.Error(),
+		})
+	}
+	return input, nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaForType returns the JSON schema of a Go type, visiting guards against recursive types
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := schemaForType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s, only string keys can be represented in JSON", t.Key())
+		}
+		values, err := schemaForType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			return map[string]interface{}{}, nil
+		}
+		if visiting[t] {
+			return nil, fmt.Errorf("recursive type %s is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		}
+		required := make([]string, 0)
+		if err := addStructProperties(t, schema["properties"].(map[string]interface{}), &required, visiting); err != nil {
+			return nil, err
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// addStructProperties adds the schemas of the fields of a struct, embedded structs are flattened like encoding/json does
+func addStructProperties(t reflect.Type, properties map[string]interface{}, required *[]string, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructProperties(embedded, properties, required, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		property, err := schemaForType(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := make([]interface{}, 0)
+			for _, raw := range strings.Split(enum, ",") {
+				value, err := parseTagValue(field.Type, strings.TrimSpace(raw))
+				if err != nil {
+					return fmt.Errorf("field %s: invalid enum value %q: %w", field.Name, raw, err)
+				}
+				values = append(values, value)
+			}
+			property["enum"] = values
+		}
+
+		defaultValue, hasDefault := field.Tag.Lookup("default")
+		if hasDefault {
+			value, err := parseTagValue(field.Type, defaultValue)
+			if err != nil {
+				return fmt.Errorf("field %s: invalid default %q: %w", field.Name, defaultValue, err)
+			}
+			property["default"] = value
+		}
+
+		isRequired := !omitEmpty && !hasDefault && field.Type.Kind() != reflect.Pointer
+		switch field.Tag.Get("required") {
+		case "true":
+			isRequired = true
+		case "false":
+			isRequired = false
+		}
+		if isRequired {
+			*required = append(*required, name)
+		}
+
+		properties[name] = property
+	}
+	return nil
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is omitted when empty or skipped entirely
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(","+options+",", ",omitempty,"), false
+}
+
+// parseTagValue parses a tag value as a JSON value of the field type, plain strings need no quotes
+func parseTagValue(t reflect.Type, raw string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		return raw, nil
+	}
+
+	target := reflect.New(t)
+	if err := json.Unmarshal([]byte(raw), target.Interface()); err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// applyFieldDefaults sets the fields of a struct that declare a default, recursing into embedded structs
+func applyFieldDefaults(v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := applyFieldDefaults(v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		defaultValue, ok := field.Tag.Lookup("default")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		target := v.Field(i)
+		raw := defaultValue
+		if underlying := field.Type; underlying.Kind() == reflect.String ||
+			(underlying.Kind() == reflect.Pointer && underlying.Elem().Kind() == reflect.String) {
+			quoted, _ := json.Marshal(defaultValue)
+			raw = string(quoted)
+		}
+		if err := json.Unmarshal([]byte(raw), target.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid default %q of field %s: %w", defaultValue, field.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package server_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type forecastPeriod struct {
+	Days int `json:"days" description:"Number of days to forecast" default:"3"`
+}
+
+type weatherInput struct {
+	forecastPeriod
+	Location string   `json:"location" description:"The city and state, e.g. San Francisco, CA"`
+	Unit     string   `json:"unit" description:"Temperature unit" enum:"celsius,fahrenheit" default:"celsius"`
+	Tags     []string `json:"tags,omitempty"`
+	Country  *string  `json:"country"`
+	Internal string   `json:"-"`
+}
+
+type weatherOutput struct {
+	Location    string  `json:"location"`
+	Temperature float64 `json:"temperature"`
+	Unit        string  `json:"unit"`
+	Days        int     `json:"days"`
+}
+
+func TestToolParametersFromType(t *testing.T) {
+	parameters, err := server.ToolParametersFromType(reflect.TypeOf(weatherInput{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"days": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of days to forecast",
+				"default":     float64(3),
+			},
+			"location": map[string]interface{}{
+				"type":        "string",
+				"description": "The city and state, e.g. San Francisco, CA",
+			},
+			"unit": map[string]interface{}{
+				"type":        "string",
+				"description": "Temperature unit",
+				"enum":        []interface{}{"celsius", "fahrenheit"},
+				"default":     "celsius",
+			},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+			"country": map[string]interface{}{
+				"type": "string",
+			},
+		},
+		"required":             []string{"location"},
+		"additionalProperties": false,
+	}, parameters)
+}
+
+func TestToolParametersFromType_Errors(t *testing.T) {
+	type recursive struct {
+		Children []recursive `json:"children"`
+	}
+	type invalidDefault struct {
+		Days int `json:"days" default:"three"`
+	}
+	type unsupported struct {
+		Callback func() `json:"callback"`
+	}
+
+	tests := []struct {
+		name          string
+		value         interface{}
+		expectedError string
+	}{
+		{name: "not a struct", value: "text", expectedError: "tool parameters must be a struct"},
+		{name: "recursive type", value: recursive{}, expectedError: "recursive type"},
+		{name: "invalid default", value: invalidDefault{}, expectedError: "invalid default"},
+		{name: "unsupported field type", value: unsupported{}, expectedError: "unsupported type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.ToolParametersFromType(reflect.TypeOf(tt.value))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestNewTypedTool(t *testing.T) {
+	var received weatherInput
+	tool, err := server.NewTypedTool("get_weather", "Get current weather for a location",
+		func(ctx context.Context, input weatherInput) (weatherOutput, error) {
+			received = input
+			return weatherOutput{Location: input.Location, Temperature: 21.5, Unit: input.Unit, Days: input.Days}, nil
+		})
+	require.NoError(t, err)
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(tool)
+
+	tests := []struct {
+		name           string
+		arguments      map[string]interface{}
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			name:           "applies defaults to missing arguments",
+			arguments:      map[string]interface{}{"location": "Berlin"},
+			expectedResult: `{"location":"Berlin","temperature":21.5,"unit":"celsius","days":3}`,
+		},
+		{
+			name:           "decodes provided arguments",
+			arguments:      map[string]interface{}{"location": "Austin", "unit": "fahrenheit", "days": float64(5), "tags": []interface{}{"rain"}},
+			expectedResult: `{"location":"Austin","temperature":21.5,"unit":"fahrenheit","days":5}`,
+		},
+		{
+			name:          "rejects missing required arguments",
+			arguments:     map[string]interface{}{"unit": "celsius"},
+			expectedError: "$.location: is required",
+		},
+		{
+			name:          "rejects values outside the enum",
+			arguments:     map[string]interface{}{"location": "Berlin", "unit": "kelvin"},
+			expectedError: "$.unit: must be one of",
+		},
+		{
+			name:          "rejects unknown arguments",
+			arguments:     map[string]interface{}{"location": "Berlin", "Internal": "secret"},
+			expectedError: "$.Internal: is not an allowed property",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolBox.ExecuteTool(context.Background(), "get_weather", tt.arguments)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result)
+		})
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"location": "Paris", "extra": true})
+	require.Error(t, err, "strict decoding rejects unknown fields even without schema validation")
+	assert.Empty(t, result)
+	assert.Equal(t, "Austin", received.Location)
+}
@@ -3,7 +3,9 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"time"
 
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
 	sdk "github.com/inference-gateway/sdk"
 )
 
@@ -39,14 +41,20 @@ type Tool interface {
 }
 
 // DefaultToolBox is a default implementation of ToolBox
+// Tool arguments are validated against the parameters schema of the tool before it is executed
 type DefaultToolBox struct {
-	tools map[string]Tool
+	tools          map[string]Tool
+	telemetry      otel.OpenTelemetry
+	telemetryAttrs otel.TelemetryAttributes
+	limits         ToolLimits
+	interceptors   []ToolInterceptor
 }
 
 // NewDefaultToolBox creates a new DefaultToolBox
 func NewDefaultToolBox() *DefaultToolBox {
 	return &DefaultToolBox{
-		tools: make(map[string]Tool),
+		tools:  make(map[string]Tool),
+		limits: DefaultToolLimits(),
 	}
 }
 
@@ -76,14 +84,55 @@ func (tb *DefaultToolBox) GetTools() []sdk.ChatCompletionTool {
 	return tools
 }
 
-// ExecuteTool executes a tool by name with the provided arguments
+// SetTelemetry records tool call failures with the given telemetry and attributes
+func (tb *DefaultToolBox) SetTelemetry(telemetry otel.OpenTelemetry, attrs otel.TelemetryAttributes) {
+	tb.telemetry = telemetry
+	tb.telemetryAttrs = attrs
+}
+
+// SetLimits replaces the timeout and output limits applied to tool calls
+func (tb *DefaultToolBox) SetLimits(limits ToolLimits) {
+	tb.limits = limits
+}
+
+// Use appends interceptors to the chain wrapping every tool call, the first interceptor is the outermost
+func (tb *DefaultToolBox) Use(interceptors ...ToolInterceptor) {
+	tb.interceptors = append(tb.interceptors, interceptors...)
+}
+
+// ExecuteTool executes a tool by name with the provided arguments, passing the call through the interceptors
+// It returns a ToolArgumentsValidationError without executing the tool if the arguments do not match its schema.
+// The call is stopped with a ToolTimeoutError at its timeout, a panic is returned as a ToolPanicError, and
+// outputs exceeding the output limit are summarized or truncated.
 func (tb *DefaultToolBox) ExecuteTool(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
+	return chainToolInterceptors(tb.interceptors, tb.executeTool)(ctx, toolName, arguments)
+}
+
+// executeTool validates the arguments and executes the tool at the end of the interceptor chain
+func (tb *DefaultToolBox) executeTool(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
 	tool, exists := tb.tools[toolName]
 	if !exists {
+		tb.recordToolCallFailure(ctx, toolName, &ToolNotFoundError{ToolName: toolName})
 		return "", &ToolNotFoundError{ToolName: toolName}
 	}
 
-	return tool.Execute(ctx, arguments)
+	if err := ValidateToolArguments(toolName, tool.GetParameters(), arguments); err != nil {
+		tb.recordToolCallFailure(ctx, toolName, err)
+		return "", err
+	}
+
+	return tb.executeWithLimits(ctx, tool, arguments)
+}
+
+// recordToolCallFailure counts a tool call that failed before reaching the tool
+func (tb *DefaultToolBox) recordToolCallFailure(ctx context.Context, toolName string, err error) {
+	if tb.telemetry == nil {
+		return
+	}
+
+	attrs := tb.telemetryAttrs
+	attrs.TaskID = taskIDFromContext(ctx)
+	tb.telemetry.RecordToolCallFailure(ctx, attrs, toolName, err.Error())
 }
 
 // GetToolNames returns a list of all available tool names
@@ -101,6 +150,18 @@ func (tb *DefaultToolBox) HasTool(toolName string) bool {
 	return exists
 }
 
+// IsSequential checks if the tool opted out of parallel execution
+func (tb *DefaultToolBox) IsSequential(toolName string) bool {
+	sequential, ok := tb.tools[toolName].(SequentialTool)
+	return ok && sequential.IsSequential()
+}
+
+// RequiresApproval checks if the user must approve calls to the tool before it is executed
+func (tb *DefaultToolBox) RequiresApproval(toolName string) bool {
+	approval, ok := tb.tools[toolName].(ApprovalRequiredTool)
+	return ok && approval.RequiresApproval()
+}
+
 // ToolNotFoundError represents an error when a requested tool is not found
 type ToolNotFoundError struct {
 	ToolName string
@@ -116,6 +177,33 @@ type BasicTool struct {
 	description string
 	parameters  map[string]interface{}
 	executor    func(ctx context.Context, arguments map[string]interface{}) (string, error)
+	sequential  bool
+	approval    bool
+	timeout     time.Duration
+}
+
+// BasicToolOption configures optional behavior of a BasicTool
+type BasicToolOption func(*BasicTool)
+
+// WithSequential marks the tool as having side effects so it never runs concurrently with other tool calls
+func WithSequential() BasicToolOption {
+	return func(t *BasicTool) {
+		t.sequential = true
+	}
+}
+
+// WithApprovalRequired pauses the task for the approval of the user before the tool is executed
+func WithApprovalRequired() BasicToolOption {
+	return func(t *BasicTool) {
+		t.approval = true
+	}
+}
+
+// WithTimeout overrides the default timeout of the toolbox for the tool
+func WithTimeout(timeout time.Duration) BasicToolOption {
+	return func(t *BasicTool) {
+		t.timeout = timeout
+	}
 }
 
 // NewBasicTool creates a new BasicTool
@@ -124,13 +212,18 @@ func NewBasicTool(
 	description string,
 	parameters map[string]interface{},
 	executor func(ctx context.Context, arguments map[string]interface{}) (string, error),
+	opts ...BasicToolOption,
 ) *BasicTool {
-	return &BasicTool{
+	tool := &BasicTool{
 		name:        name,
 		description: description,
 		parameters:  parameters,
 		executor:    executor,
 	}
+	for _, opt := range opts {
+		opt(tool)
+	}
+	return tool
 }
 
 func (t *BasicTool) GetName() string {
@@ -149,6 +242,18 @@ func (t *BasicTool) Execute(ctx context.Context, arguments map[string]interface{
 	return t.executor(ctx, arguments)
 }
 
+func (t *BasicTool) IsSequential() bool {
+	return t.sequential
+}
+
+func (t *BasicTool) RequiresApproval() bool {
+	return t.approval
+}
+
+func (t *BasicTool) GetTimeout() time.Duration {
+	return t.timeout
+}
+
 // JSONTool creates a tool result that can be marshaled to JSON
 func JSONTool(result interface{}) (string, error) {
 	data, err := json.Marshal(result)
@@ -0,0 +1,382 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/inference-gateway/sdk"
+	mcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	zap "go.uber.org/zap"
+)
+
+var _ ToolBox = (*MCPToolBox)(nil)
+
+// mcpRefreshTimeout bounds the refresh of the tools after a server reported that its tool list changed
+const mcpRefreshTimeout = 30 * time.Second
+
+// MCPServerConfig describes how to connect to a Model Context Protocol server
+// Exactly one of Command, URL or Transport must be set
+type MCPServerConfig struct {
+	// Name identifies the server in logs and errors
+	Name string
+
+	// Command and Args start the server as a subprocess speaking MCP over stdio
+	Command string
+	Args    []string
+	// Env is added to the environment of the subprocess, in KEY=value form
+	Env []string
+
+	// URL connects to the server over the streamable HTTP transport
+	URL string
+	// Headers are sent with every HTTP request, e.g. for authentication
+	Headers map[string]string
+	// HTTPClient is used for HTTP requests, defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	// Transport connects to the server over a custom transport
+	Transport mcp.Transport
+
+	// ToolPrefix is prepended to the names of the server tools to avoid conflicts between servers
+	ToolPrefix string
+}
+
+// transport returns the MCP transport for the server configuration
+func (c MCPServerConfig) transport() (mcp.Transport, error) {
+	configured := 0
+	for _, set := range []bool{c.Command != "", c.URL != "", c.Transport != nil} {
+		if set {
+			configured++
+		}
+	}
+	if configured != 1 {
+		return nil, fmt.Errorf("mcp server %q requires exactly one of command, url or transport", c.Name)
+	}
+
+	switch {
+	case c.Transport != nil:
+		return c.Transport, nil
+	case c.Command != "":
+		cmd := exec.Command(c.Command, c.Args...)
+		cmd.Env = append(os.Environ(), c.Env...)
+		return &mcp.CommandTransport{Command: cmd}, nil
+	default:
+		httpClient := c.HTTPClient
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		if len(c.Headers) > 0 {
+			base := httpClient.Transport
+			if base == nil {
+				base = http.DefaultTransport
+			}
+			client := *httpClient
+			client.Transport = &headerRoundTripper{headers: c.Headers, base: base}
+			httpClient = &client
+		}
+		return &mcp.StreamableClientTransport{Endpoint: c.URL, HTTPClient: httpClient}, nil
+	}
+}
+
+// headerRoundTripper adds static headers to every request
+type headerRoundTripper struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// mcpServer is a connected MCP server
+type mcpServer struct {
+	config  MCPServerConfig
+	session *mcp.ClientSession
+}
+
+// mcpTool is a tool offered by an MCP server
+type mcpTool struct {
+	server *mcpServer
+	tool   *mcp.Tool
+}
+
+// MCPToolError represents a tool call the MCP server reported as failed
+type MCPToolError struct {
+	ServerName string
+	ToolName   string
+	Message    string
+}
+
+func (e *MCPToolError) Error() string {
+	return fmt.Sprintf("mcp tool %s on server %s failed: %s", e.ToolName, e.ServerName, e.Message)
+}
+
+// MCPToolBox is a ToolBox exposing the tools of Model Context Protocol servers
+// The tool list is refreshed whenever a server reports that its tools changed
+type MCPToolBox struct {
+	logger *zap.Logger
+
+	// refreshMu runs refreshes one at a time, so an older tool list never replaces a newer one
+	refreshMu sync.Mutex
+
+	mu      sync.RWMutex
+	servers []*mcpServer
+	tools   map[string]mcpTool
+	names   []string
+}
+
+// NewMCPToolBox connects to the MCP servers and lists their tools
+// The connections stay open until Close is called
+func NewMCPToolBox(ctx context.Context, logger *zap.Logger, configs ...MCPServerConfig) (*MCPToolBox, error) {
+	tb := &MCPToolBox{
+		logger: logger,
+		tools:  make(map[string]mcpTool),
+	}
+
+	for _, cfg := range configs {
+		if err := tb.connect(ctx, cfg); err != nil {
+			_ = tb.Close()
+			return nil, err
+		}
+	}
+
+	if err := tb.Refresh(ctx); err != nil {
+		_ = tb.Close()
+		return nil, err
+	}
+
+	return tb, nil
+}
+
+// connect opens a session with an MCP server
+func (tb *MCPToolBox) connect(ctx context.Context, cfg MCPServerConfig) error {
+	transport, err := cfg.transport()
+	if err != nil {
+		return err
+	}
+
+	server := &mcpServer{config: cfg}
+	client := mcp.NewClient(&mcp.Implementation{Name: "inference-gateway-a2a", Version: BuildAgentVersion}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			// The handler runs on the connection, listing tools from it would block
+			go tb.refreshOnChange(server)
+		},
+	})
+
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to mcp server %q: %w", cfg.Name, err)
+	}
+	server.session = session
+
+	tb.mu.Lock()
+	tb.servers = append(tb.servers, server)
+	tb.mu.Unlock()
+
+	tb.logger.Info("connected to mcp server", zap.String("server", cfg.Name))
+	return nil
+}
+
+// refreshOnChange reloads the tools after a server reported that its tool list changed
+func (tb *MCPToolBox) refreshOnChange(server *mcpServer) {
+	tb.logger.Debug("mcp server tools changed, refreshing", zap.String("server", server.config.Name))
+
+	ctx, cancel := context.WithTimeout(context.Background(), mcpRefreshTimeout)
+	defer cancel()
+	if err := tb.Refresh(ctx); err != nil {
+		tb.logger.Error("failed to refresh mcp tools", zap.String("server", server.config.Name), zap.Error(err))
+	}
+}
+
+// Refresh lists the tools of all servers again
+// Concurrent refreshes run one after another
+func (tb *MCPToolBox) Refresh(ctx context.Context) error {
+	tb.refreshMu.Lock()
+	defer tb.refreshMu.Unlock()
+
+	tools := make(map[string]mcpTool)
+
+	for _, server := range tb.connectedServers() {
+		for tool, err := range server.session.Tools(ctx, nil) {
+			if err != nil {
+				return fmt.Errorf("failed to list tools of mcp server %q: %w", server.config.Name, err)
+			}
+
+			name := server.config.ToolPrefix + tool.Name
+			if existing, exists := tools[name]; exists {
+				tb.logger.Warn("mcp tool name conflict, keeping the first tool",
+					zap.String("tool", name),
+					zap.String("server", server.config.Name),
+					zap.String("existing_server", existing.server.config.Name))
+				continue
+			}
+			tools[name] = mcpTool{server: server, tool: tool}
+		}
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tb.mu.Lock()
+	tb.tools = tools
+	tb.names = names
+	tb.mu.Unlock()
+
+	tb.logger.Debug("mcp tools refreshed", zap.Int("count", len(names)))
+	return nil
+}
+
+// connectedServers returns a snapshot of the connected servers
+func (tb *MCPToolBox) connectedServers() []*mcpServer {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+	return append([]*mcpServer(nil), tb.servers...)
+}
+
+// lookup returns the tool with the given name
+func (tb *MCPToolBox) lookup(toolName string) (mcpTool, bool) {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+	tool, exists := tb.tools[toolName]
+	return tool, exists
+}
+
+// GetTools returns all available tools in OpenAI function call format
+func (tb *MCPToolBox) GetTools() []sdk.ChatCompletionTool {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	tools := make([]sdk.ChatCompletionTool, 0, len(tb.names))
+	for _, name := range tb.names {
+		tool := tb.tools[name].tool
+		description := tool.Description
+		parameters := mcpInputSchema(tool.InputSchema)
+
+		tools = append(tools, sdk.ChatCompletionTool{
+			Type: sdk.Function,
+			Function: sdk.FunctionObject{
+				Name:        name,
+				Description: &description,
+				Parameters:  (*sdk.FunctionParameters)(&parameters),
+			},
+		})
+	}
+	return tools
+}
+
+// ExecuteTool calls the tool on the MCP server offering it
+func (tb *MCPToolBox) ExecuteTool(ctx context.Context, toolName string, arguments map[string]interface{}) (string, error) {
+	tool, exists := tb.lookup(toolName)
+	if !exists {
+		return "", &ToolNotFoundError{ToolName: toolName}
+	}
+
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	result, err := tool.server.session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      tool.tool.Name,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to call mcp tool %s on server %s: %w", tool.tool.Name, tool.server.config.Name, err)
+	}
+
+	content := mcpResultContent(result)
+	if result.IsError {
+		return "", &MCPToolError{ServerName: tool.server.config.Name, ToolName: tool.tool.Name, Message: content}
+	}
+	return content, nil
+}
+
+// GetToolNames returns a list of all available tool names
+func (tb *MCPToolBox) GetToolNames() []string {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+	return append([]string(nil), tb.names...)
+}
+
+// HasTool checks if a tool with the given name exists
+func (tb *MCPToolBox) HasTool(toolName string) bool {
+	_, exists := tb.lookup(toolName)
+	return exists
+}
+
+// IsSequential checks if the MCP server annotated the tool as modifying its environment destructively
+func (tb *MCPToolBox) IsSequential(toolName string) bool {
+	tool, exists := tb.lookup(toolName)
+	if !exists || tool.tool.Annotations == nil {
+		return false
+	}
+
+	annotations := tool.tool.Annotations
+	if annotations.ReadOnlyHint {
+		return false
+	}
+	return annotations.DestructiveHint == nil || *annotations.DestructiveHint
+}
+
+// Close closes the sessions with all MCP servers, stopping stdio subprocesses
+func (tb *MCPToolBox) Close() error {
+	var errs []error
+	for _, server := range tb.connectedServers() {
+		if err := server.session.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close mcp server %q: %w", server.config.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// mcpInputSchema converts the input schema of an MCP tool to function parameters
+func mcpInputSchema(schema any) map[string]interface{} {
+	if parameters, ok := schema.(map[string]interface{}); ok {
+		return parameters
+	}
+
+	parameters := map[string]interface{}{"type": "object"}
+	data, err := json.Marshal(schema)
+	if err != nil || string(data) == "null" {
+		return parameters
+	}
+	_ = json.Unmarshal(data, &parameters)
+	return parameters
+}
+
+// mcpResultContent flattens the content of an MCP tool result into a single string
+// Text content is returned as is, other content and structured content as JSON
+func mcpResultContent(result *mcp.CallToolResult) string {
+	parts := make([]string, 0, len(result.Content))
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+			continue
+		}
+		if data, err := json.Marshal(content); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+
+	if len(parts) == 0 && result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
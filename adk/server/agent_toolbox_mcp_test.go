@@ -0,0 +1,199 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var mcpObjectSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"text": map[string]interface{}{"type": "string"},
+	},
+}
+
+func mcpTextResult(text string, isError bool) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: isError,
+	}
+}
+
+func newMCPTestServer(t *testing.T) (*mcp.Server, mcp.Transport) {
+	t.Helper()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	mcpServer.AddTool(&mcp.Tool{Name: "echo", Description: "Echo the text", InputSchema: mcpObjectSchema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcpTextResult("echo: "+string(req.Params.Arguments), false), nil
+		})
+	mcpServer.AddTool(&mcp.Tool{Name: "fail", Description: "Always fails", InputSchema: mcpObjectSchema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcpTextResult("something broke", true), nil
+		})
+
+	destructive := true
+	mcpServer.AddTool(&mcp.Tool{
+		Name:        "delete",
+		Description: "Delete a record",
+		InputSchema: mcpObjectSchema,
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &destructive},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcpTextResult("deleted", false), nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := mcpServer.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return mcpServer, clientTransport
+}
+
+func TestMCPToolBox(t *testing.T) {
+	ctx := context.Background()
+	_, transport := newMCPTestServer(t)
+
+	toolBox, err := server.NewMCPToolBox(ctx, zap.NewNop(), server.MCPServerConfig{
+		Name:       "test",
+		Transport:  transport,
+		ToolPrefix: "test_",
+	})
+	require.NoError(t, err)
+	defer func() { _ = toolBox.Close() }()
+
+	assert.Equal(t, []string{"test_delete", "test_echo", "test_fail"}, toolBox.GetToolNames())
+	assert.True(t, toolBox.HasTool("test_echo"))
+	assert.False(t, toolBox.HasTool("echo"), "tools are exposed with the configured prefix")
+
+	tools := toolBox.GetTools()
+	require.Len(t, tools, 3)
+	assert.Equal(t, "test_echo", tools[1].Function.Name)
+	assert.Equal(t, "Echo the text", *tools[1].Function.Description)
+	assert.Equal(t, "object", (*tools[1].Function.Parameters)["type"])
+
+	result, err := toolBox.ExecuteTool(ctx, "test_echo", map[string]interface{}{"text": "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, `echo: {"text":"hello"}`, result)
+
+	_, err = toolBox.ExecuteTool(ctx, "test_fail", map[string]interface{}{})
+	var toolErr *server.MCPToolError
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, "something broke", toolErr.Message)
+
+	_, err = toolBox.ExecuteTool(ctx, "test_missing", nil)
+	var notFoundErr *server.ToolNotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	assert.True(t, toolBox.IsSequential("test_delete"), "destructive tools run on their own")
+	assert.False(t, toolBox.IsSequential("test_echo"))
+}
+
+func TestMCPToolBox_RefreshesOnToolListChange(t *testing.T) {
+	ctx := context.Background()
+	mcpServer, transport := newMCPTestServer(t)
+
+	toolBox, err := server.NewMCPToolBox(ctx, zap.NewNop(), server.MCPServerConfig{Name: "test", Transport: transport})
+	require.NoError(t, err)
+	defer func() { _ = toolBox.Close() }()
+
+	require.False(t, toolBox.HasTool("reverse"))
+
+	mcpServer.AddTool(&mcp.Tool{Name: "reverse", Description: "Reverse the text", InputSchema: mcpObjectSchema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcpTextResult("esrever", false), nil
+		})
+	assert.Eventually(t, func() bool { return toolBox.HasTool("reverse") }, 2*time.Second, 10*time.Millisecond)
+
+	mcpServer.RemoveTools("fail")
+	assert.Eventually(t, func() bool { return !toolBox.HasTool("fail") }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestMCPToolBox_ConcurrentRefresh(t *testing.T) {
+	ctx := context.Background()
+	mcpServer, transport := newMCPTestServer(t)
+
+	toolBox, err := server.NewMCPToolBox(ctx, zap.NewNop(), server.MCPServerConfig{Name: "test", Transport: transport})
+	require.NoError(t, err)
+	defer func() { _ = toolBox.Close() }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, toolBox.Refresh(ctx))
+		}()
+	}
+	mcpServer.AddTool(&mcp.Tool{Name: "reverse", Description: "Reverse the text", InputSchema: mcpObjectSchema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcpTextResult("esrever", false), nil
+		})
+	wg.Wait()
+
+	require.NoError(t, toolBox.Refresh(ctx))
+	assert.True(t, toolBox.HasTool("reverse"), "the last refresh lists the latest tools")
+}
+
+func TestMCPToolBox_StreamableHTTP(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "http-server", Version: "1.0.0"}, nil)
+	mcpServer.AddTool(&mcp.Tool{Name: "ping", Description: "Ping", InputSchema: mcpObjectSchema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcpTextResult("pong", false), nil
+		})
+
+	var unauthorized atomic.Int32
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			unauthorized.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	toolBox, err := server.NewMCPToolBox(ctx, zap.NewNop(), server.MCPServerConfig{
+		Name:    "http",
+		URL:     httpServer.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = toolBox.Close() }()
+
+	result, err := toolBox.ExecuteTool(ctx, "ping", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", result)
+	assert.Zero(t, unauthorized.Load())
+}
+
+func TestNewMCPToolBox_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config server.MCPServerConfig
+	}{
+		{name: "no transport", config: server.MCPServerConfig{Name: "empty"}},
+		{name: "command and url", config: server.MCPServerConfig{Name: "both", Command: "mcp-server", URL: "http://localhost:8080/mcp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.NewMCPToolBox(context.Background(), zap.NewNop(), tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "requires exactly one of command, url or transport")
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	sdk "github.com/inference-gateway/sdk"
+)
+
+// MetadataTokenCostKey is the task metadata key holding the cost of the tokens used by the LLM for the task
+const MetadataTokenCostKey = "tokenCost"
+
+// Scopes of the LLM budgets
+const (
+	BudgetScopeTask      = "task"
+	BudgetScopeContext   = "context"
+	BudgetScopePrincipal = "principal"
+)
+
+// Resources limited by the LLM budgets
+const (
+	BudgetResourceTokens = "token"
+	BudgetResourceCost   = "cost"
+)
+
+// ModelPrice is the price of a model per million prompt and completion tokens
+type ModelPrice struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+// Cost returns the price of the tokens used by a completion
+func (p ModelPrice) Cost(usage sdk.CompletionUsage) float64 {
+	return (float64(usage.PromptTokens)*p.PromptPerMillion + float64(usage.CompletionTokens)*p.CompletionPerMillion) / 1_000_000
+}
+
+// ParseModelPrices parses a price table of models to prompt/completion prices per million tokens, such as gpt-4o: 2.5/10
+func ParseModelPrices(prices map[string]string) (map[string]ModelPrice, error) {
+	parsed := make(map[string]ModelPrice, len(prices))
+	for model, price := range prices {
+		prompt, completion, ok := strings.Cut(price, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid price %q of model %s: expected prompt/completion price per million tokens", price, model)
+		}
+
+		promptPrice, err := strconv.ParseFloat(strings.TrimSpace(prompt), 64)
+		if err != nil || promptPrice < 0 {
+			return nil, fmt.Errorf("invalid prompt price %q of model %s", prompt, model)
+		}
+		completionPrice, err := strconv.ParseFloat(strings.TrimSpace(completion), 64)
+		if err != nil || completionPrice < 0 {
+			return nil, fmt.Errorf("invalid completion price %q of model %s", completion, model)
+		}
+
+		parsed[parseModelName(strings.TrimSpace(model), "")] = ModelPrice{
+			PromptPerMillion:     promptPrice,
+			CompletionPerMillion: completionPrice,
+		}
+	}
+	return parsed, nil
+}
+
+// BudgetStatus reports the tokens and cost used in a budget and its limits, a zero limit is unlimited
+type BudgetStatus struct {
+	Scope      string     `json:"scope"`
+	Key        string     `json:"key"`
+	TokensUsed int64      `json:"tokens_used"`
+	TokenLimit int64      `json:"token_limit,omitempty"`
+	CostUsed   float64    `json:"cost_used"`
+	CostLimit  float64    `json:"cost_limit,omitempty"`
+	ResetsAt   *time.Time `json:"resets_at,omitempty"`
+}
+
+// Err returns a BudgetExceededError if the tokens or the cost of the budget are used up
+func (s BudgetStatus) Err() error {
+	if s.TokenLimit > 0 && s.TokensUsed >= s.TokenLimit {
+		return NewBudgetExceededError(s.Scope, BudgetResourceTokens, float64(s.TokensUsed), float64(s.TokenLimit), s.ResetsAt)
+	}
+	if s.CostLimit > 0 && s.CostUsed >= s.CostLimit {
+		return NewBudgetExceededError(s.Scope, BudgetResourceCost, s.CostUsed, s.CostLimit, s.ResetsAt)
+	}
+	return nil
+}
+
+// TokenBudget decides whether a task may request further LLM completions
+type TokenBudget interface {
+	// CheckBudget returns a BudgetExceededError if a budget of the task is used up
+	CheckBudget(ctx context.Context, task *adk.Task) error
+}
+
+// tokenBudgetContextKey is the context key of the budget of the task being processed
+type tokenBudgetContextKey struct{}
+
+// WithTokenBudget returns a copy of ctx carrying the budget of the task being processed
+func WithTokenBudget(ctx context.Context, budget TokenBudget) context.Context {
+	return context.WithValue(ctx, tokenBudgetContextKey{}, budget)
+}
+
+// checkTokenBudget checks the budget of ctx before a completion, it allows the completion if ctx carries no budget
+func checkTokenBudget(ctx context.Context, task *adk.Task) error {
+	budget, ok := ctx.Value(tokenBudgetContextKey{}).(TokenBudget)
+	if !ok || budget == nil {
+		return nil
+	}
+	return budget.CheckBudget(ctx, task)
+}
+
+// budgetExhaustedMessage is the failure message of a task stopped by an exhausted budget or an unpriced model
+func budgetExhaustedMessage(err error) string {
+	var unpricedErr *UnpricedModelError
+	if errors.As(err, &unpricedErr) {
+		return fmt.Sprintf("The task was stopped because its LLM cost cannot be tracked: %v", err)
+	}
+	return fmt.Sprintf("The task was stopped because its LLM budget is used up: %v", err)
+}
+
+var _ TokenBudget = (*BudgetTracker)(nil)
+
+// BudgetTracker enforces the token and cost budgets per task, per context per day and per principal per month.
+// Days and months are calendar periods in UTC.
+type BudgetTracker struct {
+	cfg          config.BudgetConfig
+	prices       map[string]ModelPrice
+	defaultModel string
+	now          func() time.Time
+
+	mu         sync.Mutex
+	contexts   map[string]*budgetWindow
+	principals map[string]*budgetWindow
+}
+
+// budgetWindow is the usage of a context or principal in the current period
+type budgetWindow struct {
+	start  time.Time
+	tokens int64
+	cost   float64
+}
+
+// NewBudgetTracker creates a budget tracker, the default model is priced for tasks that did not record their model
+func NewBudgetTracker(cfg config.BudgetConfig, defaultModel string) (*BudgetTracker, error) {
+	prices, err := ParseModelPrices(cfg.ModelPrices)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasCostLimit(cfg) && len(prices) == 0 {
+		return nil, fmt.Errorf("cost limits require model prices")
+	}
+
+	return &BudgetTracker{
+		cfg:          cfg,
+		prices:       prices,
+		defaultModel: parseModelName(defaultModel, ""),
+		now:          time.Now,
+		contexts:     make(map[string]*budgetWindow),
+		principals:   make(map[string]*budgetWindow),
+	}, nil
+}
+
+// Record implements TokenUsageReporter, charging the usage to the task, its context and its principal
+func (b *BudgetTracker) Record(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage) {
+	cost := b.cost(ctx, task, usage)
+	if cost > 0 {
+		SetTaskMetadata(ctx, task, MetadataTokenCostKey, taskTokenCost(task)+cost)
+	}
+
+	now := b.now().UTC()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	contextWindow := b.window(b.contexts, task.ContextID, startOfDay(now))
+	contextWindow.tokens += usage.TotalTokens
+	contextWindow.cost += cost
+
+	if principal := requestPrincipal(ctx); principal != "" {
+		window := b.window(b.principals, principal, startOfMonth(now))
+		window.tokens += usage.TotalTokens
+		window.cost += cost
+	}
+}
+
+// CheckBudget implements TokenBudget
+// With a cost limit, completions requested with a model without a price are rejected with an UnpricedModelError
+func (b *BudgetTracker) CheckBudget(ctx context.Context, task *adk.Task) error {
+	if hasCostLimit(b.cfg) {
+		if model := b.requestedModel(ctx); !b.Priced(model) {
+			return NewUnpricedModelError(model)
+		}
+	}
+	if err := b.TaskBudget(task).Err(); err != nil {
+		return err
+	}
+	if err := b.ContextBudget(task.ContextID).Err(); err != nil {
+		return err
+	}
+	if principal := requestPrincipal(ctx); principal != "" {
+		return b.PrincipalBudget(principal).Err()
+	}
+	return nil
+}
+
+// TaskBudget returns the budget status of the task
+func (b *BudgetTracker) TaskBudget(task *adk.Task) BudgetStatus {
+	return BudgetStatus{
+		Scope:      BudgetScopeTask,
+		Key:        task.ID,
+		TokensUsed: TaskTokenUsage(task).TotalTokens,
+		TokenLimit: b.cfg.MaxTokensPerTask,
+		CostUsed:   taskTokenCost(task),
+		CostLimit:  b.cfg.MaxCostPerTask,
+	}
+}
+
+// ContextBudget returns the budget status of the context for the current day
+func (b *BudgetTracker) ContextBudget(contextID string) BudgetStatus {
+	start := startOfDay(b.now().UTC())
+	return b.status(BudgetScopeContext, contextID, b.contexts, start, start.AddDate(0, 0, 1),
+		b.cfg.MaxTokensPerContextPerDay, b.cfg.MaxCostPerContextPerDay)
+}
+
+// PrincipalBudget returns the budget status of the principal, identified by its owner, for the current month
+func (b *BudgetTracker) PrincipalBudget(owner string) BudgetStatus {
+	start := startOfMonth(b.now().UTC())
+	return b.status(BudgetScopePrincipal, owner, b.principals, start, start.AddDate(0, 1, 0),
+		b.cfg.MaxTokensPerPrincipalPerMonth, b.cfg.MaxCostPerPrincipalPerMonth)
+}
+
+// status returns the usage of the key in the period starting at start
+func (b *BudgetTracker) status(scope string, key string, windows map[string]*budgetWindow, start time.Time, resetsAt time.Time, tokenLimit int64, costLimit float64) BudgetStatus {
+	status := BudgetStatus{
+		Scope:      scope,
+		Key:        key,
+		TokenLimit: tokenLimit,
+		CostLimit:  costLimit,
+		ResetsAt:   &resetsAt,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if window, ok := windows[key]; ok && window.start.Equal(start) {
+		status.TokensUsed = window.tokens
+		status.CostUsed = window.cost
+	}
+	return status
+}
+
+// window returns the usage of the key in the period starting at start, discarding the usage of past periods
+func (b *BudgetTracker) window(windows map[string]*budgetWindow, key string, start time.Time) *budgetWindow {
+	window, ok := windows[key]
+	if ok && window.start.Equal(start) {
+		return window
+	}
+
+	for otherKey, other := range windows {
+		if other.start.Before(start) {
+			delete(windows, otherKey)
+		}
+	}
+
+	window = &budgetWindow{start: start}
+	windows[key] = window
+	return window
+}
+
+// Priced reports whether the model, with or without its provider prefix, has a price
+func (b *BudgetTracker) Priced(model string) bool {
+	_, ok := b.prices[parseModelName(model, "")]
+	return ok
+}
+
+// cost prices the usage with the model that answered the task, or else the model it was processed
+// or requested with, so answers of fallback models without a price are charged at the requested price
+func (b *BudgetTracker) cost(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage) float64 {
+	models := []string{}
+	if model, ok := task.Metadata[MetadataLLMModelKey].(string); ok && model != "" {
+		models = append(models, parseModelName(model, ""))
+	}
+	if options, ok := task.Metadata[MetadataLLMRequestKey].(LLMRequestOptions); ok && options.Model != "" {
+		models = append(models, parseModelName(options.Model, ""))
+	}
+	models = append(models, b.requestedModel(ctx))
+
+	for _, model := range models {
+		if price, ok := b.prices[model]; ok {
+			return price.Cost(usage)
+		}
+	}
+	return 0
+}
+
+// requestedModel returns the model completions are requested with, the default model unless the request overrides it
+func (b *BudgetTracker) requestedModel(ctx context.Context) string {
+	if options, ok := LLMRequestOptionsFromContext(ctx); ok && options.Model != "" {
+		return parseModelName(options.Model, "")
+	}
+	return b.defaultModel
+}
+
+// hasCostLimit reports whether a cost limit is set
+func hasCostLimit(cfg config.BudgetConfig) bool {
+	return cfg.MaxCostPerTask > 0 || cfg.MaxCostPerContextPerDay > 0 || cfg.MaxCostPerPrincipalPerMonth > 0
+}
+
+// taskTokenCost returns the cost of the tokens used by the LLM for the task so far
+func taskTokenCost(task *adk.Task) float64 {
+	cost, _ := task.Metadata[MetadataTokenCostKey].(float64)
+	return cost
+}
+
+// startOfDay returns the start of the day of t
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfMonth returns the start of the month of t
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,279 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseModelPrices(t *testing.T) {
+	tests := []struct {
+		name          string
+		prices        map[string]string
+		expected      map[string]server.ModelPrice
+		expectedError string
+	}{
+		{
+			name:   "prices per million tokens",
+			prices: map[string]string{"gpt-4o": "2.5/10", "openai/gpt-4o-mini": " 0.15 / 0.6 "},
+			expected: map[string]server.ModelPrice{
+				"gpt-4o":      {PromptPerMillion: 2.5, CompletionPerMillion: 10},
+				"gpt-4o-mini": {PromptPerMillion: 0.15, CompletionPerMillion: 0.6},
+			},
+		},
+		{
+			name:          "missing completion price",
+			prices:        map[string]string{"gpt-4o": "2.5"},
+			expectedError: "expected prompt/completion price per million tokens",
+		},
+		{
+			name:          "negative price",
+			prices:        map[string]string{"gpt-4o": "-1/10"},
+			expectedError: `invalid prompt price "-1" of model gpt-4o`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices, err := server.ParseModelPrices(tt.prices)
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, prices)
+		})
+	}
+}
+
+func TestBudgetTracker(t *testing.T) {
+	usage := sdk.CompletionUsage{PromptTokens: 800_000, CompletionTokens: 200_000, TotalTokens: 1_000_000}
+
+	tests := []struct {
+		name             string
+		cfg              config.BudgetConfig
+		expectedScope    string
+		expectedResource string
+	}{
+		{
+			name: "within budget",
+			cfg:  config.BudgetConfig{MaxTokensPerTask: 2_000_000, MaxTokensPerContextPerDay: 2_000_000, MaxTokensPerPrincipalPerMonth: 2_000_000},
+		},
+		{
+			name:             "task tokens",
+			cfg:              config.BudgetConfig{MaxTokensPerTask: 1_000_000},
+			expectedScope:    server.BudgetScopeTask,
+			expectedResource: server.BudgetResourceTokens,
+		},
+		{
+			name:             "context tokens per day",
+			cfg:              config.BudgetConfig{MaxTokensPerContextPerDay: 500_000},
+			expectedScope:    server.BudgetScopeContext,
+			expectedResource: server.BudgetResourceTokens,
+		},
+		{
+			name:             "principal tokens per month",
+			cfg:              config.BudgetConfig{MaxTokensPerPrincipalPerMonth: 1_000_000},
+			expectedScope:    server.BudgetScopePrincipal,
+			expectedResource: server.BudgetResourceTokens,
+		},
+		{
+			name:             "principal cost per month",
+			cfg:              config.BudgetConfig{MaxCostPerPrincipalPerMonth: 3, ModelPrices: map[string]string{"gpt-4o": "2.5/5"}},
+			expectedScope:    server.BudgetScopePrincipal,
+			expectedResource: server.BudgetResourceCost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := server.NewBudgetTracker(tt.cfg, "openai/gpt-4o")
+			require.NoError(t, err)
+
+			ctx := middlewares.WithPrincipal(context.Background(), &middlewares.Principal{Subject: "team-a"})
+			task := &adk.Task{ID: "task-1", ContextID: "ctx-1"}
+			require.NoError(t, budget.CheckBudget(ctx, task))
+
+			server.RecordTokenUsage(server.WithTokenUsageReporter(ctx, budget.Record), task, &usage)
+
+			err = budget.CheckBudget(ctx, task)
+			if tt.expectedScope == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var exceeded *server.BudgetExceededError
+			require.ErrorAs(t, err, &exceeded)
+			assert.Equal(t, tt.expectedScope, exceeded.Scope)
+			assert.Equal(t, tt.expectedResource, exceeded.Resource)
+		})
+	}
+}
+
+func TestBudgetTracker_Status(t *testing.T) {
+	budget, err := server.NewBudgetTracker(config.BudgetConfig{
+		MaxTokensPerContextPerDay:     1000,
+		MaxTokensPerPrincipalPerMonth: 5000,
+		MaxCostPerTask:                1,
+		ModelPrices:                   map[string]string{"gpt-4o": "2.5/10", "gpt-4o-mini": "0.15/0.6"},
+	}, "gpt-4o")
+	require.NoError(t, err)
+
+	ctx := middlewares.WithPrincipal(context.Background(), &middlewares.Principal{Subject: "team-a"})
+	task := &adk.Task{ID: "task-1", ContextID: "ctx-1", Metadata: map[string]interface{}{
+		server.MetadataLLMRequestKey: server.LLMRequestOptions{Model: "gpt-4o-mini"},
+	}}
+	budget.Record(ctx, task, sdk.CompletionUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150})
+	budget.Record(context.Background(), &adk.Task{ID: "task-2", ContextID: "ctx-2"}, sdk.CompletionUsage{TotalTokens: 10})
+
+	taskStatus := budget.TaskBudget(task)
+	assert.InDelta(t, 0.000045, taskStatus.CostUsed, 1e-12, "the task is priced with the model it used")
+	assert.Equal(t, 1.0, taskStatus.CostLimit)
+
+	contextStatus := budget.ContextBudget("ctx-1")
+	assert.Equal(t, int64(150), contextStatus.TokensUsed)
+	assert.Equal(t, int64(1000), contextStatus.TokenLimit)
+	require.NotNil(t, contextStatus.ResetsAt)
+	assert.True(t, contextStatus.ResetsAt.After(time.Now()))
+	assert.LessOrEqual(t, time.Until(*contextStatus.ResetsAt), 24*time.Hour)
+
+	principalStatus := budget.PrincipalBudget("team-a")
+	assert.Equal(t, int64(150), principalStatus.TokensUsed)
+	assert.Equal(t, int64(5000), principalStatus.TokenLimit)
+	assert.Equal(t, int64(0), budget.PrincipalBudget("team-b").TokensUsed)
+}
+
+func TestBudgetTracker_PricesAnsweringModel(t *testing.T) {
+	budget, err := server.NewBudgetTracker(config.BudgetConfig{
+		ModelPrices: map[string]string{"gpt-4o": "2.5/10", "claude-3-5-haiku": "0.8/4"},
+	}, "gpt-4o")
+	require.NoError(t, err)
+
+	usage := sdk.CompletionUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000, TotalTokens: 2_000_000}
+
+	fallback := &adk.Task{ID: "task-1", Metadata: map[string]interface{}{
+		server.MetadataLLMRequestKey: server.LLMRequestOptions{Model: "gpt-4o"},
+		server.MetadataLLMModelKey:   "anthropic/claude-3-5-haiku",
+	}}
+	budget.Record(context.Background(), fallback, usage)
+	assert.InDelta(t, 4.8, budget.TaskBudget(fallback).CostUsed, 1e-9, "the model that answered is priced")
+
+	unpriced := &adk.Task{ID: "task-2", Metadata: map[string]interface{}{
+		server.MetadataLLMRequestKey: server.LLMRequestOptions{Model: "gpt-4o"},
+		server.MetadataLLMModelKey:   "gpt-4o-2024-08-06",
+	}}
+	budget.Record(context.Background(), unpriced, usage)
+	assert.InDelta(t, 12.5, budget.TaskBudget(unpriced).CostUsed, 1e-9, "the requested model is priced when the answering model has no price")
+}
+
+func TestNewBudgetTracker_CostLimitRequiresPrices(t *testing.T) {
+	_, err := server.NewBudgetTracker(config.BudgetConfig{MaxCostPerContextPerDay: 5}, "gpt-4o")
+	assert.EqualError(t, err, "cost limits require model prices")
+}
+
+func TestBudgetTracker_RejectsUnpricedModels(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.BudgetConfig
+		model         string
+		expectedModel string
+	}{
+		{
+			name: "priced default model",
+			cfg:  config.BudgetConfig{MaxCostPerTask: 1, ModelPrices: map[string]string{"gpt-4o": "2.5/10"}},
+		},
+		{
+			name:  "priced override with provider prefix",
+			cfg:   config.BudgetConfig{MaxCostPerTask: 1, ModelPrices: map[string]string{"gpt-4o": "2.5/10", "gpt-4o-mini": "0.15/0.6"}},
+			model: "openai/gpt-4o-mini",
+		},
+		{
+			name:          "unpriced override",
+			cfg:           config.BudgetConfig{MaxCostPerTask: 1, ModelPrices: map[string]string{"gpt-4o": "2.5/10"}},
+			model:         "llama-3",
+			expectedModel: "llama-3",
+		},
+		{
+			name:          "unpriced default model",
+			cfg:           config.BudgetConfig{MaxCostPerContextPerDay: 5, ModelPrices: map[string]string{"gpt-4o-mini": "0.15/0.6"}},
+			expectedModel: "gpt-4o",
+		},
+		{
+			name:  "unpriced model without cost limits",
+			cfg:   config.BudgetConfig{MaxTokensPerTask: 1000},
+			model: "llama-3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := server.NewBudgetTracker(tt.cfg, "gpt-4o")
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tt.model != "" {
+				ctx = server.WithLLMRequestOptions(ctx, &server.LLMRequestOptions{Model: tt.model})
+			}
+
+			err = budget.CheckBudget(ctx, &adk.Task{ID: "task-1", ContextID: "ctx-1"})
+			if tt.expectedModel == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var unpriced *server.UnpricedModelError
+			require.ErrorAs(t, err, &unpriced)
+			assert.Equal(t, tt.expectedModel, unpriced.Model)
+		})
+	}
+}
+
+func TestNewA2AServerEnvironmentAware_EnforcesBudgets(t *testing.T) {
+	a2aServer := server.NewA2AServerEnvironmentAware(&config.Config{
+		BudgetConfig: config.BudgetConfig{Enable: true, MaxTokensPerContextPerDay: 100},
+	}, zap.NewNop(), nil)
+	require.NotNil(t, a2aServer.GetBudgetTracker())
+
+	a2aServer.GetBudgetTracker().Record(context.Background(), &adk.Task{ID: "task-1", ContextID: "ctx-env"}, sdk.CompletionUsage{TotalTokens: 150})
+
+	var exceeded *server.BudgetExceededError
+	require.ErrorAs(t, a2aServer.GetBudgetTracker().CheckBudget(context.Background(), &adk.Task{ID: "task-2", ContextID: "ctx-env"}), &exceeded)
+	assert.Equal(t, server.BudgetScopeContext, exceeded.Scope)
+}
+
+func TestDefaultOpenAICompatibleAgent_StopsWhenBudgetExhausted(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "record 7", nil
+		}))
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturns(withUsage(newToolCallResponse("lookup"), 900, 200), nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithToolBox(toolBox).Build()
+	require.NoError(t, err)
+
+	budget, err := server.NewBudgetTracker(config.BudgetConfig{MaxTokensPerTask: 1000}, "gpt-4o")
+	require.NoError(t, err)
+	ctx := server.WithTokenUsageReporter(server.WithTokenBudget(context.Background(), budget), budget.Record)
+
+	task, err := agent.ProcessTask(ctx, &adk.Task{ID: "task-1", ContextID: "ctx-1"}, textMessage("user", "Find record 7"))
+	require.NoError(t, err)
+
+	assert.Equal(t, adk.TaskStateFailed, task.Status.State)
+	assert.Equal(t, 1, llmClient.CreateChatCompletionCallCount(), "no completion is requested once the budget is used up")
+	text := task.Status.Message.Parts[0].(map[string]interface{})["text"].(string)
+	assert.Contains(t, text, "task token budget exhausted: 1100 of 1000 used")
+}
@@ -10,20 +10,23 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	AgentName                     string             // Build-time metadata, not configurable via environment
-	AgentDescription              string             // Build-time metadata, not configurable via environment
-	AgentVersion                  string             // Build-time metadata, not configurable via environment
-	AgentURL                      string             `env:"AGENT_URL"`
-	AgentCardFilePath             string             `env:"AGENT_CARD_FILE_PATH" description:"Path to JSON file containing static agent card definition"`
-	Debug                         bool               `env:"DEBUG,default=false"`
-	Timezone                      string             `env:"TIMEZONE,default=UTC" description:"Timezone for timestamps (e.g., UTC, America/New_York, Europe/London)"`
-	StreamingStatusUpdateInterval time.Duration      `env:"STREAMING_STATUS_UPDATE_INTERVAL,default=1s"`
-	AgentConfig                   AgentConfig        `env:",prefix=AGENT_CLIENT_"`
-	CapabilitiesConfig            CapabilitiesConfig `env:",prefix=CAPABILITIES_"`
-	AuthConfig                    AuthConfig         `env:",prefix=AUTH_"`
-	QueueConfig                   QueueConfig        `env:",prefix=QUEUE_"`
-	ServerConfig                  ServerConfig       `env:",prefix=SERVER_"`
-	TelemetryConfig               TelemetryConfig    `env:",prefix=TELEMETRY_"`
+	AgentName                     string               // Build-time metadata, not configurable via environment
+	AgentDescription              string               // Build-time metadata, not configurable via environment
+	AgentVersion                  string               // Build-time metadata, not configurable via environment
+	AgentURL                      string               `env:"AGENT_URL"`
+	AgentCardFilePath             string               `env:"AGENT_CARD_FILE_PATH" description:"Path to JSON file containing static agent card definition"`
+	Debug                         bool                 `env:"DEBUG,default=false"`
+	Timezone                      string               `env:"TIMEZONE,default=UTC" description:"Timezone for timestamps (e.g., UTC, America/New_York, Europe/London)"`
+	StreamingStatusUpdateInterval time.Duration        `env:"STREAMING_STATUS_UPDATE_INTERVAL,default=1s"`
+	AgentConfig                   AgentConfig          `env:",prefix=AGENT_CLIENT_"`
+	CapabilitiesConfig            CapabilitiesConfig   `env:",prefix=CAPABILITIES_"`
+	AuthConfig                    AuthConfig           `env:",prefix=AUTH_"`
+	BudgetConfig                  BudgetConfig         `env:",prefix=BUDGET_"`
+	CircuitBreakerConfig          CircuitBreakerConfig `env:",prefix=CIRCUIT_BREAKER_"`
+	QueueConfig                   QueueConfig          `env:",prefix=QUEUE_"`
+	RateLimitConfig               RateLimitConfig      `env:",prefix=RATE_LIMIT_"`
+	ServerConfig                  ServerConfig         `env:",prefix=SERVER_"`
+	TelemetryConfig               TelemetryConfig      `env:",prefix=TELEMETRY_"`
 }
 
 // AgentConfig holds agent-specific configuration
@@ -34,7 +37,10 @@ type AgentConfig struct {
 	APIKey                      string            `env:"API_KEY" description:"API key for authentication"`
 	Timeout                     time.Duration     `env:"TIMEOUT,default=30s" description:"Client timeout for requests"`
 	MaxRetries                  int               `env:"MAX_RETRIES,default=3" description:"Maximum number of retries"`
+	RetryInitialBackoff         time.Duration     `env:"RETRY_INITIAL_BACKOFF,default=1s" description:"Backoff before the first retry, doubled on each further retry"`
+	RetryMaxBackoff             time.Duration     `env:"RETRY_MAX_BACKOFF,default=30s" description:"Maximum backoff between retries"`
 	MaxChatCompletionIterations int               `env:"MAX_CHAT_COMPLETION_ITERATIONS,default=10" description:"Maximum chat completion iterations"`
+	MaxParallelToolCalls        int               `env:"MAX_PARALLEL_TOOL_CALLS,default=1" description:"Maximum tool calls of one LLM turn executed concurrently, 1 runs them one after another"`
 	CustomHeaders               map[string]string `env:"CUSTOM_HEADERS" description:"Custom headers to include in requests"`
 	TLSConfig                   ClientTLSConfig   `env:",prefix=TLS_" description:"TLS configuration for client"`
 	ProxyURL                    string            `env:"PROXY_URL" description:"Proxy URL for requests"`
@@ -46,6 +52,33 @@ type AgentConfig struct {
 	PresencePenalty             float64           `env:"PRESENCE_PENALTY,default=0.0" description:"Presence penalty for completion"`
 	SystemPrompt                string            `env:"SYSTEM_PROMPT,default=You are a helpful AI assistant processing an A2A (Agent-to-Agent) task. Please provide helpful and accurate responses." description:"System prompt for LLM interactions"`
 	MaxConversationHistory      int               `env:"MAX_CONVERSATION_HISTORY,default=20" description:"Maximum number of messages to keep in conversation history per context"`
+	FallbackModels              []string          `env:"FALLBACK_MODELS" description:"Ordered provider/model pairs tried when a model fails with a timeout, connection error, rate limit or server error"`
+	Routing                     LLMRoutingConfig  `env:",prefix=ROUTING_" description:"Routing of completions to models"`
+	AllowedModels               []string          `env:"ALLOWED_MODELS" description:"Provider/model pairs messages may select in their llmOptions besides the configured, fallback and routing models"`
+	Cache                       LLMCacheConfig    `env:",prefix=CACHE_" description:"Caching of LLM responses"`
+	Cassette                    LLMCassetteConfig `env:",prefix=CASSETTE_" description:"Recording and replay of LLM interactions"`
+}
+
+// LLMRoutingConfig selects the model of a completion, models are given as provider/model pairs
+type LLMRoutingConfig struct {
+	ToolsModel          string `env:"TOOLS_MODEL" description:"Model answering completions offering tools"`
+	ShortPromptModel    string `env:"SHORT_PROMPT_MODEL" description:"Model answering completions with short prompts"`
+	ShortPromptMaxChars int    `env:"SHORT_PROMPT_MAX_CHARS,default=500" description:"Maximum characters of the messages of a short prompt"`
+}
+
+// LLMCacheConfig holds the configuration of the LLM response cache
+type LLMCacheConfig struct {
+	Enable     bool          `env:"ENABLE,default=false" description:"Answer repeated completions from the cache"`
+	Store      string        `env:"STORE,default=memory" description:"Store of the cached responses: memory or file"`
+	MaxEntries int           `env:"MAX_ENTRIES,default=1000" description:"Maximum responses kept by the memory store, the least recently used are evicted first"`
+	Dir        string        `env:"DIR" description:"Directory of the file store"`
+	TTL        time.Duration `env:"TTL,default=1h" description:"Time a cached response is answered from the cache, zero keeps responses forever"`
+}
+
+// LLMCassetteConfig records the LLM interactions of a live run to a cassette file or replays them
+type LLMCassetteConfig struct {
+	Mode string `env:"MODE" description:"Cassette mode: record or replay, empty disables the cassette"`
+	Path string `env:"PATH" description:"Cassette file of the recorded interactions"`
 }
 
 // ClientTLSConfig holds TLS configuration for LLM client
@@ -72,10 +105,29 @@ type TLSConfig struct {
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	Enable       bool   `env:"ENABLE,default=false"`
-	IssuerURL    string `env:"ISSUER_URL,default=http://keycloak:8080/realms/inference-gateway-realm"`
-	ClientID     string `env:"CLIENT_ID,default=inference-gateway-client"`
-	ClientSecret string `env:"CLIENT_SECRET"`
+	Enable           bool              `env:"ENABLE,default=false"`
+	Schemes          []string          `env:"SCHEMES,default=oidc" description:"Comma separated authentication schemes to accept (oidc, jwt, apikey, basic, mtls)"`
+	IssuerURL        string            `env:"ISSUER_URL,default=http://keycloak:8080/realms/inference-gateway-realm"`
+	ClientID         string            `env:"CLIENT_ID,default=inference-gateway-client"`
+	ClientSecret     string            `env:"CLIENT_SECRET"`
+	TenantClaim      string            `env:"TENANT_CLAIM" description:"Token claim holding the tenant tasks are owned by (empty to scope by subject)"`
+	RolesClaim       string            `env:"ROLES_CLAIM,default=realm_access.roles" description:"Token claim holding the caller roles, dotted paths are supported"`
+	AdminRole        string            `env:"ADMIN_ROLE,default=a2a-admin" description:"Role allowed to access tasks owned by any principal"`
+	ScopesClaim      string            `env:"SCOPES_CLAIM,default=scope" description:"Token claim holding the caller scopes, dotted paths are supported"`
+	MethodScopes     map[string]string `env:"METHOD_SCOPES" description:"Space separated scopes required per JSON-RPC method (e.g. message/send:a2a:write)"`
+	MethodRoles      map[string]string `env:"METHOD_ROLES" description:"Space separated roles of which one is required per JSON-RPC method"`
+	SkillScopes      map[string]string `env:"SKILL_SCOPES" description:"Space separated scopes required per agent skill ID"`
+	SkillRoles       map[string]string `env:"SKILL_ROLES" description:"Space separated roles of which one is required per agent skill ID"`
+	JWKSFile         string            `env:"JWKS_FILE" description:"Path to a local JWKS file used to verify JWTs"`
+	JWTPublicKeyFile string            `env:"JWT_PUBLIC_KEY_FILE" description:"Path to a PEM encoded public key or certificate used to verify JWTs"`
+	JWTIssuer        string            `env:"JWT_ISSUER" description:"Expected JWT issuer (empty to skip the check)"`
+	JWTAudience      string            `env:"JWT_AUDIENCE" description:"Expected JWT audience (empty to skip the check)"`
+	APIKeys          map[string]string `env:"API_KEYS" description:"Static API keys per subject (e.g. ci-bot:secret)"`
+	APIKeyName       string            `env:"API_KEY_NAME,default=X-API-Key" description:"Name of the header or query parameter carrying the API key"`
+	APIKeyIn         string            `env:"API_KEY_IN,default=header" description:"Location of the API key (header or query)"`
+	BasicUsers       map[string]string `env:"BASIC_USERS" description:"HTTP basic credentials per user, passwords may be bcrypt hashes"`
+	MTLSClientCAFile string            `env:"MTLS_CLIENT_CA_FILE" description:"Path to the CA bundle used to verify client certificates"`
+	SubjectRoles     map[string]string `env:"SUBJECT_ROLES" description:"Space separated roles per subject for API key, basic and mTLS principals"`
 }
 
 // QueueConfig holds task queue configuration
@@ -84,6 +136,38 @@ type QueueConfig struct {
 	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL,default=30s"`
 }
 
+// RateLimitConfig holds per-client rate limiting configuration
+type RateLimitConfig struct {
+	Enable            bool          `env:"ENABLE,default=false" description:"Enable per-client rate limiting of the A2A endpoint"`
+	KeyBy             string        `env:"KEY_BY,default=principal" description:"Client identity used as rate limit key (principal, apikey or ip), falls back to the client IP"`
+	RequestsPerMinute int           `env:"REQUESTS_PER_MINUTE,default=60" description:"Requests per client per minute"`
+	RequestBurst      int           `env:"REQUEST_BURST,default=10" description:"Maximum requests a client may send at once"`
+	TokensPerMinute   int           `env:"TOKENS_PER_MINUTE,default=0" description:"LLM tokens per client per minute (0 disables the token limit)"`
+	TokenBurst        int           `env:"TOKEN_BURST,default=0" description:"Maximum LLM tokens a client may consume at once (0 uses the tokens per minute)"`
+	IdleTimeout       time.Duration `env:"IDLE_TIMEOUT,default=10m" description:"Time after which the buckets of an idle client are discarded"`
+}
+
+// BudgetConfig holds the LLM token and cost budgets of tasks, contexts and principals
+type BudgetConfig struct {
+	Enable                        bool              `env:"ENABLE,default=false" description:"Enable LLM token and cost budgets"`
+	MaxTokensPerTask              int64             `env:"MAX_TOKENS_PER_TASK,default=0" description:"LLM tokens a task may use (0 disables the limit)"`
+	MaxTokensPerContextPerDay     int64             `env:"MAX_TOKENS_PER_CONTEXT_PER_DAY,default=0" description:"LLM tokens the tasks of a context may use per day (0 disables the limit)"`
+	MaxTokensPerPrincipalPerMonth int64             `env:"MAX_TOKENS_PER_PRINCIPAL_PER_MONTH,default=0" description:"LLM tokens the tasks of a principal may use per month (0 disables the limit)"`
+	MaxCostPerTask                float64           `env:"MAX_COST_PER_TASK,default=0" description:"LLM cost a task may incur (0 disables the limit)"`
+	MaxCostPerContextPerDay       float64           `env:"MAX_COST_PER_CONTEXT_PER_DAY,default=0" description:"LLM cost the tasks of a context may incur per day (0 disables the limit)"`
+	MaxCostPerPrincipalPerMonth   float64           `env:"MAX_COST_PER_PRINCIPAL_PER_MONTH,default=0" description:"LLM cost the tasks of a principal may incur per month (0 disables the limit)"`
+	ModelPrices                   map[string]string `env:"MODEL_PRICES" description:"Price per million prompt and completion tokens by model used to compute costs, e.g. gpt-4o:2.5/10,gpt-4o-mini:0.15/0.6"`
+}
+
+// CircuitBreakerConfig holds the circuit breaker and the concurrency limit of the requests to each LLM provider
+type CircuitBreakerConfig struct {
+	Enable                bool          `env:"ENABLE,default=false" description:"Enable the circuit breaker and concurrency limit of LLM requests"`
+	FailureThreshold      int           `env:"FAILURE_THRESHOLD,default=5" description:"Consecutive timeouts, connection errors, rate limits or server errors of a provider opening its circuit (0 disables the breaker)"`
+	OpenTimeout           time.Duration `env:"OPEN_TIMEOUT,default=30s" description:"Time an open circuit fails fast before letting probe requests through"`
+	HalfOpenMaxRequests   int           `env:"HALF_OPEN_MAX_REQUESTS,default=1" description:"Probe requests a half-open circuit lets through at once"`
+	MaxConcurrentRequests int           `env:"MAX_CONCURRENT_REQUESTS,default=0" description:"In-flight requests per provider (0 disables the limit)"`
+}
+
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port                  string        `env:"PORT,default=8080" description:"HTTP server port"`
@@ -91,6 +175,7 @@ type ServerConfig struct {
 	WriteTimeout          time.Duration `env:"WRITE_TIMEOUT,default=120s" description:"HTTP server write timeout"`
 	IdleTimeout           time.Duration `env:"IDLE_TIMEOUT,default=120s" description:"HTTP server idle timeout"`
 	DisableHealthcheckLog bool          `env:"DISABLE_HEALTHCHECK_LOG,default=true" description:"Disable logging for health check requests"`
+	DisableStrictMode     bool          `env:"DISABLE_STRICT_MODE,default=false" description:"Start even if the startup self-check reports errors such as auth misconfiguration"`
 	TLSConfig             TLSConfig     `env:",prefix=TLS_"`
 }
 
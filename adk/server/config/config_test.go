@@ -34,11 +34,17 @@ func TestConfig_LoadWithLookuper(t *testing.T) {
 				assert.Equal(t, "", cfg.AgentConfig.Model)
 				assert.Equal(t, 30*time.Second, cfg.AgentConfig.Timeout)
 				assert.Equal(t, 3, cfg.AgentConfig.MaxRetries)
+				assert.Equal(t, 1*time.Second, cfg.AgentConfig.RetryInitialBackoff)
+				assert.Equal(t, 30*time.Second, cfg.AgentConfig.RetryMaxBackoff)
 				assert.Equal(t, 10, cfg.AgentConfig.MaxChatCompletionIterations)
 				assert.Equal(t, "a2a-agent/1.0", cfg.AgentConfig.UserAgent)
 				assert.Equal(t, 4096, cfg.AgentConfig.MaxTokens)
 				assert.Equal(t, 0.7, cfg.AgentConfig.Temperature)
 				assert.Equal(t, 1.0, cfg.AgentConfig.TopP)
+				assert.False(t, cfg.AgentConfig.Cache.Enable)
+				assert.Equal(t, "memory", cfg.AgentConfig.Cache.Store)
+				assert.Equal(t, 1000, cfg.AgentConfig.Cache.MaxEntries)
+				assert.Equal(t, time.Hour, cfg.AgentConfig.Cache.TTL)
 
 				require.NotNil(t, cfg.CapabilitiesConfig)
 				assert.True(t, cfg.CapabilitiesConfig.Streaming)
@@ -49,6 +55,9 @@ func TestConfig_LoadWithLookuper(t *testing.T) {
 				assert.False(t, cfg.AuthConfig.Enable)
 				assert.Equal(t, "http://keycloak:8080/realms/inference-gateway-realm", cfg.AuthConfig.IssuerURL)
 				assert.Equal(t, "inference-gateway-client", cfg.AuthConfig.ClientID)
+				assert.Equal(t, []string{"oidc"}, cfg.AuthConfig.Schemes)
+				assert.Equal(t, "X-API-Key", cfg.AuthConfig.APIKeyName)
+				assert.Equal(t, "header", cfg.AuthConfig.APIKeyIn)
 
 				require.NotNil(t, cfg.QueueConfig)
 				assert.Equal(t, 100, cfg.QueueConfig.MaxSize)
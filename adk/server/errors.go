@@ -1,5 +1,10 @@
 package server
 
+import (
+	"fmt"
+	"time"
+)
+
 // Additional error types for the new interface-based design
 
 // EmptyMessagePartsError represents an error for empty message parts
@@ -25,3 +30,156 @@ func (e *StreamingNotImplementedError) Error() string {
 func NewStreamingNotImplementedError() error {
 	return &StreamingNotImplementedError{}
 }
+
+// InputRequiredError is returned by a tool that cannot continue without more input from the user
+// The task is moved to the input-required state with the message as its status message
+type InputRequiredError struct {
+	Message string
+}
+
+func (e *InputRequiredError) Error() string {
+	return "input required: " + e.Message
+}
+
+// NewInputRequiredError creates a new InputRequiredError
+func NewInputRequiredError(message string) error {
+	return &InputRequiredError{Message: message}
+}
+
+// ToolTimeoutError is returned when a tool does not finish within its timeout
+type ToolTimeoutError struct {
+	ToolName string
+	Timeout  time.Duration
+}
+
+func (e *ToolTimeoutError) Error() string {
+	return fmt.Sprintf("tool %s timed out after %s", e.ToolName, e.Timeout)
+}
+
+// NewToolTimeoutError creates a new ToolTimeoutError
+func NewToolTimeoutError(toolName string, timeout time.Duration) error {
+	return &ToolTimeoutError{ToolName: toolName, Timeout: timeout}
+}
+
+// ToolPanicError is returned when a tool panics, the panic is recovered so it does not crash the worker
+type ToolPanicError struct {
+	ToolName string
+	Value    interface{}
+}
+
+func (e *ToolPanicError) Error() string {
+	return fmt.Sprintf("tool %s panicked: %v", e.ToolName, e.Value)
+}
+
+// NewToolPanicError creates a new ToolPanicError
+func NewToolPanicError(toolName string, value interface{}) error {
+	return &ToolPanicError{ToolName: toolName, Value: value}
+}
+
+// InvalidLLMOptionsError is returned when the LLM options of a message cannot be applied to the request
+type InvalidLLMOptionsError struct {
+	Option string
+	Reason string
+}
+
+func (e *InvalidLLMOptionsError) Error() string {
+	return fmt.Sprintf("invalid llm option %s: %s", e.Option, e.Reason)
+}
+
+// NewInvalidLLMOptionsError creates a new InvalidLLMOptionsError
+func NewInvalidLLMOptionsError(option string, reason string) error {
+	return &InvalidLLMOptionsError{Option: option, Reason: reason}
+}
+
+// BudgetExceededError is returned when a task, its context or its principal has used up an LLM budget
+type BudgetExceededError struct {
+	Scope    string
+	Resource string
+	Used     float64
+	Limit    float64
+	ResetsAt *time.Time
+}
+
+func (e *BudgetExceededError) Error() string {
+	format := "%.0f of %.0f"
+	if e.Resource == BudgetResourceCost {
+		format = "%.2f of %.2f"
+	}
+
+	message := fmt.Sprintf("%s %s budget exhausted: "+format+" used", e.Scope, e.Resource, e.Used, e.Limit)
+	if e.ResetsAt != nil {
+		message += ", resets at " + e.ResetsAt.Format(time.RFC3339)
+	}
+	return message
+}
+
+// NewBudgetExceededError creates a new BudgetExceededError
+func NewBudgetExceededError(scope string, resource string, used float64, limit float64, resetsAt *time.Time) error {
+	return &BudgetExceededError{Scope: scope, Resource: resource, Used: used, Limit: limit, ResetsAt: resetsAt}
+}
+
+// UnpricedModelError is returned when a cost budget is set and a completion is requested with a model without a price
+type UnpricedModelError struct {
+	Model string
+}
+
+func (e *UnpricedModelError) Error() string {
+	return fmt.Sprintf("model %q has no price, its cost cannot be charged to the cost budgets", e.Model)
+}
+
+// NewUnpricedModelError creates a new UnpricedModelError
+func NewUnpricedModelError(model string) error {
+	return &UnpricedModelError{Model: model}
+}
+
+// LLMAPIError is returned when the LLM gateway rejects a chat completion request
+type LLMAPIError struct {
+	StatusCode int
+	Message    string
+	Body       string
+
+	// RetryAfter is the wait the gateway asked for before retrying, if any
+	RetryAfter time.Duration
+}
+
+func (e *LLMAPIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("API error: %s (status code: %d)", e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("request failed with status code: %d, response body: %s", e.StatusCode, e.Body)
+}
+
+// NewLLMAPIError creates a new LLMAPIError
+func NewLLMAPIError(statusCode int, message string, body string) error {
+	return &LLMAPIError{StatusCode: statusCode, Message: message, Body: body}
+}
+
+// CircuitOpenError is returned without calling the LLM provider while its circuit breaker is open
+type CircuitOpenError struct {
+	Provider string
+	RetryAt  time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker of llm provider %s is open until %s", e.Provider, e.RetryAt.Format(time.RFC3339))
+}
+
+// NewCircuitOpenError creates a new CircuitOpenError
+func NewCircuitOpenError(provider string, retryAt time.Time) error {
+	return &CircuitOpenError{Provider: provider, RetryAt: retryAt}
+}
+
+// UnmatchedLLMRequestError is returned by a replaying cassette when no recorded interaction matches a request
+type UnmatchedLLMRequestError struct {
+	Cassette string
+	Request  string
+}
+
+func (e *UnmatchedLLMRequestError) Error() string {
+	return fmt.Sprintf("no recorded llm interaction of cassette %s matches request %s", e.Cassette, e.Request)
+}
+
+// NewUnmatchedLLMRequestError creates a new UnmatchedLLMRequestError
+func NewUnmatchedLLMRequestError(cassette string, request string) error {
+	return &UnmatchedLLMRequestError{Cassette: cassette, Request: request}
+}
@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/inference-gateway/a2a/adk/server"
 	"github.com/stretchr/testify/assert"
@@ -41,6 +42,59 @@ func TestErrorTypes(t *testing.T) {
 			createError: server.NewStreamingNotImplementedError,
 			expectedMsg: "streaming not implemented",
 		},
+		{
+			name:        "InputRequiredError",
+			createError: func() error { return server.NewInputRequiredError("which city?") },
+			expectedMsg: "input required: which city?",
+		},
+		{
+			name:        "ToolTimeoutError",
+			createError: func() error { return server.NewToolTimeoutError("search", 5*time.Second) },
+			expectedMsg: "tool search timed out after 5s",
+		},
+		{
+			name:        "ToolPanicError",
+			createError: func() error { return server.NewToolPanicError("search", "nil map") },
+			expectedMsg: "tool search panicked: nil map",
+		},
+		{
+			name:        "InvalidLLMOptionsError",
+			createError: func() error { return server.NewInvalidLLMOptionsError("temperature", "must be between 0 and 2") },
+			expectedMsg: "invalid llm option temperature: must be between 0 and 2",
+		},
+		{
+			name: "BudgetExceededError",
+			createError: func() error {
+				return server.NewBudgetExceededError(server.BudgetScopeContext, server.BudgetResourceCost, 12.5, 10, nil)
+			},
+			expectedMsg: "context cost budget exhausted: 12.50 of 10.00 used",
+		},
+		{
+			name:        "UnpricedModelError",
+			createError: func() error { return server.NewUnpricedModelError("llama-3") },
+			expectedMsg: `model "llama-3" has no price, its cost cannot be charged to the cost budgets`,
+		},
+		{
+			name: "LLMAPIError",
+			createError: func() error {
+				return server.NewLLMAPIError(503, "upstream unavailable", "")
+			},
+			expectedMsg: "API error: upstream unavailable (status code: 503)",
+		},
+		{
+			name: "CircuitOpenError",
+			createError: func() error {
+				return server.NewCircuitOpenError("openai", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+			},
+			expectedMsg: "circuit breaker of llm provider openai is open until 2025-01-02T03:04:05Z",
+		},
+		{
+			name: "UnmatchedLLMRequestError",
+			createError: func() error {
+				return server.NewUnmatchedLLMRequestError("testdata/weather.json", `user: "Will it rain?"`)
+			},
+			expectedMsg: `no recorded llm interaction of cassette testdata/weather.json matches request user: "Will it rain?"`,
+		},
 	}
 
 	for _, tt := range tests {
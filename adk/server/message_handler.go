@@ -2,15 +2,14 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	uuid "github.com/google/uuid"
 	adk "github.com/inference-gateway/a2a/adk"
 	config "github.com/inference-gateway/a2a/adk/server/config"
-	utils "github.com/inference-gateway/a2a/adk/server/utils"
-	sdk "github.com/inference-gateway/sdk"
+	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
 	zap "go.uber.org/zap"
 )
 
@@ -25,14 +24,10 @@ type MessageHandler interface {
 
 // DefaultMessageHandler implements the MessageHandler interface
 type DefaultMessageHandler struct {
-	logger        *zap.Logger
-	taskManager   TaskManager
-	agent         OpenAICompatibleAgent
-	config        *config.Config
-	llmClient     LLMClient
-	converter     *utils.OptimizedMessageConverter
-	toolBox       ToolBox
-	maxIterations int
+	logger      *zap.Logger
+	taskManager TaskManager
+	config      *config.Config
+	taskHandler TaskHandler
 }
 
 // NewDefaultMessageHandler creates a new default message handler
@@ -42,41 +37,33 @@ func NewDefaultMessageHandler(logger *zap.Logger, taskManager TaskManager, cfg *
 	}
 
 	return &DefaultMessageHandler{
-		logger:        logger,
-		taskManager:   taskManager,
-		agent:         nil,
-		config:        cfg,
-		llmClient:     nil,
-		converter:     utils.NewOptimizedMessageConverter(logger),
-		toolBox:       nil,
-		maxIterations: cfg.AgentConfig.MaxChatCompletionIterations,
+		logger:      logger,
+		taskManager: taskManager,
+		config:      cfg,
 	}
 }
 
-// NewDefaultMessageHandlerWithAgent creates a new default message handler with an agent for streaming
+// NewDefaultMessageHandlerWithAgent creates a new default message handler streaming with an agent
 func NewDefaultMessageHandlerWithAgent(logger *zap.Logger, taskManager TaskManager, agent OpenAICompatibleAgent, cfg *config.Config) *DefaultMessageHandler {
-	if cfg == nil {
-		logger.Fatal("config is required but was nil")
-	}
-
-	var llmClient LLMClient
-	var toolBox ToolBox
-
+	handler := NewDefaultMessageHandler(logger, taskManager, cfg)
 	if agent != nil {
-		llmClient = agent.GetLLMClient()
-		toolBox = agent.GetToolBox()
+		handler.taskHandler = NewAgentTaskHandlerWithConfig(logger, agent, &cfg.AgentConfig)
 	}
+	return handler
+}
 
-	return &DefaultMessageHandler{
-		logger:        logger,
-		taskManager:   taskManager,
-		agent:         agent,
-		config:        cfg,
-		llmClient:     llmClient,
-		converter:     utils.NewOptimizedMessageConverter(logger),
-		toolBox:       toolBox,
-		maxIterations: cfg.AgentConfig.MaxChatCompletionIterations,
+// SetTaskHandler sets the task handler processing the tasks of message/stream
+// Handlers not implementing StreamingTaskHandler stream the status updates they report and their final state
+func (mh *DefaultMessageHandler) SetTaskHandler(handler TaskHandler) {
+	mh.taskHandler = handler
+}
+
+// agentConfig returns the agent configuration the LLM options of messages are validated with
+func (mh *DefaultMessageHandler) agentConfig() *config.AgentConfig {
+	if mh.config == nil {
+		return nil
 	}
+	return &mh.config.AgentConfig
 }
 
 // HandleMessageSend processes message/send requests
@@ -85,13 +72,26 @@ func (mh *DefaultMessageHandler) HandleMessageSend(ctx context.Context, params a
 		return nil, NewEmptyMessagePartsError()
 	}
 
+	if _, err := ParseLLMRequestOptions(params.Message.Metadata, mh.agentConfig()); err != nil {
+		return nil, err
+	}
+
 	contextID := params.Message.ContextID
 	if contextID == nil {
 		newContextID := uuid.New().String()
 		contextID = &newContextID
 	}
 
-	task := mh.taskManager.CreateTask(*contextID, adk.TaskStateSubmitted, &params.Message)
+	task, err := mh.resumeTask(ctx, &params.Message, adk.TaskStateSubmitted)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		task, err = mh.createTask(ctx, *contextID, adk.TaskStateSubmitted, &params.Message)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	if task != nil {
 		mh.logger.Info("message send handled",
@@ -110,13 +110,26 @@ func (mh *DefaultMessageHandler) HandleMessageStream(ctx context.Context, params
 		return NewEmptyMessagePartsError()
 	}
 
+	if _, err := ParseLLMRequestOptions(params.Message.Metadata, mh.agentConfig()); err != nil {
+		return err
+	}
+
 	contextID := params.Message.ContextID
 	if contextID == nil {
 		newContextID := uuid.New().String()
 		contextID = &newContextID
 	}
 
-	task := mh.taskManager.CreateTask(*contextID, adk.TaskStateWorking, &params.Message)
+	task, err := mh.resumeTask(ctx, &params.Message, adk.TaskStateWorking)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		task, err = mh.createTask(ctx, *contextID, adk.TaskStateWorking, &params.Message)
+		if err != nil {
+			return err
+		}
+	}
 	if task == nil {
 		mh.logger.Error("failed to create streaming task - task manager returned nil")
 		return fmt.Errorf("failed to create streaming task")
@@ -126,40 +139,32 @@ func (mh *DefaultMessageHandler) HandleMessageStream(ctx context.Context, params
 		zap.String("task_id", task.ID),
 		zap.String("context_id", task.ContextID))
 
-	select {
-	case responseChan <- adk.TaskStatusUpdateEvent{
-		Kind:      "status-update",
-		TaskID:    task.ID,
-		ContextID: task.ContextID,
-		Status:    task.Status,
-		Final:     false,
-	}:
-	case <-ctx.Done():
-		return ctx.Err()
+	emitter := newChannelStreamEventEmitter(task, responseChan, mh.getCurrentTimestamp)
+	if err := emitter.EmitStatus(ctx, task.Status, false); err != nil {
+		return err
 	}
 
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
 		defer func() {
-			if err := mh.taskManager.UpdateTask(task.ID, adk.TaskStateCompleted, nil); err != nil {
+			finalState := adk.TaskStateCompleted
+			if IsFinalTaskState(task.Status.State) {
+				finalState = task.Status.State
+			}
+			if err := mh.taskManager.UpdateTask(task.ID, finalState, nil); err != nil {
 				mh.logger.Error("failed to update streaming task", zap.Error(err))
 			}
 		}()
 
-		if mh.llmClient == nil {
-			mh.logger.Error("no LLM client available for streaming")
-			mh.handleMockStreaming(ctx, task, responseChan)
-			return
-		}
-
-		if mh.agent == nil {
-			mh.logger.Error("no agent available for streaming")
-			mh.handleMockStreaming(ctx, task, responseChan)
+		handler := asStreamingTaskHandler(mh.taskHandler)
+		if handler == nil {
+			mh.logger.Error("no task handler available for streaming")
+			mh.handleMockStreaming(ctx, task, emitter)
 			return
 		}
 
-		mh.handleIterativeStreaming(ctx, task, &params.Message, responseChan)
+		mh.streamTask(ctx, handler, task, &params.Message, emitter)
 	}()
 
 	select {
@@ -170,347 +175,96 @@ func (mh *DefaultMessageHandler) HandleMessageStream(ctx context.Context, params
 	}
 }
 
-// handleIterativeStreaming handles the iterative streaming process with tool calling support
-func (mh *DefaultMessageHandler) handleIterativeStreaming(
-	ctx context.Context,
-	task *adk.Task,
-	message *adk.Message,
-	responseChan chan<- adk.SendStreamingMessageResponse,
-) {
-	messages := make([]adk.Message, 0)
-
-	systemMessage := adk.Message{
-		Kind:      "message",
-		MessageID: "system-prompt",
-		Role:      "system",
-		Parts: []adk.Part{
-			map[string]interface{}{
-				"kind": "text",
-				"text": mh.agent.GetSystemPrompt(),
-			},
-		},
+// streamTask processes the task with the streaming handler and ends the stream with the final state of the task
+// unless the handler did, artifacts the handler added without emitting them are sent first
+func (mh *DefaultMessageHandler) streamTask(ctx context.Context, handler StreamingTaskHandler, task *adk.Task, message *adk.Message, emitter *channelStreamEventEmitter) {
+	artifactsLength := len(task.Artifacts)
+	result, err := handler.HandleStreamingTask(ctx, task, message, emitter)
+	if err != nil {
+		mh.logger.Error("streaming task processing failed", zap.Error(err), zap.String("task_id", task.ID))
+		result = failedTask(task, fmt.Sprintf("Task processing failed: %v", err))
 	}
-	messages = append(messages, systemMessage)
-
-	if message != nil {
-		messages = append(messages, *message)
+	if result != nil && result != task {
+		*task = *result
 	}
 
-	messages = append(messages, task.History...)
+	mh.taskManager.UpdateConversationHistory(task.ContextID, task.History)
 
-	var tools []sdk.ChatCompletionTool
-	if mh.toolBox != nil {
-		tools = mh.toolBox.GetTools()
-	}
-
-	for iteration := 1; iteration <= mh.maxIterations; iteration++ {
-		mh.logger.Debug("starting streaming iteration",
-			zap.Int("iteration", iteration),
-			zap.String("task_id", task.ID))
-
-		sdkMessages, err := mh.converter.ConvertToSDK(messages)
-		if err != nil {
-			mh.logger.Error("failed to convert messages", zap.Error(err))
-			mh.sendErrorResponse(ctx, task, fmt.Sprintf("Message conversion failed: %v", err), responseChan)
-			return
-		}
-
-		streamResponseChan, streamErrorChan := mh.llmClient.CreateStreamingChatCompletion(ctx, sdkMessages, tools...)
-		toolCallsExecuted, assistantMessage, toolResultMessages := mh.processStream(ctx, task, iteration, streamResponseChan, streamErrorChan, responseChan, &messages)
-
-		if assistantMessage != nil {
-			messages = append(messages, *assistantMessage)
-			task.History = append(task.History, *assistantMessage)
+	if final := emitter.finalStatus(); final != nil {
+		if !IsFinalTaskState(task.Status.State) {
+			task.Status.State = final.State
 		}
+		return
+	}
 
-		for _, toolResultMsg := range toolResultMessages {
-			messages = append(messages, toolResultMsg)
-			task.History = append(task.History, toolResultMsg)
+	for i := artifactsLength; i < len(task.Artifacts); i++ {
+		if emitter.emitted(task.Artifacts[i].ArtifactID) {
+			continue
 		}
-
-		if !toolCallsExecuted {
-			finalMessage := assistantMessage
-			if finalMessage != nil {
-				task.Status.Message = finalMessage
-				mh.taskManager.UpdateConversationHistory(task.ContextID, task.History)
-			}
-
-			mh.logger.Info("streaming task completed successfully",
-				zap.String("task_id", task.ID),
-				zap.Int("iterations", iteration))
-
-			select {
-			case responseChan <- adk.TaskStatusUpdateEvent{
-				Kind:      "status-update",
-				TaskID:    task.ID,
-				ContextID: task.ContextID,
-				Status: adk.TaskStatus{
-					State:     adk.TaskStateCompleted,
-					Message:   finalMessage,
-					Timestamp: StringPtr(mh.getCurrentTimestamp()),
-				},
-				Final: true,
-			}:
-			case <-ctx.Done():
-			}
+		if err := emitter.EmitArtifact(ctx, task.Artifacts[i], true); err != nil {
 			return
 		}
+	}
 
-		mh.logger.Debug("tool calls executed, continuing to next iteration",
-			zap.Int("iteration", iteration),
-			zap.String("task_id", task.ID))
-
-		mh.taskManager.UpdateConversationHistory(task.ContextID, task.History)
+	if !IsFinalTaskState(task.Status.State) {
+		task.Status.State = adk.TaskStateCompleted
 	}
 
-	mh.logger.Warn("max streaming iterations reached",
+	mh.logger.Info("streaming task finished",
 		zap.String("task_id", task.ID),
-		zap.Int("max_iterations", mh.maxIterations))
-	mh.sendErrorResponse(ctx, task, fmt.Sprintf("Maximum iterations (%d) reached without completion", mh.maxIterations), responseChan)
-}
-
-// processStream handles the streaming response and tool execution
-func (mh *DefaultMessageHandler) processStream(
-	ctx context.Context,
-	task *adk.Task,
-	iteration int,
-	streamResponseChan <-chan *sdk.CreateChatCompletionStreamResponse,
-	streamErrorChan <-chan error,
-	responseChan chan<- adk.SendStreamingMessageResponse,
-	messages *[]adk.Message,
-) (toolCallsExecuted bool, assistantMessage *adk.Message, toolResultMessages []adk.Message) {
-	var fullContent string
-	toolCallAccumulator := make(map[int]*sdk.ChatCompletionMessageToolCall)
-	toolResultMessages = make([]adk.Message, 0)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return false, nil, nil
-		case streamErr := <-streamErrorChan:
-			if streamErr != nil {
-				mh.logger.Error("streaming failed", zap.Error(streamErr))
-				mh.sendErrorResponse(ctx, task, fmt.Sprintf("Streaming failed: %v", streamErr), responseChan)
-				return false, nil, nil
-			}
-		case streamResp, ok := <-streamResponseChan:
-			if !ok {
-				break
-			}
-
-			if streamResp == nil || len(streamResp.Choices) == 0 {
-				continue
-			}
-
-			choice := streamResp.Choices[0]
-
-			if choice.Delta.Content != "" {
-				fullContent += choice.Delta.Content
-				mh.sendContentChunk(ctx, task, choice.Delta.Content, responseChan)
-			}
-
-			for _, toolCallChunk := range choice.Delta.ToolCalls {
-				if toolCallAccumulator[toolCallChunk.Index] == nil {
-					toolCallAccumulator[toolCallChunk.Index] = &sdk.ChatCompletionMessageToolCall{
-						Type:     "function",
-						Function: sdk.ChatCompletionMessageToolCallFunction{},
-					}
-				}
-
-				toolCall := toolCallAccumulator[toolCallChunk.Index]
-				if toolCallChunk.ID != "" {
-					toolCall.Id = toolCallChunk.ID
-				}
-				if toolCallChunk.Function.Name != "" {
-					toolCall.Function.Name = toolCallChunk.Function.Name
-				}
-				if toolCallChunk.Function.Arguments != "" {
-					toolCall.Function.Arguments += toolCallChunk.Function.Arguments
-				}
-			}
+		zap.String("state", string(task.Status.State)))
 
-			if choice.FinishReason == "" {
-				continue
-			}
-
-			assistantMessage = &adk.Message{
-				Kind:      "message",
-				MessageID: fmt.Sprintf("assistant-%s-%d", task.ID, iteration),
-				Role:      "assistant",
-				Parts:     make([]adk.Part, 0),
-				TaskID:    &task.ID,
-				ContextID: &task.ContextID,
-			}
-
-			if fullContent != "" {
-				assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
-					"kind": "text",
-					"text": fullContent,
-				})
-			}
+	_ = emitter.EmitStatus(ctx, adk.TaskStatus{
+		State:   task.Status.State,
+		Message: task.Status.Message,
+	}, true)
+}
 
-			if len(toolCallAccumulator) == 0 {
-				return false, assistantMessage, toolResultMessages
-			}
+// createTask creates a task owned by the authenticated principal of the request, if any
+func (mh *DefaultMessageHandler) createTask(ctx context.Context, contextID string, state adk.TaskState, message *adk.Message) (*adk.Task, error) {
+	principal, ok := middlewares.PrincipalFromContext(ctx)
+	if !ok {
+		return mh.taskManager.CreateTask(contextID, state, message), nil
+	}
 
-			toolCalls := make([]sdk.ChatCompletionMessageToolCall, 0, len(toolCallAccumulator))
-			for _, toolCall := range toolCallAccumulator {
-				toolCalls = append(toolCalls, *toolCall)
-			}
-			assistantMessage.Parts = append(assistantMessage.Parts, map[string]interface{}{
-				"kind": "data",
-				"data": map[string]interface{}{
-					"tool_calls": toolCalls,
-				},
-			})
-
-			for _, toolCall := range toolCalls {
-				if toolCall.Function.Name == "" || toolCall.Id == "" {
-					continue
-				}
-
-				mh.sendToolExecutionEvent(ctx, task, toolCall.Function.Name, "started", responseChan)
-
-				if mh.toolBox == nil {
-					mh.logger.Debug("no toolbox available for tool execution", zap.String("function", toolCall.Function.Name))
-					continue
-				}
-
-				var argsMap map[string]interface{}
-				if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &argsMap); err != nil {
-					mh.logger.Error("failed to parse tool arguments", zap.Error(err), zap.String("function", toolCall.Function.Name))
-					mh.sendToolExecutionEvent(ctx, task, toolCall.Function.Name, "failed", responseChan)
-					continue
-				}
-
-				toolResult, err := mh.toolBox.ExecuteTool(ctx, toolCall.Function.Name, argsMap)
-				if err != nil {
-					mh.logger.Error("tool execution failed", zap.Error(err), zap.String("function", toolCall.Function.Name))
-					mh.sendToolExecutionEvent(ctx, task, toolCall.Function.Name, "failed", responseChan)
-					continue
-				}
-
-				mh.logger.Info("tool executed successfully", zap.String("function", toolCall.Function.Name))
-
-				mh.sendToolExecutionEvent(ctx, task, toolCall.Function.Name, "completed", responseChan)
-
-				toolResultMessage := adk.Message{
-					Kind:      "message",
-					MessageID: fmt.Sprintf("tool-result-%s", toolCall.Id),
-					Role:      "tool",
-					Parts: []adk.Part{
-						map[string]interface{}{
-							"kind": "data",
-							"data": map[string]interface{}{
-								"tool_call_id": toolCall.Id,
-								"result":       toolResult,
-							},
-						},
-					},
-					TaskID:    &task.ID,
-					ContextID: &task.ContextID,
-				}
-
-				select {
-				case responseChan <- adk.TaskStatusUpdateEvent{
-					Kind:      "status-update",
-					TaskID:    task.ID,
-					ContextID: task.ContextID,
-					Status: adk.TaskStatus{
-						State:     adk.TaskStateWorking,
-						Message:   &toolResultMessage,
-						Timestamp: StringPtr(mh.getCurrentTimestamp()),
-					},
-					Final: false,
-				}:
-				case <-ctx.Done():
-				}
+	return mh.taskManager.CreateTaskForPrincipal(principal, contextID, state, message)
+}
 
-				toolResultMessages = append(toolResultMessages, toolResultMessage)
-			}
+// resumeTask continues the input-required task the message replies to, recording the reply in the task history.
+// It returns nil if the message does not reply to an existing task, and an error if the task is not waiting for input.
+func (mh *DefaultMessageHandler) resumeTask(ctx context.Context, message *adk.Message, state adk.TaskState) (*adk.Task, error) {
+	if message.TaskID == nil || *message.TaskID == "" {
+		return nil, nil
+	}
 
-			return true, assistantMessage, toolResultMessages
+	var notFound *TaskNotFoundError
+	if principal, ok := middlewares.PrincipalFromContext(ctx); ok {
+		err := mh.taskManager.AuthorizeTask(*message.TaskID, principal)
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
-}
 
-// sendContentChunk sends a content chunk through the response channel
-func (mh *DefaultMessageHandler) sendContentChunk(
-	ctx context.Context,
-	task *adk.Task,
-	content string,
-	responseChan chan<- adk.SendStreamingMessageResponse,
-) {
-	select {
-	case responseChan <- adk.TaskStatusUpdateEvent{
-		Kind:      "status-update",
-		TaskID:    task.ID,
-		ContextID: task.ContextID,
-		Status: adk.TaskStatus{
-			State: adk.TaskStateWorking,
-			Message: &adk.Message{
-				Kind:      "message",
-				MessageID: fmt.Sprintf("stream-chunk-%s", task.ID),
-				Role:      "assistant",
-				Parts: []adk.Part{
-					map[string]interface{}{
-						"kind": "text",
-						"text": content,
-					},
-				},
-				TaskID:    &task.ID,
-				ContextID: &task.ContextID,
-			},
-			Timestamp: StringPtr(mh.getCurrentTimestamp()),
-		},
-		Final: false,
-	}:
-	case <-ctx.Done():
+	task, err := mh.taskManager.ResumeTask(*message.TaskID, state, message)
+	if errors.As(err, &notFound) {
+		return nil, nil
 	}
-}
-
-// sendToolExecutionEvent sends tool execution status events
-func (mh *DefaultMessageHandler) sendToolExecutionEvent(
-	ctx context.Context,
-	task *adk.Task,
-	toolName string,
-	status string,
-	responseChan chan<- adk.SendStreamingMessageResponse,
-) {
-	select {
-	case responseChan <- adk.TaskStatusUpdateEvent{
-		Kind:      "status-update",
-		TaskID:    task.ID,
-		ContextID: task.ContextID,
-		Status: adk.TaskStatus{
-			State: adk.TaskStateWorking,
-			Message: &adk.Message{
-				Kind:      "message",
-				MessageID: fmt.Sprintf("tool-status-%s-%s", task.ID, status),
-				Role:      "assistant",
-				Parts: []adk.Part{
-					map[string]interface{}{
-						"kind": "data",
-						"data": map[string]interface{}{
-							"tool_name": toolName,
-							"status":    status,
-						},
-					},
-				},
-				TaskID:    &task.ID,
-				ContextID: &task.ContextID,
-			},
-			Timestamp: StringPtr(mh.getCurrentTimestamp()),
-		},
-		Final: false,
-	}:
-	case <-ctx.Done():
+	if err != nil {
+		return nil, err
 	}
+
+	mh.logger.Info("input-required task resumed",
+		zap.String("task_id", task.ID),
+		zap.String("context_id", task.ContextID))
+	return task, nil
 }
 
-// handleMockStreaming provides fallback mock streaming for backward compatibility
-func (mh *DefaultMessageHandler) handleMockStreaming(ctx context.Context, task *adk.Task, responseChan chan<- adk.SendStreamingMessageResponse) {
-	mh.logger.Debug("using mock streaming - no LLM agent configured",
+// handleMockStreaming provides fallback mock streaming when no task handler is configured
+func (mh *DefaultMessageHandler) handleMockStreaming(ctx context.Context, task *adk.Task, emitter StreamEventEmitter) {
+	mh.logger.Debug("using mock streaming - no task handler configured",
 		zap.String("task_id", task.ID),
 		zap.String("context_id", task.ContextID))
 
@@ -524,95 +278,34 @@ func (mh *DefaultMessageHandler) handleMockStreaming(ctx context.Context, task *
 	}
 
 	for i, chunk := range chunks {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			select {
-			case responseChan <- adk.TaskStatusUpdateEvent{
-				Kind:      "status-update",
-				TaskID:    task.ID,
-				ContextID: task.ContextID,
-				Status: adk.TaskStatus{
-					State: adk.TaskStateWorking,
-					Message: &adk.Message{
-						Kind:      "message",
-						MessageID: fmt.Sprintf("mock-progress-%s-%d", task.ID, i+1),
-						Role:      "assistant",
-						Parts: []adk.Part{
-							map[string]interface{}{
-								"kind": "text",
-								"text": chunk,
-							},
-						},
-						TaskID:    &task.ID,
-						ContextID: &task.ContextID,
-					},
-					Timestamp: StringPtr(mh.getCurrentTimestamp()),
-				},
-				Final: false,
-			}:
-				mh.logger.Debug("mock streaming chunk sent",
-					zap.String("task_id", task.ID),
-					zap.Int("chunk_id", i+1),
-					zap.String("content", chunk))
-				time.Sleep(100 * time.Millisecond)
-			case <-ctx.Done():
-				return
-			}
-		}
-	}
-
-	select {
-	case <-ctx.Done():
-		return
-	default:
-		select {
-		case responseChan <- adk.TaskStatusUpdateEvent{
-			Kind:      "status-update",
-			TaskID:    task.ID,
-			ContextID: task.ContextID,
-			Status: adk.TaskStatus{
-				State:     adk.TaskStateCompleted,
-				Timestamp: StringPtr(mh.getCurrentTimestamp()),
-			},
-			Final: true,
-		}:
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
-// sendErrorResponse sends an error response through the stream
-func (mh *DefaultMessageHandler) sendErrorResponse(ctx context.Context, task *adk.Task, errorMsg string, responseChan chan<- adk.SendStreamingMessageResponse) {
-	select {
-	case responseChan <- adk.TaskStatusUpdateEvent{
-		Kind:      "status-update",
-		TaskID:    task.ID,
-		ContextID: task.ContextID,
-		Status: adk.TaskStatus{
-			State: adk.TaskStateFailed,
+		err := emitter.EmitStatus(ctx, adk.TaskStatus{
+			State: adk.TaskStateWorking,
 			Message: &adk.Message{
 				Kind:      "message",
-				MessageID: fmt.Sprintf("error-%s", task.ID),
+				MessageID: fmt.Sprintf("mock-progress-%s-%d", task.ID, i+1),
 				Role:      "assistant",
 				Parts: []adk.Part{
 					map[string]interface{}{
 						"kind": "text",
-						"text": errorMsg,
+						"text": chunk,
 					},
 				},
 				TaskID:    &task.ID,
 				ContextID: &task.ContextID,
 			},
-			Timestamp: StringPtr(mh.getCurrentTimestamp()),
-		},
-		Final: true,
-	}:
-	case <-ctx.Done():
-		return
+		}, false)
+		if err != nil {
+			return
+		}
+
+		mh.logger.Debug("mock streaming chunk sent",
+			zap.String("task_id", task.ID),
+			zap.Int("chunk_id", i+1),
+			zap.String("content", chunk))
+		time.Sleep(100 * time.Millisecond)
 	}
+
+	_ = emitter.EmitStatus(ctx, adk.TaskStatus{State: adk.TaskStateCompleted}, true)
 }
 
 // getCurrentTimestamp returns the current timestamp in the configured timezone
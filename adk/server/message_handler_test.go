@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -102,6 +103,42 @@ func TestDefaultMessageHandler_HandleMessageSend(t *testing.T) {
 	}
 }
 
+func TestDefaultMessageHandler_HandleMessageSend_ReplyToTask(t *testing.T) {
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	messageHandler := server.NewDefaultMessageHandler(zap.NewNop(), taskManager, &config.Config{})
+
+	waiting := taskManager.CreateTask("context-1", adk.TaskStateInputRequired, nil)
+	completed := taskManager.CreateTask("context-2", adk.TaskStateCompleted, nil)
+
+	tests := []struct {
+		name        string
+		taskID      string
+		expectSame  bool
+		expectedErr interface{}
+	}{
+		{name: "reply resumes the input-required task", taskID: waiting.ID, expectSame: true},
+		{name: "reply to a task not waiting for input is rejected", taskID: completed.ID, expectedErr: &server.TaskNotResumableError{}},
+		{name: "reply to an unknown task creates a new task", taskID: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := *textMessage("user", "Yes")
+			message.TaskID = &tt.taskID
+
+			task, err := messageHandler.HandleMessageSend(context.Background(), adk.MessageSendParams{Message: message})
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.IsType(t, tt.expectedErr, err)
+				assert.Nil(t, task)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSame, task.ID == tt.taskID)
+			assert.Equal(t, adk.TaskStateSubmitted, task.Status.State)
+		})
+	}
+}
 func TestDefaultMessageHandler_HandleMessageStream(t *testing.T) {
 	logger := zap.NewNop()
 	mockTaskManager := &mocks.FakeTaskManager{}
@@ -752,3 +789,520 @@ responseLoop:
 	assert.True(t, toolExecutionCompleted, "Tool should have been executed")
 	assert.True(t, foundToolResultWithCallID, "Should have found tool result message with correct tool_call_id")
 }
+
+func TestMessageHandler_HandleMessageStream_ParallelToolCallsKeepOrder(t *testing.T) {
+	logger := zap.NewNop()
+
+	mockLLMClient := &mocks.FakeLLMClient{}
+
+	streamResponseChan := make(chan *sdk.CreateChatCompletionStreamResponse, 5)
+	streamErrorChan := make(chan error, 1)
+
+	toolCallChunk := func(index int, id string, name string) sdk.ChatCompletionMessageToolCallChunk {
+		chunk := sdk.ChatCompletionMessageToolCallChunk{Index: index, ID: id, Type: "function"}
+		chunk.Function.Name = name
+		chunk.Function.Arguments = `{}`
+		return chunk
+	}
+
+	go func() {
+		defer close(streamResponseChan)
+		defer close(streamErrorChan)
+
+		streamResponseChan <- &sdk.CreateChatCompletionStreamResponse{
+			Choices: []sdk.ChatCompletionStreamChoice{
+				{
+					Delta: sdk.ChatCompletionStreamResponseDelta{
+						ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{
+							toolCallChunk(2, "call_2", "fast_tool"),
+							toolCallChunk(0, "call_0", "slow_tool"),
+							toolCallChunk(1, "call_1", "medium_tool"),
+						},
+					},
+				},
+			},
+		}
+
+		streamResponseChan <- &sdk.CreateChatCompletionStreamResponse{
+			Choices: []sdk.ChatCompletionStreamChoice{{FinishReason: "tool_calls"}},
+		}
+
+		streamResponseChan <- &sdk.CreateChatCompletionStreamResponse{
+			Choices: []sdk.ChatCompletionStreamChoice{
+				{
+					Delta:        sdk.ChatCompletionStreamResponseDelta{Content: "done"},
+					FinishReason: "stop",
+				},
+			},
+		}
+	}()
+
+	mockLLMClient.CreateStreamingChatCompletionReturns(streamResponseChan, streamErrorChan)
+
+	toolBox := server.NewDefaultToolBox()
+	for name, delay := range map[string]time.Duration{
+		"slow_tool":   200 * time.Millisecond,
+		"medium_tool": 200 * time.Millisecond,
+		"fast_tool":   0,
+	} {
+		toolBox.AddTool(server.NewBasicTool(name, "A test tool", map[string]interface{}{"type": "object"},
+			func(ctx context.Context, args map[string]interface{}) (string, error) {
+				time.Sleep(delay)
+				return "result", nil
+			}))
+	}
+
+	agent, err := server.NewAgentBuilder(logger).
+		WithLLMClient(mockLLMClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		AgentConfig: config.AgentConfig{
+			MaxChatCompletionIterations: 10,
+			MaxParallelToolCalls:        3,
+		},
+	}
+
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, server.NewDefaultTaskManager(logger, 10), agent, cfg)
+
+	contextID := "test-context"
+	params := adk.MessageSendParams{
+		Message: adk.Message{
+			ContextID: &contextID,
+			Kind:      "message",
+			MessageID: "test-message",
+			Role:      "user",
+			Parts: []adk.Part{
+				map[string]interface{}{
+					"kind": "text",
+					"text": "Please use all tools.",
+				},
+			},
+		},
+	}
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 50)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err = messageHandler.HandleMessageStream(ctx, params, responseChan)
+	require.NoError(t, err)
+
+	var toolCallIDs []string
+	timeout := time.After(time.Second)
+
+responseLoop:
+	for {
+		select {
+		case response := <-responseChan:
+			statusUpdate, ok := response.(adk.TaskStatusUpdateEvent)
+			if !ok {
+				continue
+			}
+			if statusUpdate.Final {
+				break responseLoop
+			}
+			message := statusUpdate.Status.Message
+			if message == nil || message.Role != "tool" {
+				continue
+			}
+			data := message.Parts[0].(map[string]interface{})["data"].(map[string]interface{})
+			toolCallIDs = append(toolCallIDs, data["tool_call_id"].(string))
+		case <-timeout:
+			t.Fatal("Timeout waiting for streaming responses with tool calls")
+		}
+	}
+
+	assert.Equal(t, []string{"call_0", "call_1", "call_2"}, toolCallIDs, "tool results follow the tool call order")
+	assert.Less(t, time.Since(start), 350*time.Millisecond, "tool calls run concurrently")
+}
+
+func TestMessageHandler_HandleMessageStream_ToolInputRequired(t *testing.T) {
+	logger := zap.NewNop()
+
+	streamResponseChan := make(chan *sdk.CreateChatCompletionStreamResponse, 2)
+	streamErrorChan := make(chan error, 1)
+
+	toolCall := sdk.ChatCompletionMessageToolCallChunk{Index: 0, ID: "call_0", Type: "function"}
+	toolCall.Function.Name = "book_flight"
+	toolCall.Function.Arguments = `{}`
+	streamResponseChan <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{
+			{Delta: sdk.ChatCompletionStreamResponseDelta{ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{toolCall}}},
+		},
+	}
+	streamResponseChan <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{{FinishReason: "tool_calls"}},
+	}
+	close(streamResponseChan)
+	close(streamErrorChan)
+
+	mockLLMClient := &mocks.FakeLLMClient{}
+	mockLLMClient.CreateStreamingChatCompletionReturns(streamResponseChan, streamErrorChan)
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("book_flight", "Book a flight", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "", server.NewInputRequiredError("Which date do you want to fly?")
+		}))
+
+	agent, err := server.NewAgentBuilder(logger).
+		WithLLMClient(mockLLMClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		AgentConfig: config.AgentConfig{
+			MaxChatCompletionIterations: 10,
+			MaxParallelToolCalls:        1,
+		},
+	}
+
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, taskManager, agent, cfg)
+
+	contextID := "test-context"
+	params := adk.MessageSendParams{
+		Message: adk.Message{
+			ContextID: &contextID,
+			Kind:      "message",
+			MessageID: "test-message",
+			Role:      "user",
+			Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": "Book a flight to Paris."}},
+		},
+	}
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 50)
+	err = messageHandler.HandleMessageStream(context.Background(), params, responseChan)
+	require.NoError(t, err)
+	close(responseChan)
+
+	var final *adk.TaskStatusUpdateEvent
+	for response := range responseChan {
+		if statusUpdate, ok := response.(adk.TaskStatusUpdateEvent); ok && statusUpdate.Final {
+			final = &statusUpdate
+		}
+	}
+
+	require.NotNil(t, final)
+	assert.Equal(t, adk.TaskStateInputRequired, final.Status.State)
+	require.NotNil(t, final.Status.Message)
+	assert.Equal(t, "Which date do you want to fly?", final.Status.Message.Parts[0].(map[string]interface{})["text"])
+	assert.Equal(t, 1, mockLLMClient.CreateStreamingChatCompletionCallCount(), "the stream stops until the user replies")
+
+	task, exists := taskManager.GetTask(final.TaskID)
+	require.True(t, exists)
+	assert.Equal(t, adk.TaskStateInputRequired, task.Status.State)
+}
+
+func TestMessageHandler_HandleMessageStream_AnswersFailedToolCalls(t *testing.T) {
+	logger := zap.NewNop()
+
+	toolCallStream := make(chan *sdk.CreateChatCompletionStreamResponse, 2)
+	unnamedCall := sdk.ChatCompletionMessageToolCallChunk{Index: 0, ID: "call_0", Type: "function"}
+	unnamedCall.Function.Arguments = `{}`
+	failingCall := sdk.ChatCompletionMessageToolCallChunk{Index: 1, ID: "call_1", Type: "function"}
+	failingCall.Function.Name = "lookup_order"
+	failingCall.Function.Arguments = `{}`
+	toolCallStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{
+			{Delta: sdk.ChatCompletionStreamResponseDelta{ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{unnamedCall, failingCall}}},
+		},
+	}
+	toolCallStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{{FinishReason: "tool_calls"}},
+	}
+	close(toolCallStream)
+
+	answerStream := make(chan *sdk.CreateChatCompletionStreamResponse, 1)
+	answerStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{
+			{Delta: sdk.ChatCompletionStreamResponseDelta{Content: "The order service is down."}, FinishReason: "stop"},
+		},
+	}
+	close(answerStream)
+
+	streamErrorChan := make(chan error)
+	close(streamErrorChan)
+
+	mockLLMClient := &mocks.FakeLLMClient{}
+	mockLLMClient.CreateStreamingChatCompletionReturnsOnCall(0, toolCallStream, streamErrorChan)
+	mockLLMClient.CreateStreamingChatCompletionReturnsOnCall(1, answerStream, streamErrorChan)
+
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup_order", "Look up an order", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "", errors.New("order service unavailable")
+		}))
+
+	agent, err := server.NewAgentBuilder(logger).
+		WithLLMClient(mockLLMClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, taskManager, agent, &config.Config{
+		AgentConfig: config.AgentConfig{MaxChatCompletionIterations: 10},
+	})
+
+	contextID := "test-context"
+	responseChan := make(chan adk.SendStreamingMessageResponse, 50)
+	err = messageHandler.HandleMessageStream(context.Background(), adk.MessageSendParams{
+		Message: adk.Message{
+			ContextID: &contextID,
+			Kind:      "message",
+			MessageID: "test-message",
+			Role:      "user",
+			Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": "Where is my order?"}},
+		},
+	}, responseChan)
+	require.NoError(t, err)
+	close(responseChan)
+
+	var toolResults []adk.Message
+	for response := range responseChan {
+		if statusUpdate, ok := response.(adk.TaskStatusUpdateEvent); ok && statusUpdate.Status.Message != nil {
+			toolResults = append(toolResults, *statusUpdate.Status.Message)
+		}
+	}
+
+	assert.Equal(t, []string{"call_0", "call_1"}, toolResultCallIDs(toolResults), "every tool call is answered")
+	for _, result := range toolResultsOf(toolResults) {
+		assert.Contains(t, result, "Error executing tool")
+	}
+	assert.Equal(t, 2, mockLLMClient.CreateStreamingChatCompletionCallCount(), "the failures are returned to the LLM")
+}
+
+func TestMessageHandler_HandleMessageStream_ToolApproval(t *testing.T) {
+	logger := zap.NewNop()
+
+	toolCallStream := make(chan *sdk.CreateChatCompletionStreamResponse, 2)
+	toolCall := sdk.ChatCompletionMessageToolCallChunk{Index: 0, ID: "call_0", Type: "function"}
+	toolCall.Function.Name = "delete_record"
+	toolCall.Function.Arguments = `{"id":"42"}`
+	toolCallStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{
+			{Delta: sdk.ChatCompletionStreamResponseDelta{ToolCalls: []sdk.ChatCompletionMessageToolCallChunk{toolCall}}},
+		},
+	}
+	toolCallStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{{FinishReason: "tool_calls"}},
+	}
+	close(toolCallStream)
+
+	answerStream := make(chan *sdk.CreateChatCompletionStreamResponse, 1)
+	answerStream <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{
+			{Delta: sdk.ChatCompletionStreamResponseDelta{Content: "Record 7 deleted."}, FinishReason: "stop"},
+		},
+	}
+	close(answerStream)
+
+	streamErrorChan := make(chan error)
+	close(streamErrorChan)
+
+	mockLLMClient := &mocks.FakeLLMClient{}
+	mockLLMClient.CreateStreamingChatCompletionReturnsOnCall(0, toolCallStream, streamErrorChan)
+	mockLLMClient.CreateStreamingChatCompletionReturnsOnCall(1, answerStream, streamErrorChan)
+
+	var deleted []interface{}
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("delete_record", "Delete a record", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			deleted = append(deleted, args["id"])
+			return "deleted", nil
+		},
+		server.WithApprovalRequired()))
+
+	agent, err := server.NewAgentBuilder(logger).
+		WithLLMClient(mockLLMClient).
+		WithToolBox(toolBox).
+		Build()
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		AgentConfig: config.AgentConfig{
+			MaxChatCompletionIterations: 10,
+			MaxParallelToolCalls:        1,
+		},
+	}
+
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, taskManager, agent, cfg)
+
+	stream := func(message adk.Message) *adk.TaskStatusUpdateEvent {
+		responseChan := make(chan adk.SendStreamingMessageResponse, 50)
+		err := messageHandler.HandleMessageStream(context.Background(), adk.MessageSendParams{Message: message}, responseChan)
+		require.NoError(t, err)
+		close(responseChan)
+
+		var final *adk.TaskStatusUpdateEvent
+		for response := range responseChan {
+			if statusUpdate, ok := response.(adk.TaskStatusUpdateEvent); ok && statusUpdate.Final {
+				final = &statusUpdate
+			}
+		}
+		require.NotNil(t, final)
+		return final
+	}
+
+	paused := stream(*textMessage("user", "Delete record 42"))
+	assert.Equal(t, adk.TaskStateInputRequired, paused.Status.State)
+	require.Len(t, paused.Status.Message.Parts, 2)
+	request := paused.Status.Message.Parts[1].(map[string]interface{})["data"].(map[string]interface{})
+	assert.Equal(t, server.ToolApprovalDataType, request["type"])
+	assert.Empty(t, deleted, "the tool waits for approval")
+
+	reply := adk.Message{
+		Kind:      "message",
+		MessageID: "reply",
+		Role:      "user",
+		TaskID:    &paused.TaskID,
+		Parts: []adk.Part{
+			map[string]interface{}{"kind": "data", "data": map[string]interface{}{"arguments": map[string]interface{}{"id": "7"}}},
+		},
+	}
+	final := stream(reply)
+	assert.Equal(t, paused.TaskID, final.TaskID, "the reply resumes the task")
+	assert.Equal(t, adk.TaskStateCompleted, final.Status.State)
+	assert.Equal(t, []interface{}{"7"}, deleted, "the tool runs with the edited arguments")
+
+	task, exists := taskManager.GetTask(final.TaskID)
+	require.True(t, exists)
+	assert.Equal(t, adk.TaskStateCompleted, task.Status.State)
+	assert.NotContains(t, task.Metadata, server.MetadataPendingToolApprovalKey)
+
+	var approval interface{}
+	for _, message := range task.History {
+		if message.Role == "tool" {
+			approval = message.Parts[0].(map[string]interface{})["data"].(map[string]interface{})["approval"]
+		}
+	}
+	assert.Equal(t, "edited", approval)
+}
+
+func TestMessageHandler_HandleMessageStream_SkillRouter(t *testing.T) {
+	logger := zap.NewNop()
+
+	newStreamingLLM := func(content string) *mocks.FakeLLMClient {
+		llmClient := &mocks.FakeLLMClient{}
+		llmClient.CreateStreamingChatCompletionStub = func(ctx context.Context, messages []sdk.Message, tools ...sdk.ChatCompletionTool) (<-chan *sdk.CreateChatCompletionStreamResponse, <-chan error) {
+			responses := make(chan *sdk.CreateChatCompletionStreamResponse, 1)
+			errs := make(chan error)
+			responses <- &sdk.CreateChatCompletionStreamResponse{
+				Choices: []sdk.ChatCompletionStreamChoice{
+					{Delta: sdk.ChatCompletionStreamResponseDelta{Content: content}, FinishReason: "stop"},
+				},
+			}
+			close(responses)
+			return responses, errs
+		}
+		return llmClient
+	}
+
+	defaultLLM := newStreamingLLM("default")
+	defaultAgent, err := server.NewAgentBuilder(logger).WithLLMClient(defaultLLM).Build()
+	require.NoError(t, err)
+
+	weatherLLM := newStreamingLLM("sunny")
+	weatherAgent, err := server.NewAgentBuilder(logger).WithLLMClient(weatherLLM).Build()
+	require.NoError(t, err)
+
+	router, err := server.NewSkillRouter(logger, []adk.AgentSkill{{ID: "weather", Name: "Weather", Tags: []string{"weather"}}}, defaultAgent,
+		server.WithSkillRoute("weather", server.SkillRoute{Agent: weatherAgent}))
+	require.NoError(t, err)
+
+	cfg := &config.Config{AgentConfig: config.AgentConfig{MaxChatCompletionIterations: 10}}
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, taskManager, router, cfg)
+
+	params := adk.MessageSendParams{
+		Message: adk.Message{
+			Kind:      "message",
+			MessageID: "test-message",
+			Role:      "user",
+			Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": "What is the weather like?"}},
+		},
+	}
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 20)
+	err = messageHandler.HandleMessageStream(context.Background(), params, responseChan)
+	require.NoError(t, err)
+	close(responseChan)
+
+	var taskID string
+	for response := range responseChan {
+		if statusUpdate, ok := response.(adk.TaskStatusUpdateEvent); ok {
+			taskID = statusUpdate.TaskID
+		}
+	}
+
+	assert.Equal(t, 1, weatherLLM.CreateStreamingChatCompletionCallCount())
+	assert.Zero(t, defaultLLM.CreateStreamingChatCompletionCallCount())
+
+	task, exists := taskManager.GetTask(taskID)
+	require.True(t, exists)
+	assert.Equal(t, "weather", task.Metadata[server.MetadataSkillIDKey])
+}
+
+func TestMessageHandler_HandleMessageStream_WorkflowAgent(t *testing.T) {
+	logger := zap.NewNop()
+
+	step := func(name string) server.WorkflowStep {
+		return server.NewHandlerStep(name, taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			output := textMessage("assistant", name+" done")
+			task.History = append(task.History, *output)
+			task.Artifacts = append(task.Artifacts, adk.Artifact{ArtifactID: name})
+			task.Status.State = adk.TaskStateCompleted
+			task.Status.Message = output
+			return task, nil
+		}))
+	}
+	workflow := server.NewSequentialAgent(logger, step("research"), step("summarize"))
+
+	cfg := &config.Config{AgentConfig: config.AgentConfig{MaxChatCompletionIterations: 10}}
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(logger, taskManager, workflow, cfg)
+
+	params := adk.MessageSendParams{
+		Message: adk.Message{
+			Kind:      "message",
+			MessageID: "test-message",
+			Role:      "user",
+			Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": "Summarize the news"}},
+		},
+	}
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 20)
+	err := messageHandler.HandleMessageStream(context.Background(), params, responseChan)
+	require.NoError(t, err)
+	close(responseChan)
+
+	var progress []string
+	var artifactIDs []string
+	var final *adk.TaskStatusUpdateEvent
+	for response := range responseChan {
+		switch event := response.(type) {
+		case adk.TaskStatusUpdateEvent:
+			if event.Final {
+				final = &event
+			} else if event.Status.Message != nil && event.Status.Message.Role != "user" {
+				progress = append(progress, textOf(event.Status.Message))
+			}
+		case adk.TaskArtifactUpdateEvent:
+			artifactIDs = append(artifactIDs, event.Artifact.ArtifactID)
+		}
+	}
+
+	assert.Equal(t, []string{"Running step research", "research done", "Running step summarize", "summarize done"}, progress)
+	assert.Equal(t, []string{"research", "summarize"}, artifactIDs)
+	require.NotNil(t, final)
+	assert.Equal(t, adk.TaskStateCompleted, final.Status.State)
+	assert.Equal(t, "summarize done", textOf(final.Status.Message))
+}
@@ -2,11 +2,14 @@ package middlewares
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	oidcV3 "github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
+	adk "github.com/inference-gateway/a2a/adk"
 	config "github.com/inference-gateway/a2a/adk/server/config"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
@@ -19,18 +22,156 @@ const (
 	IDTokenContextKey   contextKey = "idToken"
 )
 
+// Supported authentication scheme identifiers
+const (
+	AuthSchemeOIDC   = "oidc"
+	AuthSchemeJWT    = "jwt"
+	AuthSchemeAPIKey = "apikey"
+	AuthSchemeBasic  = "basic"
+	AuthSchemeMTLS   = "mtls"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no credentials for its scheme
+var ErrNoCredentials = errors.New("no credentials provided")
+
 // OIDCAuthenticator interface for authentication middleware
 type OIDCAuthenticator interface {
 	Middleware() gin.HandlerFunc
 }
 
+// Authenticator verifies the credentials of a request for a single security scheme
+type Authenticator interface {
+	// SchemeName returns the name the security scheme is published under in the agent card
+	SchemeName() string
+
+	// SecurityScheme returns the agent card description of the security scheme
+	SecurityScheme() adk.SecurityScheme
+
+	// Authenticate verifies the request credentials and returns the authenticated principal
+	// ErrNoCredentials is returned when the request carries no credentials for this scheme
+	Authenticate(c *gin.Context) (*Principal, error)
+}
+
+// AuthenticatorChain accepts a request as soon as one of its authenticators verifies it
+type AuthenticatorChain struct {
+	logger         *zap.Logger
+	authenticators []Authenticator
+}
+
+var _ OIDCAuthenticator = (*AuthenticatorChain)(nil)
+
+// NewAuthenticatorChain creates a middleware accepting any of the given authenticators
+func NewAuthenticatorChain(logger *zap.Logger, authenticators ...Authenticator) *AuthenticatorChain {
+	return &AuthenticatorChain{
+		logger:         logger,
+		authenticators: authenticators,
+	}
+}
+
+// NewAuthenticators creates the authenticators for the schemes enabled in the configuration
+func NewAuthenticators(logger *zap.Logger, cfg config.Config) ([]Authenticator, error) {
+	if len(cfg.AuthConfig.Schemes) == 0 {
+		return nil, fmt.Errorf("no authentication schemes configured")
+	}
+
+	authenticators := make([]Authenticator, 0, len(cfg.AuthConfig.Schemes))
+	for _, scheme := range cfg.AuthConfig.Schemes {
+		var authenticator Authenticator
+		var err error
+
+		switch strings.ToLower(strings.TrimSpace(scheme)) {
+		case AuthSchemeOIDC:
+			authenticator, err = newOIDCAuthenticator(logger, cfg.AuthConfig)
+		case AuthSchemeJWT:
+			authenticator, err = NewJWTAuthenticator(logger, cfg.AuthConfig)
+		case AuthSchemeAPIKey:
+			authenticator, err = NewAPIKeyAuthenticator(logger, cfg.AuthConfig)
+		case AuthSchemeBasic:
+			authenticator, err = NewBasicAuthenticator(logger, cfg.AuthConfig)
+		case AuthSchemeMTLS:
+			authenticator, err = NewMTLSAuthenticator(logger, cfg)
+		default:
+			err = fmt.Errorf("unsupported authentication scheme %q", scheme)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s authenticator: %w", scheme, err)
+		}
+
+		authenticators = append(authenticators, authenticator)
+	}
+
+	return authenticators, nil
+}
+
+// Authenticators returns the authenticators of the chain
+func (chain *AuthenticatorChain) Authenticators() []Authenticator {
+	return chain.authenticators
+}
+
+// SecuritySchemes returns the agent card security schemes of all authenticators
+func (chain *AuthenticatorChain) SecuritySchemes() map[string]adk.SecurityScheme {
+	schemes := make(map[string]adk.SecurityScheme, len(chain.authenticators))
+	for _, authenticator := range chain.authenticators {
+		schemes[authenticator.SchemeName()] = authenticator.SecurityScheme()
+	}
+	return schemes
+}
+
+// Security returns the agent card security requirements, satisfying any one of the schemes is sufficient
+func (chain *AuthenticatorChain) Security() []map[string][]string {
+	security := make([]map[string][]string, 0, len(chain.authenticators))
+	for _, authenticator := range chain.authenticators {
+		security = append(security, map[string][]string{authenticator.SchemeName(): {}})
+	}
+	return security
+}
+
+// Middleware returns the authentication middleware for AuthenticatorChain
+func (chain *AuthenticatorChain) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var lastErr error
+		for _, authenticator := range chain.authenticators {
+			principal, err := authenticator.Authenticate(c)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				chain.logger.Debug("authentication failed",
+					zap.String("scheme", authenticator.SchemeName()),
+					zap.Error(err))
+				lastErr = err
+				continue
+			}
+
+			c.Set(string(PrincipalContextKey), principal)
+			c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), principal))
+			c.Next()
+			return
+		}
+
+		if lastErr != nil {
+			chain.logger.Error("failed to authenticate request", zap.Error(lastErr))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+			c.Abort()
+			return
+		}
+
+		chain.logger.Error("missing credentials")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+		c.Abort()
+	}
+}
+
 // OIDCAuthenticatorImpl implements OIDC authentication
 type OIDCAuthenticatorImpl struct {
-	logger   *zap.Logger
-	verifier *oidcV3.IDTokenVerifier
-	config   oauth2.Config
+	logger     *zap.Logger
+	verifier   *oidcV3.IDTokenVerifier
+	config     oauth2.Config
+	authConfig config.AuthConfig
 }
 
+var _ Authenticator = (*OIDCAuthenticatorImpl)(nil)
+
 // OIDCAuthenticatorNoop is a no-op authenticator for when auth is disabled
 type OIDCAuthenticatorNoop struct{}
 
@@ -40,64 +181,77 @@ func NewOIDCAuthenticatorMiddleware(logger *zap.Logger, cfg config.Config) (OIDC
 		return &OIDCAuthenticatorNoop{}, nil
 	}
 
-	if cfg.AuthConfig.IssuerURL == "" || cfg.AuthConfig.ClientID == "" || cfg.AuthConfig.ClientSecret == "" {
-		logger.Warn("AuthConfig is enabled but required fields are missing, disabling authentication")
-		return &OIDCAuthenticatorNoop{}, nil
+	return newOIDCAuthenticator(logger, cfg.AuthConfig)
+}
+
+// newOIDCAuthenticator discovers the OIDC provider and creates the token verifier.
+// The client secret is not needed to verify tokens and is therefore optional.
+func newOIDCAuthenticator(logger *zap.Logger, authConfig config.AuthConfig) (*OIDCAuthenticatorImpl, error) {
+	if authConfig.IssuerURL == "" || authConfig.ClientID == "" {
+		return nil, fmt.Errorf("oidc authentication requires an issuer url and a client id")
 	}
 
-	provider, err := oidcV3.NewProvider(context.Background(), cfg.AuthConfig.IssuerURL)
+	provider, err := oidcV3.NewProvider(context.Background(), authConfig.IssuerURL)
 	if err != nil {
 		return nil, err
 	}
 
 	oidcConfig := &oidcV3.Config{
-		ClientID: cfg.AuthConfig.ClientID,
+		ClientID: authConfig.ClientID,
 	}
 
 	return &OIDCAuthenticatorImpl{
-		logger:   logger,
-		verifier: provider.Verifier(oidcConfig),
+		logger:     logger,
+		verifier:   provider.Verifier(oidcConfig),
+		authConfig: authConfig,
 		config: oauth2.Config{
-			ClientID:     cfg.AuthConfig.ClientID,
-			ClientSecret: cfg.AuthConfig.ClientSecret,
+			ClientID:     authConfig.ClientID,
+			ClientSecret: authConfig.ClientSecret,
 			Endpoint:     provider.Endpoint(),
 			Scopes:       []string{oidcV3.ScopeOpenID, "profile", "email"},
 		},
 	}, nil
 }
 
-// Middleware returns the OIDC authentication middleware for OIDCAuthenticatorImpl
-func (auth *OIDCAuthenticatorImpl) Middleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			auth.logger.Error("missing authorization header")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
-			c.Abort()
-			return
-		}
+// SchemeName returns the name of the OIDC security scheme
+func (auth *OIDCAuthenticatorImpl) SchemeName() string {
+	return AuthSchemeOIDC
+}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			auth.logger.Error("invalid authorization header format")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
-			c.Abort()
-			return
-		}
+// SecurityScheme returns the OpenID Connect security scheme pointing to the discovery document
+func (auth *OIDCAuthenticatorImpl) SecurityScheme() adk.SecurityScheme {
+	return adk.OpenIdConnectSecurityScheme{
+		Type:             "openIdConnect",
+		OpenIDConnectURL: strings.TrimSuffix(auth.authConfig.IssuerURL, "/") + "/.well-known/openid-configuration",
+	}
+}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
+// Authenticate verifies the bearer ID token of the request
+func (auth *OIDCAuthenticatorImpl) Authenticate(c *gin.Context) (*Principal, error) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
 
-		idToken, err := auth.verifier.Verify(c.Request.Context(), token)
-		if err != nil {
-			auth.logger.Error("failed to verify id token", zap.Error(err))
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
-			return
-		}
+	idToken, err := auth.verifier.Verify(c.Request.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id token: %w", err)
+	}
 
-		c.Set(string(AuthTokenContextKey), token)
-		c.Set(string(IDTokenContextKey), idToken)
-		c.Next()
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
 	}
+
+	c.Set(string(AuthTokenContextKey), token)
+	c.Set(string(IDTokenContextKey), idToken)
+
+	return NewPrincipalFromClaims(AuthSchemeOIDC, idToken.Subject, claims, auth.authConfig), nil
+}
+
+// Middleware returns the OIDC authentication middleware for OIDCAuthenticatorImpl
+func (auth *OIDCAuthenticatorImpl) Middleware() gin.HandlerFunc {
+	return NewAuthenticatorChain(auth.logger, auth).Middleware()
 }
 
 // Middleware returns a no-op middleware for OIDCAuthenticatorNoop
@@ -106,3 +260,25 @@ func (auth *OIDCAuthenticatorNoop) Middleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// bearerToken extracts the bearer token from the Authorization header
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return "", false
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	return token, token != ""
+}
+
+// staticPrincipal creates the principal of a subject authenticated by a static credential of the scheme
+func staticPrincipal(scheme string, subject string, authConfig config.AuthConfig) *Principal {
+	principal := &Principal{
+		Subject: subject,
+		Issuer:  scheme,
+		Roles:   strings.Fields(authConfig.SubjectRoles[subject]),
+	}
+	principal.Admin = principal.HasRole(authConfig.AdminRole)
+	return principal
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	"go.uber.org/zap"
+)
+
+// APIKeyAuthenticator verifies static API keys sent in a header or a query parameter
+type APIKeyAuthenticator struct {
+	logger     *zap.Logger
+	name       string
+	in         string
+	keys       map[string]string
+	authConfig config.AuthConfig
+}
+
+var _ Authenticator = (*APIKeyAuthenticator)(nil)
+
+// NewAPIKeyAuthenticator creates an API key authenticator from the configured keys
+func NewAPIKeyAuthenticator(logger *zap.Logger, authConfig config.AuthConfig) (*APIKeyAuthenticator, error) {
+	if len(authConfig.APIKeys) == 0 {
+		return nil, fmt.Errorf("api key authentication requires at least one api key")
+	}
+
+	if authConfig.APIKeyName == "" {
+		return nil, fmt.Errorf("api key authentication requires an api key name")
+	}
+
+	if authConfig.APIKeyIn != "header" && authConfig.APIKeyIn != "query" {
+		return nil, fmt.Errorf("unsupported api key location %q, expected header or query", authConfig.APIKeyIn)
+	}
+
+	for subject, key := range authConfig.APIKeys {
+		if key == "" {
+			return nil, fmt.Errorf("empty api key configured for %s", subject)
+		}
+	}
+
+	return &APIKeyAuthenticator{
+		logger:     logger,
+		name:       authConfig.APIKeyName,
+		in:         authConfig.APIKeyIn,
+		keys:       authConfig.APIKeys,
+		authConfig: authConfig,
+	}, nil
+}
+
+// SchemeName returns the name of the API key security scheme
+func (auth *APIKeyAuthenticator) SchemeName() string {
+	return AuthSchemeAPIKey
+}
+
+// SecurityScheme returns the API key security scheme
+func (auth *APIKeyAuthenticator) SecurityScheme() adk.SecurityScheme {
+	return adk.APIKeySecurityScheme{
+		Type: "apiKey",
+		In:   auth.in,
+		Name: auth.name,
+	}
+}
+
+// Authenticate looks up the subject owning the API key of the request
+func (auth *APIKeyAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	var provided string
+	if auth.in == "query" {
+		provided = c.Query(auth.name)
+	} else {
+		provided = c.GetHeader(auth.name)
+	}
+
+	if provided == "" {
+		return nil, ErrNoCredentials
+	}
+
+	for subject, key := range auth.keys {
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1 {
+			return staticPrincipal(AuthSchemeAPIKey, subject, auth.authConfig), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown api key")
+}
@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BasicAuthenticator verifies HTTP basic credentials against the configured users
+type BasicAuthenticator struct {
+	logger     *zap.Logger
+	users      map[string]string
+	authConfig config.AuthConfig
+}
+
+var _ Authenticator = (*BasicAuthenticator)(nil)
+
+// NewBasicAuthenticator creates an HTTP basic authenticator from the configured users
+func NewBasicAuthenticator(logger *zap.Logger, authConfig config.AuthConfig) (*BasicAuthenticator, error) {
+	if len(authConfig.BasicUsers) == 0 {
+		return nil, fmt.Errorf("basic authentication requires at least one user")
+	}
+
+	return &BasicAuthenticator{
+		logger:     logger,
+		users:      authConfig.BasicUsers,
+		authConfig: authConfig,
+	}, nil
+}
+
+// SchemeName returns the name of the HTTP basic security scheme
+func (auth *BasicAuthenticator) SchemeName() string {
+	return AuthSchemeBasic
+}
+
+// SecurityScheme returns the HTTP basic security scheme
+func (auth *BasicAuthenticator) SecurityScheme() adk.SecurityScheme {
+	return adk.HTTPAuthSecurityScheme{
+		Type:   "http",
+		Scheme: "basic",
+	}
+}
+
+// Authenticate verifies the basic credentials of the request
+func (auth *BasicAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	username, password, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	expected, exists := auth.users[username]
+	if !exists || !passwordMatches(expected, password) {
+		return nil, fmt.Errorf("invalid credentials for user %s", username)
+	}
+
+	return staticPrincipal(AuthSchemeBasic, username, auth.authConfig), nil
+}
+
+// passwordMatches compares the password against a bcrypt hash or a plain text password
+func passwordMatches(expected string, password string) bool {
+	if strings.HasPrefix(expected, "$2a$") || strings.HasPrefix(expected, "$2b$") || strings.HasPrefix(expected, "$2y$") {
+		return bcrypt.CompareHashAndPassword([]byte(expected), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(password)) == 1
+}
@@ -0,0 +1,166 @@
+package middlewares
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	oidcV3 "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	jose "github.com/go-jose/go-jose/v4"
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	"go.uber.org/zap"
+)
+
+// JWTAuthenticator verifies bearer JWTs against locally configured keys, without network discovery
+type JWTAuthenticator struct {
+	logger     *zap.Logger
+	verifier   *oidcV3.IDTokenVerifier
+	authConfig config.AuthConfig
+}
+
+var _ Authenticator = (*JWTAuthenticator)(nil)
+
+// NewJWTAuthenticator creates a JWT authenticator using the configured JWKS file and/or public key file
+func NewJWTAuthenticator(logger *zap.Logger, authConfig config.AuthConfig) (*JWTAuthenticator, error) {
+	var keys []crypto.PublicKey
+
+	if authConfig.JWKSFile != "" {
+		jwksKeys, err := loadJWKS(authConfig.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwksKeys...)
+	}
+
+	if authConfig.JWTPublicKeyFile != "" {
+		key, err := loadPublicKey(authConfig.JWTPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwt authentication requires a jwks file or a public key file")
+	}
+
+	verifier := oidcV3.NewVerifier(authConfig.JWTIssuer, &oidcV3.StaticKeySet{PublicKeys: keys}, &oidcV3.Config{
+		ClientID:          authConfig.JWTAudience,
+		SkipClientIDCheck: authConfig.JWTAudience == "",
+		SkipIssuerCheck:   authConfig.JWTIssuer == "",
+		SupportedSigningAlgs: []string{
+			oidcV3.RS256, oidcV3.RS384, oidcV3.RS512,
+			oidcV3.ES256, oidcV3.ES384, oidcV3.ES512,
+			oidcV3.PS256, oidcV3.PS384, oidcV3.PS512,
+			oidcV3.EdDSA,
+		},
+	})
+
+	return &JWTAuthenticator{
+		logger:     logger,
+		verifier:   verifier,
+		authConfig: authConfig,
+	}, nil
+}
+
+// SchemeName returns the name of the JWT security scheme
+func (auth *JWTAuthenticator) SchemeName() string {
+	return AuthSchemeJWT
+}
+
+// SecurityScheme returns the HTTP bearer security scheme
+func (auth *JWTAuthenticator) SecurityScheme() adk.SecurityScheme {
+	bearerFormat := "JWT"
+	return adk.HTTPAuthSecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: &bearerFormat,
+	}
+}
+
+// Authenticate verifies the bearer JWT of the request
+func (auth *JWTAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	token, ok := bearerToken(c)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	jwt, err := auth.verifier.Verify(c.Request.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify jwt: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := jwt.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse jwt claims: %w", err)
+	}
+
+	c.Set(string(AuthTokenContextKey), token)
+
+	return NewPrincipalFromClaims(AuthSchemeJWT, jwt.Subject, claims, auth.authConfig), nil
+}
+
+// loadJWKS reads the public keys of a JWKS file
+func loadJWKS(path string) ([]crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks file: %w", err)
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if !jwk.IsPublic() {
+			jwk = jwk.Public()
+		}
+		if jwk.Key == nil {
+			continue
+		}
+		keys = append(keys, jwk.Key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks file %s contains no usable keys", path)
+	}
+
+	return keys, nil
+}
+
+// loadPublicKey reads a PEM encoded public key or certificate
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key file %s is not PEM encoded", path)
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return key, nil
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	adk "github.com/inference-gateway/a2a/adk"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	"go.uber.org/zap"
+)
+
+// MTLSAuthenticator authenticates requests by their verified TLS client certificate.
+// The certificate common name becomes the subject of the principal.
+type MTLSAuthenticator struct {
+	logger     *zap.Logger
+	clientCAs  *x509.CertPool
+	authConfig config.AuthConfig
+}
+
+var _ Authenticator = (*MTLSAuthenticator)(nil)
+
+// NewMTLSAuthenticator creates an mTLS authenticator, the server must serve TLS for client certificates to be presented
+func NewMTLSAuthenticator(logger *zap.Logger, cfg config.Config) (*MTLSAuthenticator, error) {
+	if !cfg.ServerConfig.TLSConfig.Enable {
+		return nil, fmt.Errorf("mtls authentication requires server tls to be enabled")
+	}
+
+	if cfg.AuthConfig.MTLSClientCAFile == "" {
+		return nil, fmt.Errorf("mtls authentication requires a client ca file")
+	}
+
+	data, err := os.ReadFile(cfg.AuthConfig.MTLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client ca file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("client ca file %s contains no certificates", cfg.AuthConfig.MTLSClientCAFile)
+	}
+
+	return &MTLSAuthenticator{
+		logger:     logger,
+		clientCAs:  clientCAs,
+		authConfig: cfg.AuthConfig,
+	}, nil
+}
+
+// ClientCAs returns the pool client certificates are verified against during the TLS handshake
+func (auth *MTLSAuthenticator) ClientCAs() *x509.CertPool {
+	return auth.clientCAs
+}
+
+// SchemeName returns the name of the mutual TLS security scheme
+func (auth *MTLSAuthenticator) SchemeName() string {
+	return AuthSchemeMTLS
+}
+
+// SecurityScheme returns the mutual TLS security scheme
+func (auth *MTLSAuthenticator) SecurityScheme() adk.SecurityScheme {
+	return map[string]interface{}{
+		"type":        "mutualTLS",
+		"description": "Client certificate issued by a trusted certificate authority",
+	}
+}
+
+// Authenticate reads the subject of the client certificate verified during the TLS handshake
+func (auth *MTLSAuthenticator) Authenticate(c *gin.Context) (*Principal, error) {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil, ErrNoCredentials
+	}
+
+	cert := c.Request.TLS.VerifiedChains[0][0]
+	if cert.Subject.CommonName == "" {
+		return nil, fmt.Errorf("client certificate has no common name")
+	}
+
+	return staticPrincipal(AuthSchemeMTLS, cert.Subject.CommonName, auth.authConfig), nil
+}
@@ -0,0 +1,385 @@
+package middlewares_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newAuthRouter(t *testing.T, authenticators ...middlewares.Authenticator) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	chain := middlewares.NewAuthenticatorChain(zap.NewNop(), authenticators...)
+	router.POST("/a2a", chain.Middleware(), func(c *gin.Context) {
+		principal, ok := middlewares.PrincipalFromContext(c.Request.Context())
+		require.True(t, ok)
+		c.JSON(http.StatusOK, principal)
+	})
+	return router
+}
+
+func decodePrincipal(t *testing.T, w *httptest.ResponseRecorder) middlewares.Principal {
+	t.Helper()
+
+	var principal middlewares.Principal
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &principal))
+	return principal
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	tests := []struct {
+		name            string
+		in              string
+		setup           func(req *http.Request)
+		expectedStatus  int
+		expectedSubject string
+	}{
+		{
+			name: "valid key in header",
+			in:   "header",
+			setup: func(req *http.Request) {
+				req.Header.Set("X-API-Key", "secret-1")
+			},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "ci-bot",
+		},
+		{
+			name: "valid key in query",
+			in:   "query",
+			setup: func(req *http.Request) {
+				req.URL.RawQuery = "X-API-Key=secret-1"
+			},
+			expectedStatus:  http.StatusOK,
+			expectedSubject: "ci-bot",
+		},
+		{
+			name: "unknown key",
+			in:   "header",
+			setup: func(req *http.Request) {
+				req.Header.Set("X-API-Key", "wrong")
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing key",
+			in:             "header",
+			setup:          func(req *http.Request) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := middlewares.NewAPIKeyAuthenticator(zap.NewNop(), config.AuthConfig{
+				APIKeys:      map[string]string{"ci-bot": "secret-1"},
+				APIKeyName:   "X-API-Key",
+				APIKeyIn:     tt.in,
+				SubjectRoles: map[string]string{"ci-bot": "automation"},
+			})
+			require.NoError(t, err)
+
+			router := newAuthRouter(t, authenticator)
+			req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				principal := decodePrincipal(t, w)
+				assert.Equal(t, tt.expectedSubject, principal.Subject)
+				assert.Equal(t, "apikey|"+tt.expectedSubject, principal.Owner(), "the subject is qualified by the scheme")
+				assert.Equal(t, []string{"automation"}, principal.Roles)
+			}
+		})
+	}
+}
+
+func TestNewAPIKeyAuthenticator_InvalidConfig(t *testing.T) {
+	_, err := middlewares.NewAPIKeyAuthenticator(zap.NewNop(), config.AuthConfig{APIKeyName: "X-API-Key", APIKeyIn: "header"})
+	assert.Error(t, err)
+
+	_, err = middlewares.NewAPIKeyAuthenticator(zap.NewNop(), config.AuthConfig{
+		APIKeys:    map[string]string{"ci-bot": "secret"},
+		APIKeyName: "X-API-Key",
+		APIKeyIn:   "cookie",
+	})
+	assert.Error(t, err)
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("hashed-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	authenticator, err := middlewares.NewBasicAuthenticator(zap.NewNop(), config.AuthConfig{
+		BasicUsers: map[string]string{
+			"alice": "plain-password",
+			"bob":   string(hash),
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		username       string
+		password       string
+		expectedStatus int
+	}{
+		{name: "plain text password", username: "alice", password: "plain-password", expectedStatus: http.StatusOK},
+		{name: "bcrypt password", username: "bob", password: "hashed-password", expectedStatus: http.StatusOK},
+		{name: "wrong password", username: "alice", password: "wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown user", username: "mallory", password: "plain-password", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter(t, authenticator)
+			req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+			req.SetBasicAuth(tt.username, tt.password)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				principal := decodePrincipal(t, w)
+				assert.Equal(t, tt.username, principal.Subject)
+				assert.Equal(t, "basic|"+tt.username, principal.Owner(), "the subject is qualified by the scheme")
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	jwksPath := filepath.Join(dir, "jwks.json")
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(jwksPath, jwks, 0o600))
+
+	publicKeyPath := filepath.Join(dir, "public.pem")
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	sign := func(signingKey *rsa.PrivateKey, claims map[string]interface{}) string {
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: signingKey}, nil)
+		require.NoError(t, err)
+		payload, err := json.Marshal(claims)
+		require.NoError(t, err)
+		jws, err := signer.Sign(payload)
+		require.NoError(t, err)
+		token, err := jws.CompactSerialize()
+		require.NoError(t, err)
+		return token
+	}
+
+	validClaims := map[string]interface{}{
+		"iss":   "https://issuer.example.com",
+		"aud":   "a2a-agent",
+		"sub":   "service-a",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "a2a:read",
+	}
+
+	tests := []struct {
+		name           string
+		authConfig     config.AuthConfig
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "valid token verified with jwks file",
+			authConfig:     config.AuthConfig{JWKSFile: jwksPath, JWTIssuer: "https://issuer.example.com", JWTAudience: "a2a-agent", ScopesClaim: "scope"},
+			token:          sign(key, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid token verified with public key file",
+			authConfig:     config.AuthConfig{JWTPublicKeyFile: publicKeyPath, ScopesClaim: "scope"},
+			token:          sign(key, validClaims),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token signed with another key",
+			authConfig:     config.AuthConfig{JWKSFile: jwksPath},
+			token:          sign(otherKey, validClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong audience",
+			authConfig:     config.AuthConfig{JWKSFile: jwksPath, JWTAudience: "another-agent"},
+			token:          sign(key, validClaims),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired token",
+			authConfig: config.AuthConfig{JWKSFile: jwksPath},
+			token: sign(key, map[string]interface{}{
+				"sub": "service-a",
+				"exp": time.Now().Add(-time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := middlewares.NewJWTAuthenticator(zap.NewNop(), tt.authConfig)
+			require.NoError(t, err)
+
+			router := newAuthRouter(t, authenticator)
+			req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				principal := decodePrincipal(t, w)
+				assert.Equal(t, "service-a", principal.Subject)
+				assert.Equal(t, []string{"a2a:read"}, principal.Scopes)
+			}
+		})
+	}
+}
+
+func TestNewJWTAuthenticator_RequiresKeys(t *testing.T) {
+	_, err := middlewares.NewJWTAuthenticator(zap.NewNop(), config.AuthConfig{})
+	assert.Error(t, err)
+}
+
+func TestMTLSAuthenticator(t *testing.T) {
+	dir := t.TempDir()
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caPath := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600))
+
+	_, err = middlewares.NewMTLSAuthenticator(zap.NewNop(), config.Config{
+		AuthConfig: config.AuthConfig{MTLSClientCAFile: caPath},
+	})
+	assert.Error(t, err, "server tls is required")
+
+	authenticator, err := middlewares.NewMTLSAuthenticator(zap.NewNop(), config.Config{
+		AuthConfig:   config.AuthConfig{MTLSClientCAFile: caPath, SubjectRoles: map[string]string{"agent-b": "peer"}},
+		ServerConfig: config.ServerConfig{TLSConfig: config.TLSConfig{Enable: true}},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, authenticator.ClientCAs())
+
+	router := newAuthRouter(t, authenticator)
+
+	req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "agent-b"}}}},
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	principal := decodePrincipal(t, w)
+	assert.Equal(t, "agent-b", principal.Subject)
+	assert.Equal(t, []string{"peer"}, principal.Roles)
+}
+
+func TestAuthenticatorChain_CombinesSchemes(t *testing.T) {
+	apiKey, err := middlewares.NewAPIKeyAuthenticator(zap.NewNop(), config.AuthConfig{
+		APIKeys:    map[string]string{"ci-bot": "secret-1"},
+		APIKeyName: "X-API-Key",
+		APIKeyIn:   "header",
+	})
+	require.NoError(t, err)
+	basic, err := middlewares.NewBasicAuthenticator(zap.NewNop(), config.AuthConfig{
+		BasicUsers: map[string]string{"alice": "password"},
+	})
+	require.NoError(t, err)
+
+	chain := middlewares.NewAuthenticatorChain(zap.NewNop(), apiKey, basic)
+	assert.Len(t, chain.SecuritySchemes(), 2)
+	assert.Equal(t, []map[string][]string{{"apikey": {}}, {"basic": {}}}, chain.Security())
+
+	router := newAuthRouter(t, apiKey, basic)
+
+	req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	req.SetBasicAuth("alice", "password")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", decodePrincipal(t, w).Subject)
+
+	req = httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	req.Header.Set("X-API-Key", "secret-1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ci-bot", decodePrincipal(t, w).Subject)
+}
+
+func TestNewAuthenticators_InvalidSchemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemes []string
+	}{
+		{name: "no schemes", schemes: nil},
+		{name: "unsupported scheme", schemes: []string{"kerberos"}},
+		{name: "api key without keys", schemes: []string{"apikey"}},
+		{name: "oidc without issuer", schemes: []string{"oidc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := middlewares.NewAuthenticators(zap.NewNop(), config.Config{
+				AuthConfig: config.AuthConfig{
+					Enable:     true,
+					Schemes:    tt.schemes,
+					APIKeyName: "X-API-Key",
+					APIKeyIn:   "header",
+				},
+			})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package middlewares
+
+import (
+	"fmt"
+	"strings"
+
+	config "github.com/inference-gateway/a2a/adk/server/config"
+)
+
+// Requirement describes what a principal needs to hold to perform an operation.
+// All scopes are required, while holding any one of the roles is sufficient.
+type Requirement struct {
+	Scopes []string `json:"scopes,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+}
+
+// IsEmpty checks if the requirement does not restrict anything
+func (r Requirement) IsEmpty() bool {
+	return len(r.Scopes) == 0 && len(r.Roles) == 0
+}
+
+// SatisfiedBy checks if the principal fulfills the requirement, admins always do
+func (r Requirement) SatisfiedBy(principal *Principal) bool {
+	if principal == nil || principal.Admin {
+		return true
+	}
+
+	for _, scope := range r.Scopes {
+		if !principal.HasScope(scope) {
+			return false
+		}
+	}
+
+	if len(r.Roles) == 0 {
+		return true
+	}
+	for _, role := range r.Roles {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizationPolicy maps JSON-RPC methods and agent skill IDs to the requirements callers must fulfill
+type AuthorizationPolicy struct {
+	Methods map[string]Requirement
+	Skills  map[string]Requirement
+}
+
+// NewAuthorizationPolicy builds the authorization policy from the auth configuration
+func NewAuthorizationPolicy(cfg config.AuthConfig) *AuthorizationPolicy {
+	return &AuthorizationPolicy{
+		Methods: buildRequirements(cfg.MethodScopes, cfg.MethodRoles),
+		Skills:  buildRequirements(cfg.SkillScopes, cfg.SkillRoles),
+	}
+}
+
+// IsEmpty checks if the policy does not restrict any method or skill
+func (p *AuthorizationPolicy) IsEmpty() bool {
+	return p == nil || (len(p.Methods) == 0 && len(p.Skills) == 0)
+}
+
+// AuthorizeMethod checks that the principal may call the JSON-RPC method
+func (p *AuthorizationPolicy) AuthorizeMethod(principal *Principal, method string) error {
+	if p == nil {
+		return nil
+	}
+
+	requirement, exists := p.Methods[method]
+	if !exists || requirement.SatisfiedBy(principal) {
+		return nil
+	}
+
+	return &AuthorizationError{
+		Method:      method,
+		Requirement: requirement,
+	}
+}
+
+// AuthorizeSkill checks that the principal may invoke the agent skill
+func (p *AuthorizationPolicy) AuthorizeSkill(principal *Principal, method string, skillID string) error {
+	if p == nil || skillID == "" {
+		return nil
+	}
+
+	requirement, exists := p.Skills[skillID]
+	if !exists || requirement.SatisfiedBy(principal) {
+		return nil
+	}
+
+	return &AuthorizationError{
+		Method:      method,
+		SkillID:     skillID,
+		Requirement: requirement,
+	}
+}
+
+// AuthorizationError represents a request denied by the authorization policy.
+// It is serialized as the data of the JSON-RPC error returned to the caller.
+type AuthorizationError struct {
+	Method      string      `json:"method"`
+	SkillID     string      `json:"skillId,omitempty"`
+	Requirement Requirement `json:"required"`
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.SkillID != "" {
+		return fmt.Sprintf("permission denied for skill %s", e.SkillID)
+	}
+	return fmt.Sprintf("permission denied for method %s", e.Method)
+}
+
+// buildRequirements merges the space separated scope and role lists keyed by method or skill ID
+func buildRequirements(scopes map[string]string, roles map[string]string) map[string]Requirement {
+	requirements := make(map[string]Requirement)
+
+	for key, value := range scopes {
+		requirement := requirements[key]
+		requirement.Scopes = strings.Fields(value)
+		requirements[key] = requirement
+	}
+
+	for key, value := range roles {
+		requirement := requirements[key]
+		requirement.Roles = strings.Fields(value)
+		requirements[key] = requirement
+	}
+
+	for key, requirement := range requirements {
+		if requirement.IsEmpty() {
+			delete(requirements, key)
+		}
+	}
+
+	return requirements
+}
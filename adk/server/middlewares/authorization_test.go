@@ -0,0 +1,112 @@
+package middlewares_test
+
+import (
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationPolicy_AuthorizeMethod(t *testing.T) {
+	policy := middlewares.NewAuthorizationPolicy(config.AuthConfig{
+		MethodScopes: map[string]string{
+			"message/send": "a2a:write",
+			"tasks/get":    "a2a:read",
+		},
+		MethodRoles: map[string]string{
+			"tasks/cancel": "operator supervisor",
+		},
+	})
+
+	tests := []struct {
+		name        string
+		principal   *middlewares.Principal
+		method      string
+		expectError bool
+	}{
+		{
+			name:      "unrestricted method",
+			principal: &middlewares.Principal{Subject: "alice"},
+			method:    "tasks/list",
+		},
+		{
+			name:      "required scope granted",
+			principal: &middlewares.Principal{Subject: "alice", Scopes: []string{"a2a:read", "a2a:write"}},
+			method:    "message/send",
+		},
+		{
+			name:        "required scope missing",
+			principal:   &middlewares.Principal{Subject: "alice", Scopes: []string{"a2a:read"}},
+			method:      "message/send",
+			expectError: true,
+		},
+		{
+			name:      "one of the roles is sufficient",
+			principal: &middlewares.Principal{Subject: "alice", Roles: []string{"supervisor"}},
+			method:    "tasks/cancel",
+		},
+		{
+			name:        "required role missing",
+			principal:   &middlewares.Principal{Subject: "alice", Roles: []string{"viewer"}},
+			method:      "tasks/cancel",
+			expectError: true,
+		},
+		{
+			name:      "admin satisfies every requirement",
+			principal: &middlewares.Principal{Subject: "root", Admin: true},
+			method:    "tasks/cancel",
+		},
+		{
+			name:      "unauthenticated requests are not restricted",
+			principal: nil,
+			method:    "message/send",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.AuthorizeMethod(tt.principal, tt.method)
+			if !tt.expectError {
+				assert.NoError(t, err)
+				return
+			}
+
+			var authErr *middlewares.AuthorizationError
+			assert.ErrorAs(t, err, &authErr)
+			assert.Equal(t, tt.method, authErr.Method)
+		})
+	}
+}
+
+func TestAuthorizationPolicy_AuthorizeSkill(t *testing.T) {
+	policy := middlewares.NewAuthorizationPolicy(config.AuthConfig{
+		SkillScopes: map[string]string{
+			"billing": "billing:read",
+		},
+	})
+
+	principal := &middlewares.Principal{Subject: "alice"}
+
+	assert.NoError(t, policy.AuthorizeSkill(principal, "message/send", ""))
+	assert.NoError(t, policy.AuthorizeSkill(principal, "message/send", "weather"))
+
+	err := policy.AuthorizeSkill(principal, "message/send", "billing")
+	var authErr *middlewares.AuthorizationError
+	assert.ErrorAs(t, err, &authErr)
+	assert.Equal(t, "billing", authErr.SkillID)
+	assert.Equal(t, []string{"billing:read"}, authErr.Requirement.Scopes)
+
+	principal.Scopes = []string{"billing:read"}
+	assert.NoError(t, policy.AuthorizeSkill(principal, "message/send", "billing"))
+}
+
+func TestNewAuthorizationPolicy_Empty(t *testing.T) {
+	policy := middlewares.NewAuthorizationPolicy(config.AuthConfig{
+		MethodScopes: map[string]string{"message/send": " "},
+	})
+	assert.True(t, policy.IsEmpty())
+
+	var nilPolicy *middlewares.AuthorizationPolicy
+	assert.NoError(t, nilPolicy.AuthorizeMethod(&middlewares.Principal{}, "message/send"))
+}
@@ -0,0 +1,167 @@
+package middlewares
+
+import (
+	"context"
+	"strings"
+
+	config "github.com/inference-gateway/a2a/adk/server/config"
+)
+
+const (
+	PrincipalContextKey contextKey = "principal"
+)
+
+// Principal represents the authenticated caller of a request
+// The issuer is the token issuer or, for credentials without one, the authentication scheme
+type Principal struct {
+	Subject string                 `json:"subject"`
+	Issuer  string                 `json:"issuer,omitempty"`
+	Tenant  string                 `json:"tenant,omitempty"`
+	Roles   []string               `json:"roles,omitempty"`
+	Scopes  []string               `json:"scopes,omitempty"`
+	Admin   bool                   `json:"admin"`
+	Claims  map[string]interface{} `json:"-"`
+}
+
+// Owner returns the identifier tasks and contexts are owned by, qualified by the issuer.
+// The tenant takes precedence so that all subjects of a tenant share their resources.
+func (p *Principal) Owner() string {
+	if p == nil {
+		return ""
+	}
+	if p.Tenant != "" {
+		return qualify(p.Issuer, p.Tenant)
+	}
+	return p.QualifiedSubject()
+}
+
+// QualifiedSubject returns the subject qualified by the issuer, such as "basic|alice",
+// so that equal subjects verified by different schemes or issuers are distinct principals
+func (p *Principal) QualifiedSubject() string {
+	if p == nil || p.Subject == "" {
+		return ""
+	}
+	return qualify(p.Issuer, p.Subject)
+}
+
+// HasRole checks if the principal holds the given role
+func (p *Principal) HasRole(role string) bool {
+	if p == nil || role == "" {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope checks if the principal was granted the given scope
+func (p *Principal) HasScope(scope string) bool {
+	if p == nil || scope == "" {
+		return false
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess checks if the principal may access a resource owned by owner.
+// Resources without an owner were created while authentication was disabled and are accessible to everyone.
+func (p *Principal) CanAccess(owner string) bool {
+	if p == nil || p.Admin || owner == "" {
+		return true
+	}
+	return p.Owner() == owner
+}
+
+// NewPrincipalFromClaims builds a principal from verified token claims using the configured claim names
+// The principal is issued by the iss claim of the token, or by the scheme for tokens without one
+func NewPrincipalFromClaims(scheme string, subject string, claims map[string]interface{}, cfg config.AuthConfig) *Principal {
+	principal := &Principal{
+		Subject: subject,
+		Issuer:  scheme,
+		Claims:  claims,
+	}
+
+	if issuer, ok := claims["iss"].(string); ok && issuer != "" {
+		principal.Issuer = issuer
+	}
+
+	if cfg.TenantClaim != "" {
+		if tenant, ok := lookupClaim(claims, cfg.TenantClaim).(string); ok {
+			principal.Tenant = tenant
+		}
+	}
+
+	if cfg.RolesClaim != "" {
+		principal.Roles = claimStrings(lookupClaim(claims, cfg.RolesClaim))
+	}
+
+	if cfg.ScopesClaim != "" {
+		principal.Scopes = claimStrings(lookupClaim(claims, cfg.ScopesClaim))
+	}
+
+	principal.Admin = principal.HasRole(cfg.AdminRole)
+
+	return principal
+}
+
+// qualify prefixes the identifier with the issuer
+func qualify(issuer string, id string) string {
+	if issuer == "" {
+		return id
+	}
+	return issuer + "|" + id
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, PrincipalContextKey, principal)
+}
+
+// PrincipalFromContext retrieves the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(PrincipalContextKey).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// lookupClaim resolves a dotted claim path such as "realm_access.roles"
+func lookupClaim(claims map[string]interface{}, path string) interface{} {
+	var current interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// claimStrings converts a claim holding a string list or a space separated string into a slice
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package middlewares_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrincipalFromClaims(t *testing.T) {
+	authConfig := config.AuthConfig{
+		TenantClaim: "org",
+		RolesClaim:  "realm_access.roles",
+		AdminRole:   "a2a-admin",
+	}
+
+	tests := []struct {
+		name          string
+		claims        map[string]interface{}
+		expectedOwner string
+		expectedRoles []string
+		expectedAdmin bool
+	}{
+		{
+			name:          "subject only",
+			claims:        map[string]interface{}{},
+			expectedOwner: "jwt|user-1",
+		},
+		{
+			name:          "subject qualified by the issuer",
+			claims:        map[string]interface{}{"iss": "https://idp.example.com"},
+			expectedOwner: "https://idp.example.com|user-1",
+		},
+		{
+			name:          "tenant takes precedence over subject",
+			claims:        map[string]interface{}{"iss": "https://idp.example.com", "org": "acme"},
+			expectedOwner: "https://idp.example.com|acme",
+		},
+		{
+			name: "nested roles claim",
+			claims: map[string]interface{}{
+				"realm_access": map[string]interface{}{
+					"roles": []interface{}{"reader", "writer"},
+				},
+			},
+			expectedOwner: "jwt|user-1",
+			expectedRoles: []string{"reader", "writer"},
+		},
+		{
+			name: "admin role",
+			claims: map[string]interface{}{
+				"realm_access": map[string]interface{}{
+					"roles": []interface{}{"a2a-admin"},
+				},
+			},
+			expectedOwner: "jwt|user-1",
+			expectedRoles: []string{"a2a-admin"},
+			expectedAdmin: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := middlewares.NewPrincipalFromClaims(middlewares.AuthSchemeJWT, "user-1", tt.claims, authConfig)
+
+			assert.Equal(t, "user-1", principal.Subject)
+			assert.Equal(t, tt.expectedOwner, principal.Owner())
+			assert.Equal(t, tt.expectedRoles, principal.Roles)
+			assert.Equal(t, tt.expectedAdmin, principal.Admin)
+		})
+	}
+}
+
+func TestPrincipal_CanAccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *middlewares.Principal
+		owner     string
+		expected  bool
+	}{
+		{name: "owner", principal: &middlewares.Principal{Subject: "alice"}, owner: "alice", expected: true},
+		{name: "other owner", principal: &middlewares.Principal{Subject: "alice"}, owner: "bob", expected: false},
+		{name: "unowned resource", principal: &middlewares.Principal{Subject: "alice"}, owner: "", expected: true},
+		{name: "same subject of another issuer", principal: &middlewares.Principal{Subject: "alice", Issuer: "basic"}, owner: "https://idp.example.com|alice", expected: false},
+		{name: "admin", principal: &middlewares.Principal{Subject: "root", Admin: true}, owner: "bob", expected: true},
+		{name: "nil principal", principal: nil, owner: "bob", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.principal.CanAccess(tt.owner))
+		})
+	}
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	_, ok := middlewares.PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &middlewares.Principal{Subject: "alice"}
+	ctx := middlewares.WithPrincipal(context.Background(), principal)
+
+	got, ok := middlewares.PrincipalFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, principal, got)
+}
+
+func TestNewPrincipalFromClaims_Scopes(t *testing.T) {
+	authConfig := config.AuthConfig{ScopesClaim: "scope"}
+
+	principal := middlewares.NewPrincipalFromClaims(middlewares.AuthSchemeJWT, "user-1", map[string]interface{}{
+		"scope": "openid a2a:read a2a:write",
+	}, authConfig)
+
+	assert.Equal(t, []string{"openid", "a2a:read", "a2a:write"}, principal.Scopes)
+	assert.True(t, principal.HasScope("a2a:write"))
+	assert.False(t, principal.HasScope("a2a:admin"))
+}
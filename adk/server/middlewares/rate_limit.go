@@ -0,0 +1,311 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	config "github.com/inference-gateway/a2a/adk/server/config"
+	"go.uber.org/zap"
+)
+
+const (
+	RateLimitKeyContextKey contextKey = "rateLimitKey"
+)
+
+// Supported rate limit key sources
+const (
+	RateLimitKeyByPrincipal = "principal"
+	RateLimitKeyByAPIKey    = "apikey"
+	RateLimitKeyByIP        = "ip"
+)
+
+// TokenBucket is a token bucket refilled at a constant rate up to its capacity
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket creates a full token bucket refilled with perMinute tokens per minute
+func NewTokenBucket(capacity int, perMinute int, now time.Time) *TokenBucket {
+	return &TokenBucket{
+		capacity: float64(capacity),
+		rate:     float64(perMinute) / 60,
+		tokens:   float64(capacity),
+		last:     now,
+	}
+}
+
+// refill adds the tokens accumulated since the last update, the caller must hold the lock
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// wait returns how long it takes until the bucket holds n tokens, the caller must hold the lock
+func (b *TokenBucket) wait(n float64) time.Duration {
+	if b.rate <= 0 {
+		return time.Minute
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Take removes n tokens if available, otherwise it returns how long to wait for them
+func (b *TokenBucket) Take(n float64, now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens >= n {
+		b.tokens -= n
+		return true, 0
+	}
+	return false, b.wait(n)
+}
+
+// Check reports whether any token is left without taking one, otherwise it returns how long to wait for one
+func (b *TokenBucket) Check(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens > 0 {
+		return true, 0
+	}
+	return false, b.wait(math.Nextafter(0, 1))
+}
+
+// Consume removes n tokens unconditionally, the bucket may go into debt for usage known only afterwards
+func (b *TokenBucket) Consume(n float64, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens -= n
+}
+
+// RateLimitExceededError represents a request rejected because a client exhausted one of its limits
+type RateLimitExceededError struct {
+	Limit      string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded, retry after %s", e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// RetryAfterSeconds returns the Retry-After value, rounded up to whole seconds
+func (e *RateLimitExceededError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// rateLimitClient holds the buckets of a single client
+type rateLimitClient struct {
+	requests *TokenBucket
+	tokens   *TokenBucket
+	lastSeen time.Time
+}
+
+// RateLimiter limits requests and LLM token consumption per client using token buckets
+type RateLimiter struct {
+	logger      *zap.Logger
+	cfg         config.RateLimitConfig
+	apiKeyName  string
+	apiKeyQuery bool
+	now         func() time.Time
+
+	mu          sync.Mutex
+	clients     map[string]*rateLimitClient
+	lastCleanup time.Time
+}
+
+// NewRateLimiter creates a rate limiter from the rate limit configuration
+// The API key settings of the auth configuration are used when limiting per API key
+func NewRateLimiter(logger *zap.Logger, cfg config.Config) (*RateLimiter, error) {
+	rateLimitConfig := cfg.RateLimitConfig
+
+	switch rateLimitConfig.KeyBy {
+	case RateLimitKeyByPrincipal, RateLimitKeyByAPIKey, RateLimitKeyByIP:
+	case "":
+		rateLimitConfig.KeyBy = RateLimitKeyByPrincipal
+	default:
+		return nil, fmt.Errorf("unsupported rate limit key %q, expected principal, apikey or ip", rateLimitConfig.KeyBy)
+	}
+
+	if rateLimitConfig.RequestsPerMinute < 0 || rateLimitConfig.TokensPerMinute < 0 {
+		return nil, fmt.Errorf("rate limits must not be negative")
+	}
+
+	if rateLimitConfig.RequestBurst <= 0 {
+		rateLimitConfig.RequestBurst = max(rateLimitConfig.RequestsPerMinute, 1)
+	}
+
+	if rateLimitConfig.TokenBurst <= 0 {
+		rateLimitConfig.TokenBurst = rateLimitConfig.TokensPerMinute
+	}
+
+	apiKeyName := cfg.AuthConfig.APIKeyName
+	if apiKeyName == "" {
+		apiKeyName = "X-API-Key"
+	}
+
+	return &RateLimiter{
+		logger:      logger,
+		cfg:         rateLimitConfig,
+		apiKeyName:  apiKeyName,
+		apiKeyQuery: cfg.AuthConfig.APIKeyIn == "query",
+		now:         time.Now,
+		clients:     make(map[string]*rateLimitClient),
+		lastCleanup: time.Now(),
+	}, nil
+}
+
+// LimitsTokens checks if LLM token consumption is limited
+func (rl *RateLimiter) LimitsTokens() bool {
+	return rl != nil && rl.cfg.TokensPerMinute > 0
+}
+
+// Key returns the rate limit key identifying the client of the request
+func (rl *RateLimiter) Key(c *gin.Context) string {
+	switch rl.cfg.KeyBy {
+	case RateLimitKeyByPrincipal:
+		if principal, ok := PrincipalFromContext(c.Request.Context()); ok && principal.Subject != "" {
+			return "principal:" + principal.QualifiedSubject()
+		}
+	case RateLimitKeyByAPIKey:
+		var apiKey string
+		if rl.apiKeyQuery {
+			apiKey = c.Query(rl.apiKeyName)
+		} else {
+			apiKey = c.GetHeader(rl.apiKeyName)
+		}
+		if apiKey != "" {
+			sum := sha256.Sum256([]byte(apiKey))
+			return "apikey:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// client returns the buckets of the client, creating them on first use
+func (rl *RateLimiter) client(key string) *rateLimitClient {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if rl.cfg.IdleTimeout > 0 && now.Sub(rl.lastCleanup) > rl.cfg.IdleTimeout {
+		for k, client := range rl.clients {
+			if now.Sub(client.lastSeen) > rl.cfg.IdleTimeout {
+				delete(rl.clients, k)
+			}
+		}
+		rl.lastCleanup = now
+	}
+
+	client, exists := rl.clients[key]
+	if !exists {
+		client = &rateLimitClient{}
+		if rl.cfg.RequestsPerMinute > 0 {
+			client.requests = NewTokenBucket(rl.cfg.RequestBurst, rl.cfg.RequestsPerMinute, now)
+		}
+		if rl.cfg.TokensPerMinute > 0 {
+			client.tokens = NewTokenBucket(rl.cfg.TokenBurst, rl.cfg.TokensPerMinute, now)
+		}
+		rl.clients[key] = client
+	}
+	client.lastSeen = now
+
+	return client
+}
+
+// AllowRequest takes one request from the bucket of the client
+func (rl *RateLimiter) AllowRequest(key string) error {
+	client := rl.client(key)
+	if client.requests == nil {
+		return nil
+	}
+
+	if ok, wait := client.requests.Take(1, rl.now()); !ok {
+		return &RateLimitExceededError{Limit: "request", RetryAfter: wait}
+	}
+	return nil
+}
+
+// AllowTokens checks that the client has LLM tokens left
+func (rl *RateLimiter) AllowTokens(key string) error {
+	if !rl.LimitsTokens() {
+		return nil
+	}
+
+	client := rl.client(key)
+	if ok, wait := client.tokens.Check(rl.now()); !ok {
+		return &RateLimitExceededError{Limit: "token", RetryAfter: wait}
+	}
+	return nil
+}
+
+// ConsumeTokens charges the LLM tokens used on behalf of the client
+func (rl *RateLimiter) ConsumeTokens(key string, tokens int64) {
+	if !rl.LimitsTokens() || tokens <= 0 {
+		return
+	}
+
+	rl.client(key).tokens.Consume(float64(tokens), rl.now())
+}
+
+// Middleware returns the rate limiting middleware, it must run after authentication to key by principal
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := rl.Key(c)
+
+		err := rl.AllowRequest(key)
+		if err == nil {
+			err = rl.AllowTokens(key)
+		}
+		var rateLimitErr *RateLimitExceededError
+		if errors.As(err, &rateLimitErr) {
+			rl.logger.Warn("rate limit exceeded",
+				zap.String("key", key),
+				zap.String("limit", rateLimitErr.Limit),
+				zap.Duration("retry_after", rateLimitErr.RetryAfter))
+			c.Header("Retry-After", strconv.Itoa(rateLimitErr.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       rateLimitErr.Error(),
+				"retry_after": rateLimitErr.RetryAfterSeconds(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(string(RateLimitKeyContextKey), key)
+		c.Request = c.Request.WithContext(WithRateLimitKey(c.Request.Context(), key))
+		c.Next()
+	}
+}
+
+// WithRateLimitKey returns a copy of ctx carrying the rate limit key of the client
+func WithRateLimitKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, RateLimitKeyContextKey, key)
+}
+
+// RateLimitKeyFromContext retrieves the rate limit key stored in ctx, if any
+func RateLimitKeyFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	key, ok := ctx.Value(RateLimitKeyContextKey).(string)
+	return key, ok && key != ""
+}
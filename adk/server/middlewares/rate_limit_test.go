@@ -0,0 +1,165 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Now()
+	bucket := middlewares.NewTokenBucket(2, 60, start)
+
+	ok, _ := bucket.Take(1, start)
+	assert.True(t, ok)
+	ok, _ = bucket.Take(1, start)
+	assert.True(t, ok)
+
+	ok, wait := bucket.Take(1, start)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	ok, _ = bucket.Take(1, start.Add(time.Second))
+	assert.True(t, ok, "one token is refilled per second")
+
+	bucket.Consume(5, start.Add(time.Second))
+	ok, wait = bucket.Check(start.Add(time.Second))
+	assert.False(t, ok, "consumed tokens put the bucket into debt")
+	assert.InDelta(t, 5*time.Second, wait, float64(time.Millisecond))
+
+	ok, _ = bucket.Check(start.Add(7 * time.Second))
+	assert.True(t, ok)
+}
+
+func newRateLimitRouter(t *testing.T, limiter *middlewares.RateLimiter, principal *middlewares.Principal) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/a2a", func(c *gin.Context) {
+		if principal != nil {
+			c.Request = c.Request.WithContext(middlewares.WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	}, limiter.Middleware(), func(c *gin.Context) {
+		key, ok := middlewares.RateLimitKeyFromContext(c.Request.Context())
+		require.True(t, ok)
+		c.String(http.StatusOK, key)
+	})
+	return router
+}
+
+func TestRateLimiter_Middleware_RequestLimit(t *testing.T) {
+	limiter, err := middlewares.NewRateLimiter(zap.NewNop(), config.Config{
+		RateLimitConfig: config.RateLimitConfig{
+			Enable:            true,
+			KeyBy:             "principal",
+			RequestsPerMinute: 1,
+			RequestBurst:      2,
+		},
+	})
+	require.NoError(t, err)
+
+	alice := newRateLimitRouter(t, limiter, &middlewares.Principal{Subject: "alice"})
+	bob := newRateLimitRouter(t, limiter, &middlewares.Principal{Subject: "bob"})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		alice.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "principal:alice", w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	alice.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "request rate limit exceeded")
+
+	w = httptest.NewRecorder()
+	bob.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "limits are tracked per principal")
+}
+
+func TestRateLimiter_Middleware_TokenLimit(t *testing.T) {
+	limiter, err := middlewares.NewRateLimiter(zap.NewNop(), config.Config{
+		RateLimitConfig: config.RateLimitConfig{
+			Enable:          true,
+			KeyBy:           "ip",
+			TokensPerMinute: 600,
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, limiter.LimitsTokens())
+
+	router := newRateLimitRouter(t, limiter, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	key := w.Body.String()
+	assert.Contains(t, key, "ip:")
+
+	limiter.ConsumeTokens(key, 1200)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "token rate limit exceeded")
+}
+
+func TestRateLimiter_Key(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyBy     string
+		principal *middlewares.Principal
+		apiKey    string
+		prefix    string
+	}{
+		{name: "principal", keyBy: "principal", principal: &middlewares.Principal{Subject: "alice"}, prefix: "principal:alice"},
+		{name: "principal qualified by issuer", keyBy: "principal", principal: &middlewares.Principal{Subject: "alice", Issuer: "basic"}, prefix: "principal:basic|alice"},
+		{name: "principal falls back to ip", keyBy: "principal", prefix: "ip:"},
+		{name: "api key", keyBy: "apikey", apiKey: "secret", prefix: "apikey:"},
+		{name: "api key falls back to ip", keyBy: "apikey", prefix: "ip:"},
+		{name: "ip", keyBy: "ip", principal: &middlewares.Principal{Subject: "alice"}, prefix: "ip:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := middlewares.NewRateLimiter(zap.NewNop(), config.Config{
+				RateLimitConfig: config.RateLimitConfig{Enable: true, KeyBy: tt.keyBy, RequestsPerMinute: 100},
+			})
+			require.NoError(t, err)
+
+			router := newRateLimitRouter(t, limiter, tt.principal)
+			req := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), tt.prefix)
+			if tt.apiKey != "" {
+				assert.NotContains(t, w.Body.String(), tt.apiKey, "api keys are not used verbatim")
+			}
+		})
+	}
+}
+
+func TestNewRateLimiter_InvalidKeyBy(t *testing.T) {
+	_, err := middlewares.NewRateLimiter(zap.NewNop(), config.Config{
+		RateLimitConfig: config.RateLimitConfig{Enable: true, KeyBy: "cookie"},
+	})
+	assert.Error(t, err)
+}
@@ -75,6 +75,17 @@ type FakeAgentBuilder struct {
 	withMaxConversationHistoryReturnsOnCall map[int]struct {
 		result1 server.AgentBuilder
 	}
+	WithMaxParallelToolCallsStub        func(int) server.AgentBuilder
+	withMaxParallelToolCallsMutex       sync.RWMutex
+	withMaxParallelToolCallsArgsForCall []struct {
+		arg1 int
+	}
+	withMaxParallelToolCallsReturns struct {
+		result1 server.AgentBuilder
+	}
+	withMaxParallelToolCallsReturnsOnCall map[int]struct {
+		result1 server.AgentBuilder
+	}
 	WithSystemPromptStub        func(string) server.AgentBuilder
 	withSystemPromptMutex       sync.RWMutex
 	withSystemPromptArgsForCall []struct {
@@ -454,6 +465,67 @@ func (fake *FakeAgentBuilder) WithMaxConversationHistoryReturnsOnCall(i int, res
 	}{result1}
 }
 
+func (fake *FakeAgentBuilder) WithMaxParallelToolCalls(arg1 int) server.AgentBuilder {
+	fake.withMaxParallelToolCallsMutex.Lock()
+	ret, specificReturn := fake.withMaxParallelToolCallsReturnsOnCall[len(fake.withMaxParallelToolCallsArgsForCall)]
+	fake.withMaxParallelToolCallsArgsForCall = append(fake.withMaxParallelToolCallsArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.WithMaxParallelToolCallsStub
+	fakeReturns := fake.withMaxParallelToolCallsReturns
+	fake.recordInvocation("WithMaxParallelToolCalls", []interface{}{arg1})
+	fake.withMaxParallelToolCallsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAgentBuilder) WithMaxParallelToolCallsCallCount() int {
+	fake.withMaxParallelToolCallsMutex.RLock()
+	defer fake.withMaxParallelToolCallsMutex.RUnlock()
+	return len(fake.withMaxParallelToolCallsArgsForCall)
+}
+
+func (fake *FakeAgentBuilder) WithMaxParallelToolCallsCalls(stub func(int) server.AgentBuilder) {
+	fake.withMaxParallelToolCallsMutex.Lock()
+	defer fake.withMaxParallelToolCallsMutex.Unlock()
+	fake.WithMaxParallelToolCallsStub = stub
+}
+
+func (fake *FakeAgentBuilder) WithMaxParallelToolCallsArgsForCall(i int) int {
+	fake.withMaxParallelToolCallsMutex.RLock()
+	defer fake.withMaxParallelToolCallsMutex.RUnlock()
+	argsForCall := fake.withMaxParallelToolCallsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAgentBuilder) WithMaxParallelToolCallsReturns(result1 server.AgentBuilder) {
+	fake.withMaxParallelToolCallsMutex.Lock()
+	defer fake.withMaxParallelToolCallsMutex.Unlock()
+	fake.WithMaxParallelToolCallsStub = nil
+	fake.withMaxParallelToolCallsReturns = struct {
+		result1 server.AgentBuilder
+	}{result1}
+}
+
+func (fake *FakeAgentBuilder) WithMaxParallelToolCallsReturnsOnCall(i int, result1 server.AgentBuilder) {
+	fake.withMaxParallelToolCallsMutex.Lock()
+	defer fake.withMaxParallelToolCallsMutex.Unlock()
+	fake.WithMaxParallelToolCallsStub = nil
+	if fake.withMaxParallelToolCallsReturnsOnCall == nil {
+		fake.withMaxParallelToolCallsReturnsOnCall = make(map[int]struct {
+			result1 server.AgentBuilder
+		})
+	}
+	fake.withMaxParallelToolCallsReturnsOnCall[i] = struct {
+		result1 server.AgentBuilder
+	}{result1}
+}
+
 func (fake *FakeAgentBuilder) WithSystemPrompt(arg1 string) server.AgentBuilder {
 	fake.withSystemPromptMutex.Lock()
 	ret, specificReturn := fake.withSystemPromptReturnsOnCall[len(fake.withSystemPromptArgsForCall)]
@@ -591,6 +663,8 @@ func (fake *FakeAgentBuilder) Invocations() map[string][][]interface{} {
 	defer fake.withMaxChatCompletionMutex.RUnlock()
 	fake.withMaxConversationHistoryMutex.RLock()
 	defer fake.withMaxConversationHistoryMutex.RUnlock()
+	fake.withMaxParallelToolCallsMutex.RLock()
+	defer fake.withMaxParallelToolCallsMutex.RUnlock()
 	fake.withSystemPromptMutex.RLock()
 	defer fake.withSystemPromptMutex.RUnlock()
 	fake.withToolBoxMutex.RLock()
@@ -10,6 +10,14 @@ import (
 )
 
 type FakeOpenTelemetry struct {
+	RecordLLMRequestAttemptsStub        func(context.Context, otel.TelemetryAttributes, int, bool)
+	recordLLMRequestAttemptsMutex       sync.RWMutex
+	recordLLMRequestAttemptsArgsForCall []struct {
+		arg1 context.Context
+		arg2 otel.TelemetryAttributes
+		arg3 int
+		arg4 bool
+	}
 	RecordRequestCountStub        func(context.Context, otel.TelemetryAttributes, string)
 	recordRequestCountMutex       sync.RWMutex
 	recordRequestCountArgsForCall []struct {
@@ -71,6 +79,14 @@ type FakeOpenTelemetry struct {
 		arg3 string
 		arg4 string
 	}
+	RecordToolCallSafeguardStub        func(context.Context, otel.TelemetryAttributes, string, string)
+	recordToolCallSafeguardMutex       sync.RWMutex
+	recordToolCallSafeguardArgsForCall []struct {
+		arg1 context.Context
+		arg2 otel.TelemetryAttributes
+		arg3 string
+		arg4 string
+	}
 	ShutDownStub        func(context.Context) error
 	shutDownMutex       sync.RWMutex
 	shutDownArgsForCall []struct {
@@ -86,6 +102,41 @@ type FakeOpenTelemetry struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeOpenTelemetry) RecordLLMRequestAttempts(arg1 context.Context, arg2 otel.TelemetryAttributes, arg3 int, arg4 bool) {
+	fake.recordLLMRequestAttemptsMutex.Lock()
+	fake.recordLLMRequestAttemptsArgsForCall = append(fake.recordLLMRequestAttemptsArgsForCall, struct {
+		arg1 context.Context
+		arg2 otel.TelemetryAttributes
+		arg3 int
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecordLLMRequestAttemptsStub
+	fake.recordInvocation("RecordLLMRequestAttempts", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordLLMRequestAttemptsMutex.Unlock()
+	if stub != nil {
+		fake.RecordLLMRequestAttemptsStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeOpenTelemetry) RecordLLMRequestAttemptsCallCount() int {
+	fake.recordLLMRequestAttemptsMutex.RLock()
+	defer fake.recordLLMRequestAttemptsMutex.RUnlock()
+	return len(fake.recordLLMRequestAttemptsArgsForCall)
+}
+
+func (fake *FakeOpenTelemetry) RecordLLMRequestAttemptsCalls(stub func(context.Context, otel.TelemetryAttributes, int, bool)) {
+	fake.recordLLMRequestAttemptsMutex.Lock()
+	defer fake.recordLLMRequestAttemptsMutex.Unlock()
+	fake.RecordLLMRequestAttemptsStub = stub
+}
+
+func (fake *FakeOpenTelemetry) RecordLLMRequestAttemptsArgsForCall(i int) (context.Context, otel.TelemetryAttributes, int, bool) {
+	fake.recordLLMRequestAttemptsMutex.RLock()
+	defer fake.recordLLMRequestAttemptsMutex.RUnlock()
+	argsForCall := fake.recordLLMRequestAttemptsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeOpenTelemetry) RecordRequestCount(arg1 context.Context, arg2 otel.TelemetryAttributes, arg3 string) {
 	fake.recordRequestCountMutex.Lock()
 	fake.recordRequestCountArgsForCall = append(fake.recordRequestCountArgsForCall, struct {
@@ -363,6 +414,41 @@ func (fake *FakeOpenTelemetry) RecordToolCallFailureArgsForCall(i int) (context.
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
+func (fake *FakeOpenTelemetry) RecordToolCallSafeguard(arg1 context.Context, arg2 otel.TelemetryAttributes, arg3 string, arg4 string) {
+	fake.recordToolCallSafeguardMutex.Lock()
+	fake.recordToolCallSafeguardArgsForCall = append(fake.recordToolCallSafeguardArgsForCall, struct {
+		arg1 context.Context
+		arg2 otel.TelemetryAttributes
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RecordToolCallSafeguardStub
+	fake.recordInvocation("RecordToolCallSafeguard", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordToolCallSafeguardMutex.Unlock()
+	if stub != nil {
+		fake.RecordToolCallSafeguardStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeOpenTelemetry) RecordToolCallSafeguardCallCount() int {
+	fake.recordToolCallSafeguardMutex.RLock()
+	defer fake.recordToolCallSafeguardMutex.RUnlock()
+	return len(fake.recordToolCallSafeguardArgsForCall)
+}
+
+func (fake *FakeOpenTelemetry) RecordToolCallSafeguardCalls(stub func(context.Context, otel.TelemetryAttributes, string, string)) {
+	fake.recordToolCallSafeguardMutex.Lock()
+	defer fake.recordToolCallSafeguardMutex.Unlock()
+	fake.RecordToolCallSafeguardStub = stub
+}
+
+func (fake *FakeOpenTelemetry) RecordToolCallSafeguardArgsForCall(i int) (context.Context, otel.TelemetryAttributes, string, string) {
+	fake.recordToolCallSafeguardMutex.RLock()
+	defer fake.recordToolCallSafeguardMutex.RUnlock()
+	argsForCall := fake.recordToolCallSafeguardArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
 func (fake *FakeOpenTelemetry) ShutDown(arg1 context.Context) error {
 	fake.shutDownMutex.Lock()
 	ret, specificReturn := fake.shutDownReturnsOnCall[len(fake.shutDownArgsForCall)]
@@ -427,6 +513,8 @@ func (fake *FakeOpenTelemetry) ShutDownReturnsOnCall(i int, result1 error) {
 func (fake *FakeOpenTelemetry) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.recordLLMRequestAttemptsMutex.RLock()
+	defer fake.recordLLMRequestAttemptsMutex.RUnlock()
 	fake.recordRequestCountMutex.RLock()
 	defer fake.recordRequestCountMutex.RUnlock()
 	fake.recordRequestDurationMutex.RLock()
@@ -443,6 +531,8 @@ func (fake *FakeOpenTelemetry) Invocations() map[string][][]interface{} {
 	defer fake.recordTokenUsageMutex.RUnlock()
 	fake.recordToolCallFailureMutex.RLock()
 	defer fake.recordToolCallFailureMutex.RUnlock()
+	fake.recordToolCallSafeguardMutex.RLock()
+	defer fake.recordToolCallSafeguardMutex.RUnlock()
 	fake.shutDownMutex.RLock()
 	defer fake.shutDownMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -17,6 +17,15 @@ type FakeResponseSender struct {
 		arg3 int
 		arg4 string
 	}
+	SendErrorWithDataStub        func(*gin.Context, interface{}, int, string, interface{})
+	sendErrorWithDataMutex       sync.RWMutex
+	sendErrorWithDataArgsForCall []struct {
+		arg1 *gin.Context
+		arg2 interface{}
+		arg3 int
+		arg4 string
+		arg5 interface{}
+	}
 	SendSuccessStub        func(*gin.Context, interface{}, interface{})
 	sendSuccessMutex       sync.RWMutex
 	sendSuccessArgsForCall []struct {
@@ -63,6 +72,42 @@ func (fake *FakeResponseSender) SendErrorArgsForCall(i int) (*gin.Context, inter
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
+func (fake *FakeResponseSender) SendErrorWithData(arg1 *gin.Context, arg2 interface{}, arg3 int, arg4 string, arg5 interface{}) {
+	fake.sendErrorWithDataMutex.Lock()
+	fake.sendErrorWithDataArgsForCall = append(fake.sendErrorWithDataArgsForCall, struct {
+		arg1 *gin.Context
+		arg2 interface{}
+		arg3 int
+		arg4 string
+		arg5 interface{}
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.SendErrorWithDataStub
+	fake.recordInvocation("SendErrorWithData", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.sendErrorWithDataMutex.Unlock()
+	if stub != nil {
+		fake.SendErrorWithDataStub(arg1, arg2, arg3, arg4, arg5)
+	}
+}
+
+func (fake *FakeResponseSender) SendErrorWithDataCallCount() int {
+	fake.sendErrorWithDataMutex.RLock()
+	defer fake.sendErrorWithDataMutex.RUnlock()
+	return len(fake.sendErrorWithDataArgsForCall)
+}
+
+func (fake *FakeResponseSender) SendErrorWithDataCalls(stub func(*gin.Context, interface{}, int, string, interface{})) {
+	fake.sendErrorWithDataMutex.Lock()
+	defer fake.sendErrorWithDataMutex.Unlock()
+	fake.SendErrorWithDataStub = stub
+}
+
+func (fake *FakeResponseSender) SendErrorWithDataArgsForCall(i int) (*gin.Context, interface{}, int, string, interface{}) {
+	fake.sendErrorWithDataMutex.RLock()
+	defer fake.sendErrorWithDataMutex.RUnlock()
+	argsForCall := fake.sendErrorWithDataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
 func (fake *FakeResponseSender) SendSuccess(arg1 *gin.Context, arg2 interface{}, arg3 interface{}) {
 	fake.sendSuccessMutex.Lock()
 	fake.sendSuccessArgsForCall = append(fake.sendSuccessArgsForCall, struct {
@@ -102,6 +147,8 @@ func (fake *FakeResponseSender) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.sendErrorMutex.RLock()
 	defer fake.sendErrorMutex.RUnlock()
+	fake.sendErrorWithDataMutex.RLock()
+	defer fake.sendErrorWithDataMutex.RUnlock()
 	fake.sendSuccessMutex.RLock()
 	defer fake.sendSuccessMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,207 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+)
+
+type FakeStreamingTaskHandler struct {
+	HandleStreamingTaskStub        func(context.Context, *adk.Task, *adk.Message, server.StreamEventEmitter) (*adk.Task, error)
+	handleStreamingTaskMutex       sync.RWMutex
+	handleStreamingTaskArgsForCall []struct {
+		arg1 context.Context
+		arg2 *adk.Task
+		arg3 *adk.Message
+		arg4 server.StreamEventEmitter
+	}
+	handleStreamingTaskReturns struct {
+		result1 *adk.Task
+		result2 error
+	}
+	handleStreamingTaskReturnsOnCall map[int]struct {
+		result1 *adk.Task
+		result2 error
+	}
+	HandleTaskStub        func(context.Context, *adk.Task, *adk.Message) (*adk.Task, error)
+	handleTaskMutex       sync.RWMutex
+	handleTaskArgsForCall []struct {
+		arg1 context.Context
+		arg2 *adk.Task
+		arg3 *adk.Message
+	}
+	handleTaskReturns struct {
+		result1 *adk.Task
+		result2 error
+	}
+	handleTaskReturnsOnCall map[int]struct {
+		result1 *adk.Task
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTask(arg1 context.Context, arg2 *adk.Task, arg3 *adk.Message, arg4 server.StreamEventEmitter) (*adk.Task, error) {
+	fake.handleStreamingTaskMutex.Lock()
+	ret, specificReturn := fake.handleStreamingTaskReturnsOnCall[len(fake.handleStreamingTaskArgsForCall)]
+	fake.handleStreamingTaskArgsForCall = append(fake.handleStreamingTaskArgsForCall, struct {
+		arg1 context.Context
+		arg2 *adk.Task
+		arg3 *adk.Message
+		arg4 server.StreamEventEmitter
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.HandleStreamingTaskStub
+	fakeReturns := fake.handleStreamingTaskReturns
+	fake.recordInvocation("HandleStreamingTask", []interface{}{arg1, arg2, arg3, arg4})
+	fake.handleStreamingTaskMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTaskCallCount() int {
+	fake.handleStreamingTaskMutex.RLock()
+	defer fake.handleStreamingTaskMutex.RUnlock()
+	return len(fake.handleStreamingTaskArgsForCall)
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTaskCalls(stub func(context.Context, *adk.Task, *adk.Message, server.StreamEventEmitter) (*adk.Task, error)) {
+	fake.handleStreamingTaskMutex.Lock()
+	defer fake.handleStreamingTaskMutex.Unlock()
+	fake.HandleStreamingTaskStub = stub
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTaskArgsForCall(i int) (context.Context, *adk.Task, *adk.Message, server.StreamEventEmitter) {
+	fake.handleStreamingTaskMutex.RLock()
+	defer fake.handleStreamingTaskMutex.RUnlock()
+	argsForCall := fake.handleStreamingTaskArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTaskReturns(result1 *adk.Task, result2 error) {
+	fake.handleStreamingTaskMutex.Lock()
+	defer fake.handleStreamingTaskMutex.Unlock()
+	fake.HandleStreamingTaskStub = nil
+	fake.handleStreamingTaskReturns = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamingTaskHandler) HandleStreamingTaskReturnsOnCall(i int, result1 *adk.Task, result2 error) {
+	fake.handleStreamingTaskMutex.Lock()
+	defer fake.handleStreamingTaskMutex.Unlock()
+	fake.HandleStreamingTaskStub = nil
+	if fake.handleStreamingTaskReturnsOnCall == nil {
+		fake.handleStreamingTaskReturnsOnCall = make(map[int]struct {
+			result1 *adk.Task
+			result2 error
+		})
+	}
+	fake.handleStreamingTaskReturnsOnCall[i] = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTask(arg1 context.Context, arg2 *adk.Task, arg3 *adk.Message) (*adk.Task, error) {
+	fake.handleTaskMutex.Lock()
+	ret, specificReturn := fake.handleTaskReturnsOnCall[len(fake.handleTaskArgsForCall)]
+	fake.handleTaskArgsForCall = append(fake.handleTaskArgsForCall, struct {
+		arg1 context.Context
+		arg2 *adk.Task
+		arg3 *adk.Message
+	}{arg1, arg2, arg3})
+	stub := fake.HandleTaskStub
+	fakeReturns := fake.handleTaskReturns
+	fake.recordInvocation("HandleTask", []interface{}{arg1, arg2, arg3})
+	fake.handleTaskMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTaskCallCount() int {
+	fake.handleTaskMutex.RLock()
+	defer fake.handleTaskMutex.RUnlock()
+	return len(fake.handleTaskArgsForCall)
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTaskCalls(stub func(context.Context, *adk.Task, *adk.Message) (*adk.Task, error)) {
+	fake.handleTaskMutex.Lock()
+	defer fake.handleTaskMutex.Unlock()
+	fake.HandleTaskStub = stub
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTaskArgsForCall(i int) (context.Context, *adk.Task, *adk.Message) {
+	fake.handleTaskMutex.RLock()
+	defer fake.handleTaskMutex.RUnlock()
+	argsForCall := fake.handleTaskArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTaskReturns(result1 *adk.Task, result2 error) {
+	fake.handleTaskMutex.Lock()
+	defer fake.handleTaskMutex.Unlock()
+	fake.HandleTaskStub = nil
+	fake.handleTaskReturns = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamingTaskHandler) HandleTaskReturnsOnCall(i int, result1 *adk.Task, result2 error) {
+	fake.handleTaskMutex.Lock()
+	defer fake.handleTaskMutex.Unlock()
+	fake.HandleTaskStub = nil
+	if fake.handleTaskReturnsOnCall == nil {
+		fake.handleTaskReturnsOnCall = make(map[int]struct {
+			result1 *adk.Task
+			result2 error
+		})
+	}
+	fake.handleTaskReturnsOnCall[i] = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStreamingTaskHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.handleStreamingTaskMutex.RLock()
+	defer fake.handleStreamingTaskMutex.RUnlock()
+	fake.handleTaskMutex.RLock()
+	defer fake.handleTaskMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStreamingTaskHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ server.StreamingTaskHandler = new(FakeStreamingTaskHandler)
@@ -7,9 +7,22 @@ import (
 
 	"github.com/inference-gateway/a2a/adk"
 	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
 )
 
 type FakeTaskManager struct {
+	AuthorizeTaskStub        func(string, *middlewares.Principal) error
+	authorizeTaskMutex       sync.RWMutex
+	authorizeTaskArgsForCall []struct {
+		arg1 string
+		arg2 *middlewares.Principal
+	}
+	authorizeTaskReturns struct {
+		result1 error
+	}
+	authorizeTaskReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CancelTaskStub        func(string) error
 	cancelTaskMutex       sync.RWMutex
 	cancelTaskArgsForCall []struct {
@@ -38,6 +51,22 @@ type FakeTaskManager struct {
 	createTaskReturnsOnCall map[int]struct {
 		result1 *adk.Task
 	}
+	CreateTaskForPrincipalStub        func(*middlewares.Principal, string, adk.TaskState, *adk.Message) (*adk.Task, error)
+	createTaskForPrincipalMutex       sync.RWMutex
+	createTaskForPrincipalArgsForCall []struct {
+		arg1 *middlewares.Principal
+		arg2 string
+		arg3 adk.TaskState
+		arg4 *adk.Message
+	}
+	createTaskForPrincipalReturns struct {
+		result1 *adk.Task
+		result2 error
+	}
+	createTaskForPrincipalReturnsOnCall map[int]struct {
+		result1 *adk.Task
+		result2 error
+	}
 	DeleteTaskPushNotificationConfigStub        func(adk.DeleteTaskPushNotificationConfigParams) error
 	deleteTaskPushNotificationConfigMutex       sync.RWMutex
 	deleteTaskPushNotificationConfigArgsForCall []struct {
@@ -112,6 +141,20 @@ type FakeTaskManager struct {
 		result1 *adk.TaskList
 		result2 error
 	}
+	ListTasksForPrincipalStub        func(adk.TaskListParams, *middlewares.Principal) (*adk.TaskList, error)
+	listTasksForPrincipalMutex       sync.RWMutex
+	listTasksForPrincipalArgsForCall []struct {
+		arg1 adk.TaskListParams
+		arg2 *middlewares.Principal
+	}
+	listTasksForPrincipalReturns struct {
+		result1 *adk.TaskList
+		result2 error
+	}
+	listTasksForPrincipalReturnsOnCall map[int]struct {
+		result1 *adk.TaskList
+		result2 error
+	}
 	PollTaskStatusStub        func(string, time.Duration, time.Duration) (*adk.Task, error)
 	pollTaskStatusMutex       sync.RWMutex
 	pollTaskStatusArgsForCall []struct {
@@ -127,6 +170,28 @@ type FakeTaskManager struct {
 		result1 *adk.Task
 		result2 error
 	}
+	ResumeTaskStub        func(string, adk.TaskState, *adk.Message) (*adk.Task, error)
+	resumeTaskMutex       sync.RWMutex
+	resumeTaskArgsForCall []struct {
+		arg1 string
+		arg2 adk.TaskState
+		arg3 *adk.Message
+	}
+	resumeTaskReturns struct {
+		result1 *adk.Task
+		result2 error
+	}
+	resumeTaskReturnsOnCall map[int]struct {
+		result1 *adk.Task
+		result2 error
+	}
+	SetTaskMetadataStub        func(*adk.Task, string, interface{})
+	setTaskMetadataMutex       sync.RWMutex
+	setTaskMetadataArgsForCall []struct {
+		arg1 *adk.Task
+		arg2 string
+		arg3 interface{}
+	}
 	SetTaskPushNotificationConfigStub        func(adk.TaskPushNotificationConfig) (*adk.TaskPushNotificationConfig, error)
 	setTaskPushNotificationConfigMutex       sync.RWMutex
 	setTaskPushNotificationConfigArgsForCall []struct {
@@ -140,6 +205,17 @@ type FakeTaskManager struct {
 		result1 *adk.TaskPushNotificationConfig
 		result2 error
 	}
+	TaskOwnerStub        func(string) string
+	taskOwnerMutex       sync.RWMutex
+	taskOwnerArgsForCall []struct {
+		arg1 string
+	}
+	taskOwnerReturns struct {
+		result1 string
+	}
+	taskOwnerReturnsOnCall map[int]struct {
+		result1 string
+	}
 	UpdateConversationHistoryStub        func(string, []adk.Message)
 	updateConversationHistoryMutex       sync.RWMutex
 	updateConversationHistoryArgsForCall []struct {
@@ -163,6 +239,68 @@ type FakeTaskManager struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeTaskManager) AuthorizeTask(arg1 string, arg2 *middlewares.Principal) error {
+	fake.authorizeTaskMutex.Lock()
+	ret, specificReturn := fake.authorizeTaskReturnsOnCall[len(fake.authorizeTaskArgsForCall)]
+	fake.authorizeTaskArgsForCall = append(fake.authorizeTaskArgsForCall, struct {
+		arg1 string
+		arg2 *middlewares.Principal
+	}{arg1, arg2})
+	stub := fake.AuthorizeTaskStub
+	fakeReturns := fake.authorizeTaskReturns
+	fake.recordInvocation("AuthorizeTask", []interface{}{arg1, arg2})
+	fake.authorizeTaskMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskManager) AuthorizeTaskCallCount() int {
+	fake.authorizeTaskMutex.RLock()
+	defer fake.authorizeTaskMutex.RUnlock()
+	return len(fake.authorizeTaskArgsForCall)
+}
+
+func (fake *FakeTaskManager) AuthorizeTaskCalls(stub func(string, *middlewares.Principal) error) {
+	fake.authorizeTaskMutex.Lock()
+	defer fake.authorizeTaskMutex.Unlock()
+	fake.AuthorizeTaskStub = stub
+}
+
+func (fake *FakeTaskManager) AuthorizeTaskArgsForCall(i int) (string, *middlewares.Principal) {
+	fake.authorizeTaskMutex.RLock()
+	defer fake.authorizeTaskMutex.RUnlock()
+	argsForCall := fake.authorizeTaskArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskManager) AuthorizeTaskReturns(result1 error) {
+	fake.authorizeTaskMutex.Lock()
+	defer fake.authorizeTaskMutex.Unlock()
+	fake.AuthorizeTaskStub = nil
+	fake.authorizeTaskReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskManager) AuthorizeTaskReturnsOnCall(i int, result1 error) {
+	fake.authorizeTaskMutex.Lock()
+	defer fake.authorizeTaskMutex.Unlock()
+	fake.AuthorizeTaskStub = nil
+	if fake.authorizeTaskReturnsOnCall == nil {
+		fake.authorizeTaskReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.authorizeTaskReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeTaskManager) CancelTask(arg1 string) error {
 	fake.cancelTaskMutex.Lock()
 	ret, specificReturn := fake.cancelTaskReturnsOnCall[len(fake.cancelTaskArgsForCall)]
@@ -311,6 +449,73 @@ func (fake *FakeTaskManager) CreateTaskReturnsOnCall(i int, result1 *adk.Task) {
 	}{result1}
 }
 
+func (fake *FakeTaskManager) CreateTaskForPrincipal(arg1 *middlewares.Principal, arg2 string, arg3 adk.TaskState, arg4 *adk.Message) (*adk.Task, error) {
+	fake.createTaskForPrincipalMutex.Lock()
+	ret, specificReturn := fake.createTaskForPrincipalReturnsOnCall[len(fake.createTaskForPrincipalArgsForCall)]
+	fake.createTaskForPrincipalArgsForCall = append(fake.createTaskForPrincipalArgsForCall, struct {
+		arg1 *middlewares.Principal
+		arg2 string
+		arg3 adk.TaskState
+		arg4 *adk.Message
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.CreateTaskForPrincipalStub
+	fakeReturns := fake.createTaskForPrincipalReturns
+	fake.recordInvocation("CreateTaskForPrincipal", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createTaskForPrincipalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskManager) CreateTaskForPrincipalCallCount() int {
+	fake.createTaskForPrincipalMutex.RLock()
+	defer fake.createTaskForPrincipalMutex.RUnlock()
+	return len(fake.createTaskForPrincipalArgsForCall)
+}
+
+func (fake *FakeTaskManager) CreateTaskForPrincipalCalls(stub func(*middlewares.Principal, string, adk.TaskState, *adk.Message) (*adk.Task, error)) {
+	fake.createTaskForPrincipalMutex.Lock()
+	defer fake.createTaskForPrincipalMutex.Unlock()
+	fake.CreateTaskForPrincipalStub = stub
+}
+
+func (fake *FakeTaskManager) CreateTaskForPrincipalArgsForCall(i int) (*middlewares.Principal, string, adk.TaskState, *adk.Message) {
+	fake.createTaskForPrincipalMutex.RLock()
+	defer fake.createTaskForPrincipalMutex.RUnlock()
+	argsForCall := fake.createTaskForPrincipalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeTaskManager) CreateTaskForPrincipalReturns(result1 *adk.Task, result2 error) {
+	fake.createTaskForPrincipalMutex.Lock()
+	defer fake.createTaskForPrincipalMutex.Unlock()
+	fake.CreateTaskForPrincipalStub = nil
+	fake.createTaskForPrincipalReturns = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskManager) CreateTaskForPrincipalReturnsOnCall(i int, result1 *adk.Task, result2 error) {
+	fake.createTaskForPrincipalMutex.Lock()
+	defer fake.createTaskForPrincipalMutex.Unlock()
+	fake.CreateTaskForPrincipalStub = nil
+	if fake.createTaskForPrincipalReturnsOnCall == nil {
+		fake.createTaskForPrincipalReturnsOnCall = make(map[int]struct {
+			result1 *adk.Task
+			result2 error
+		})
+	}
+	fake.createTaskForPrincipalReturnsOnCall[i] = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTaskManager) DeleteTaskPushNotificationConfig(arg1 adk.DeleteTaskPushNotificationConfigParams) error {
 	fake.deleteTaskPushNotificationConfigMutex.Lock()
 	ret, specificReturn := fake.deleteTaskPushNotificationConfigReturnsOnCall[len(fake.deleteTaskPushNotificationConfigArgsForCall)]
@@ -689,6 +894,71 @@ func (fake *FakeTaskManager) ListTasksReturnsOnCall(i int, result1 *adk.TaskList
 	}{result1, result2}
 }
 
+func (fake *FakeTaskManager) ListTasksForPrincipal(arg1 adk.TaskListParams, arg2 *middlewares.Principal) (*adk.TaskList, error) {
+	fake.listTasksForPrincipalMutex.Lock()
+	ret, specificReturn := fake.listTasksForPrincipalReturnsOnCall[len(fake.listTasksForPrincipalArgsForCall)]
+	fake.listTasksForPrincipalArgsForCall = append(fake.listTasksForPrincipalArgsForCall, struct {
+		arg1 adk.TaskListParams
+		arg2 *middlewares.Principal
+	}{arg1, arg2})
+	stub := fake.ListTasksForPrincipalStub
+	fakeReturns := fake.listTasksForPrincipalReturns
+	fake.recordInvocation("ListTasksForPrincipal", []interface{}{arg1, arg2})
+	fake.listTasksForPrincipalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskManager) ListTasksForPrincipalCallCount() int {
+	fake.listTasksForPrincipalMutex.RLock()
+	defer fake.listTasksForPrincipalMutex.RUnlock()
+	return len(fake.listTasksForPrincipalArgsForCall)
+}
+
+func (fake *FakeTaskManager) ListTasksForPrincipalCalls(stub func(adk.TaskListParams, *middlewares.Principal) (*adk.TaskList, error)) {
+	fake.listTasksForPrincipalMutex.Lock()
+	defer fake.listTasksForPrincipalMutex.Unlock()
+	fake.ListTasksForPrincipalStub = stub
+}
+
+func (fake *FakeTaskManager) ListTasksForPrincipalArgsForCall(i int) (adk.TaskListParams, *middlewares.Principal) {
+	fake.listTasksForPrincipalMutex.RLock()
+	defer fake.listTasksForPrincipalMutex.RUnlock()
+	argsForCall := fake.listTasksForPrincipalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTaskManager) ListTasksForPrincipalReturns(result1 *adk.TaskList, result2 error) {
+	fake.listTasksForPrincipalMutex.Lock()
+	defer fake.listTasksForPrincipalMutex.Unlock()
+	fake.ListTasksForPrincipalStub = nil
+	fake.listTasksForPrincipalReturns = struct {
+		result1 *adk.TaskList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskManager) ListTasksForPrincipalReturnsOnCall(i int, result1 *adk.TaskList, result2 error) {
+	fake.listTasksForPrincipalMutex.Lock()
+	defer fake.listTasksForPrincipalMutex.Unlock()
+	fake.ListTasksForPrincipalStub = nil
+	if fake.listTasksForPrincipalReturnsOnCall == nil {
+		fake.listTasksForPrincipalReturnsOnCall = make(map[int]struct {
+			result1 *adk.TaskList
+			result2 error
+		})
+	}
+	fake.listTasksForPrincipalReturnsOnCall[i] = struct {
+		result1 *adk.TaskList
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeTaskManager) PollTaskStatus(arg1 string, arg2 time.Duration, arg3 time.Duration) (*adk.Task, error) {
 	fake.pollTaskStatusMutex.Lock()
 	ret, specificReturn := fake.pollTaskStatusReturnsOnCall[len(fake.pollTaskStatusArgsForCall)]
@@ -755,6 +1025,106 @@ func (fake *FakeTaskManager) PollTaskStatusReturnsOnCall(i int, result1 *adk.Tas
 	}{result1, result2}
 }
 
+func (fake *FakeTaskManager) ResumeTask(arg1 string, arg2 adk.TaskState, arg3 *adk.Message) (*adk.Task, error) {
+	fake.resumeTaskMutex.Lock()
+	ret, specificReturn := fake.resumeTaskReturnsOnCall[len(fake.resumeTaskArgsForCall)]
+	fake.resumeTaskArgsForCall = append(fake.resumeTaskArgsForCall, struct {
+		arg1 string
+		arg2 adk.TaskState
+		arg3 *adk.Message
+	}{arg1, arg2, arg3})
+	stub := fake.ResumeTaskStub
+	fakeReturns := fake.resumeTaskReturns
+	fake.recordInvocation("ResumeTask", []interface{}{arg1, arg2, arg3})
+	fake.resumeTaskMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTaskManager) ResumeTaskCallCount() int {
+	fake.resumeTaskMutex.RLock()
+	defer fake.resumeTaskMutex.RUnlock()
+	return len(fake.resumeTaskArgsForCall)
+}
+
+func (fake *FakeTaskManager) ResumeTaskCalls(stub func(string, adk.TaskState, *adk.Message) (*adk.Task, error)) {
+	fake.resumeTaskMutex.Lock()
+	defer fake.resumeTaskMutex.Unlock()
+	fake.ResumeTaskStub = stub
+}
+
+func (fake *FakeTaskManager) ResumeTaskArgsForCall(i int) (string, adk.TaskState, *adk.Message) {
+	fake.resumeTaskMutex.RLock()
+	defer fake.resumeTaskMutex.RUnlock()
+	argsForCall := fake.resumeTaskArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTaskManager) ResumeTaskReturns(result1 *adk.Task, result2 error) {
+	fake.resumeTaskMutex.Lock()
+	defer fake.resumeTaskMutex.Unlock()
+	fake.ResumeTaskStub = nil
+	fake.resumeTaskReturns = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskManager) ResumeTaskReturnsOnCall(i int, result1 *adk.Task, result2 error) {
+	fake.resumeTaskMutex.Lock()
+	defer fake.resumeTaskMutex.Unlock()
+	fake.ResumeTaskStub = nil
+	if fake.resumeTaskReturnsOnCall == nil {
+		fake.resumeTaskReturnsOnCall = make(map[int]struct {
+			result1 *adk.Task
+			result2 error
+		})
+	}
+	fake.resumeTaskReturnsOnCall[i] = struct {
+		result1 *adk.Task
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTaskManager) SetTaskMetadata(arg1 *adk.Task, arg2 string, arg3 interface{}) {
+	fake.setTaskMetadataMutex.Lock()
+	fake.setTaskMetadataArgsForCall = append(fake.setTaskMetadataArgsForCall, struct {
+		arg1 *adk.Task
+		arg2 string
+		arg3 interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.SetTaskMetadataStub
+	fake.recordInvocation("SetTaskMetadata", []interface{}{arg1, arg2, arg3})
+	fake.setTaskMetadataMutex.Unlock()
+	if stub != nil {
+		fake.SetTaskMetadataStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTaskManager) SetTaskMetadataCallCount() int {
+	fake.setTaskMetadataMutex.RLock()
+	defer fake.setTaskMetadataMutex.RUnlock()
+	return len(fake.setTaskMetadataArgsForCall)
+}
+
+func (fake *FakeTaskManager) SetTaskMetadataCalls(stub func(*adk.Task, string, interface{})) {
+	fake.setTaskMetadataMutex.Lock()
+	defer fake.setTaskMetadataMutex.Unlock()
+	fake.SetTaskMetadataStub = stub
+}
+
+func (fake *FakeTaskManager) SetTaskMetadataArgsForCall(i int) (*adk.Task, string, interface{}) {
+	fake.setTaskMetadataMutex.RLock()
+	defer fake.setTaskMetadataMutex.RUnlock()
+	argsForCall := fake.setTaskMetadataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
 func (fake *FakeTaskManager) SetTaskPushNotificationConfig(arg1 adk.TaskPushNotificationConfig) (*adk.TaskPushNotificationConfig, error) {
 	fake.setTaskPushNotificationConfigMutex.Lock()
 	ret, specificReturn := fake.setTaskPushNotificationConfigReturnsOnCall[len(fake.setTaskPushNotificationConfigArgsForCall)]
@@ -819,6 +1189,67 @@ func (fake *FakeTaskManager) SetTaskPushNotificationConfigReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *FakeTaskManager) TaskOwner(arg1 string) string {
+	fake.taskOwnerMutex.Lock()
+	ret, specificReturn := fake.taskOwnerReturnsOnCall[len(fake.taskOwnerArgsForCall)]
+	fake.taskOwnerArgsForCall = append(fake.taskOwnerArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.TaskOwnerStub
+	fakeReturns := fake.taskOwnerReturns
+	fake.recordInvocation("TaskOwner", []interface{}{arg1})
+	fake.taskOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTaskManager) TaskOwnerCallCount() int {
+	fake.taskOwnerMutex.RLock()
+	defer fake.taskOwnerMutex.RUnlock()
+	return len(fake.taskOwnerArgsForCall)
+}
+
+func (fake *FakeTaskManager) TaskOwnerCalls(stub func(string) string) {
+	fake.taskOwnerMutex.Lock()
+	defer fake.taskOwnerMutex.Unlock()
+	fake.TaskOwnerStub = stub
+}
+
+func (fake *FakeTaskManager) TaskOwnerArgsForCall(i int) string {
+	fake.taskOwnerMutex.RLock()
+	defer fake.taskOwnerMutex.RUnlock()
+	argsForCall := fake.taskOwnerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTaskManager) TaskOwnerReturns(result1 string) {
+	fake.taskOwnerMutex.Lock()
+	defer fake.taskOwnerMutex.Unlock()
+	fake.TaskOwnerStub = nil
+	fake.taskOwnerReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeTaskManager) TaskOwnerReturnsOnCall(i int, result1 string) {
+	fake.taskOwnerMutex.Lock()
+	defer fake.taskOwnerMutex.Unlock()
+	fake.TaskOwnerStub = nil
+	if fake.taskOwnerReturnsOnCall == nil {
+		fake.taskOwnerReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.taskOwnerReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeTaskManager) UpdateConversationHistory(arg1 string, arg2 []adk.Message) {
 	var arg2Copy []adk.Message
 	if arg2 != nil {
@@ -923,12 +1354,16 @@ func (fake *FakeTaskManager) UpdateTaskReturnsOnCall(i int, result1 error) {
 func (fake *FakeTaskManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.authorizeTaskMutex.RLock()
+	defer fake.authorizeTaskMutex.RUnlock()
 	fake.cancelTaskMutex.RLock()
 	defer fake.cancelTaskMutex.RUnlock()
 	fake.cleanupCompletedTasksMutex.RLock()
 	defer fake.cleanupCompletedTasksMutex.RUnlock()
 	fake.createTaskMutex.RLock()
 	defer fake.createTaskMutex.RUnlock()
+	fake.createTaskForPrincipalMutex.RLock()
+	defer fake.createTaskForPrincipalMutex.RUnlock()
 	fake.deleteTaskPushNotificationConfigMutex.RLock()
 	defer fake.deleteTaskPushNotificationConfigMutex.RUnlock()
 	fake.getConversationHistoryMutex.RLock()
@@ -941,10 +1376,18 @@ func (fake *FakeTaskManager) Invocations() map[string][][]interface{} {
 	defer fake.listTaskPushNotificationConfigsMutex.RUnlock()
 	fake.listTasksMutex.RLock()
 	defer fake.listTasksMutex.RUnlock()
+	fake.listTasksForPrincipalMutex.RLock()
+	defer fake.listTasksForPrincipalMutex.RUnlock()
 	fake.pollTaskStatusMutex.RLock()
 	defer fake.pollTaskStatusMutex.RUnlock()
+	fake.resumeTaskMutex.RLock()
+	defer fake.resumeTaskMutex.RUnlock()
+	fake.setTaskMetadataMutex.RLock()
+	defer fake.setTaskMetadataMutex.RUnlock()
 	fake.setTaskPushNotificationConfigMutex.RLock()
 	defer fake.setTaskPushNotificationConfigMutex.RUnlock()
+	fake.taskOwnerMutex.RLock()
+	defer fake.taskOwnerMutex.RUnlock()
 	fake.updateConversationHistoryMutex.RLock()
 	defer fake.updateConversationHistoryMutex.RUnlock()
 	fake.updateTaskMutex.RLock()
@@ -27,6 +27,8 @@ type OpenTelemetry interface {
 	RecordTaskCompleted(ctx context.Context, attrs TelemetryAttributes, success bool)
 	RecordTaskFailure(ctx context.Context, attrs TelemetryAttributes, toolName string, errorMessage string)
 	RecordToolCallFailure(ctx context.Context, attrs TelemetryAttributes, toolName string, errorMessage string)
+	RecordToolCallSafeguard(ctx context.Context, attrs TelemetryAttributes, toolName string, safeguard string)
+	RecordLLMRequestAttempts(ctx context.Context, attrs TelemetryAttributes, attempts int, success bool)
 
 	// Shutdown the telemetry system
 	ShutDown(ctx context.Context) error
@@ -49,12 +51,17 @@ type OpenTelemetryImpl struct {
 	responseStatusCounter    metric.Int64Counter
 	requestDurationHistogram metric.Float64Histogram
 	toolCallFailureCounter   metric.Int64Counter
+	toolCallSafeguardCounter metric.Int64Counter
+	llmRequestAttempts       metric.Int64Histogram
 }
 
 type TelemetryAttributes struct {
 	Provider string
 	Model    string
 	TaskID   string
+
+	// Principal is the owner the usage is billed to, recorded on the token usage metrics
+	Principal string
 }
 
 // NewOpenTelemetry creates a new OpenTelemetry implementation with proper dependency injection
@@ -143,6 +150,7 @@ func (o *OpenTelemetryImpl) RecordTokenUsage(ctx context.Context, attrs Telemetr
 	attributes := []attribute.KeyValue{
 		attribute.String("provider", attrs.Provider),
 		attribute.String("model", attrs.Model),
+		attribute.String("principal", attrs.Principal),
 	}
 
 	o.promptTokensCounter.Add(ctx, usage.PromptTokens, metric.WithAttributes(attributes...))
@@ -244,6 +252,32 @@ func (o *OpenTelemetryImpl) RecordToolCallFailure(ctx context.Context, attrs Tel
 	o.toolCallFailureCounter.Add(ctx, 1, metric.WithAttributes(attributes...))
 }
 
+func (o *OpenTelemetryImpl) RecordToolCallSafeguard(ctx context.Context, attrs TelemetryAttributes, toolName string, safeguard string) {
+	attributes := []attribute.KeyValue{
+		attribute.String("task_id", attrs.TaskID),
+		attribute.String("tool_name", toolName),
+		attribute.String("safeguard", safeguard),
+	}
+	if attrs.Provider != "" {
+		attributes = append(attributes, attribute.String("provider", attrs.Provider))
+	}
+	if attrs.Model != "" {
+		attributes = append(attributes, attribute.String("model", attrs.Model))
+	}
+
+	o.toolCallSafeguardCounter.Add(ctx, 1, metric.WithAttributes(attributes...))
+}
+
+func (o *OpenTelemetryImpl) RecordLLMRequestAttempts(ctx context.Context, attrs TelemetryAttributes, attempts int, success bool) {
+	attributes := []attribute.KeyValue{
+		attribute.String("provider", attrs.Provider),
+		attribute.String("model", attrs.Model),
+		attribute.Bool("success", success),
+	}
+
+	o.llmRequestAttempts.Record(ctx, int64(attempts), metric.WithAttributes(attributes...))
+}
+
 func (o *OpenTelemetryImpl) ShutDown(ctx context.Context) error {
 	return o.meterProvider.Shutdown(ctx)
 }
@@ -351,6 +385,24 @@ func (o *OpenTelemetryImpl) initializeMetrics(serviceName string) error {
 		return fmt.Errorf("failed to create tool call failure counter: %w", err)
 	}
 
+	o.toolCallSafeguardCounter, err = o.meter.Int64Counter(
+		"a2a.tool_call_safeguards.total",
+		metric.WithDescription("Total number of tool calls stopped by a timeout or panic, or with truncated or summarized output"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create tool call safeguard counter: %w", err)
+	}
+
+	o.llmRequestAttempts, err = o.meter.Int64Histogram(
+		"a2a.llm.request.attempts",
+		metric.WithDescription("Number of attempts of LLM chat completion requests, retries included"),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create llm request attempts histogram: %w", err)
+	}
+
 	o.logger.Debug("all opentelemetry metrics initialized successfully")
 	return nil
 }
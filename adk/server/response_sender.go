@@ -13,6 +13,9 @@ type ResponseSender interface {
 
 	// SendError sends a JSON-RPC error response
 	SendError(c *gin.Context, id interface{}, code int, message string)
+
+	// SendErrorWithData sends a JSON-RPC error response carrying additional structured data
+	SendErrorWithData(c *gin.Context, id interface{}, code int, message string, data interface{})
 }
 
 // DefaultResponseSender implements the ResponseSender interface
@@ -51,3 +54,18 @@ func (rs *DefaultResponseSender) SendError(c *gin.Context, id interface{}, code
 	c.JSON(200, resp) // JSON-RPC always returns 200 OK, errors are in the response body
 	rs.logger.Error("sending error response", zap.Int("code", code), zap.String("message", message))
 }
+
+// SendErrorWithData sends a JSON-RPC error response carrying additional structured data
+func (rs *DefaultResponseSender) SendErrorWithData(c *gin.Context, id interface{}, code int, message string, data interface{}) {
+	resp := adk.JSONRPCErrorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &adk.JSONRPCError{
+			Code:    code,
+			Message: message,
+			Data:    &data,
+		},
+	}
+	c.JSON(200, resp)
+	rs.logger.Error("sending error response", zap.Int("code", code), zap.String("message", message), zap.Any("data", data))
+}
@@ -1,6 +1,7 @@
 package server_test
 
 import (
+	"encoding/json"
 	"net/http/httptest"
 	"testing"
 
@@ -175,3 +176,30 @@ func TestNewDefaultResponseSender_WithNilLogger(t *testing.T) {
 		assert.NotNil(t, responseSender)
 	})
 }
+
+func TestDefaultResponseSender_SendErrorWithData(t *testing.T) {
+	logger := zap.NewNop()
+	responseSender := server.NewDefaultResponseSender(logger)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	responseSender.SendErrorWithData(ctx, "data-id", -32010, "permission denied", map[string]interface{}{
+		"method": "tasks/cancel",
+	})
+
+	assert.Equal(t, 200, w.Code)
+
+	var resp struct {
+		Error struct {
+			Code    int                    `json:"code"`
+			Message string                 `json:"message"`
+			Data    map[string]interface{} `json:"data"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, -32010, resp.Error.Code)
+	assert.Equal(t, "permission denied", resp.Error.Message)
+	assert.Equal(t, "tasks/cancel", resp.Error.Data["method"])
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+
+	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
+	zap "go.uber.org/zap"
+)
+
+// SelfCheckStatus represents the outcome of a single startup check
+type SelfCheckStatus string
+
+const (
+	SelfCheckStatusOK      SelfCheckStatus = "ok"
+	SelfCheckStatusWarning SelfCheckStatus = "warning"
+	SelfCheckStatusError   SelfCheckStatus = "error"
+)
+
+// SelfCheckResult is the outcome of checking a single component
+type SelfCheckResult struct {
+	Component string          `json:"component"`
+	Status    SelfCheckStatus `json:"status"`
+	Message   string          `json:"message"`
+}
+
+// SelfCheckReport summarizes the startup self-check covering auth, TLS, rate limiting and capabilities
+type SelfCheckReport struct {
+	Results []SelfCheckResult `json:"results"`
+}
+
+// add records the outcome of a check
+func (r *SelfCheckReport) add(component string, status SelfCheckStatus, format string, args ...interface{}) {
+	r.Results = append(r.Results, SelfCheckResult{
+		Component: component,
+		Status:    status,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// Err returns the failed checks joined into a single error, or nil if no check failed
+func (r *SelfCheckReport) Err() error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, result := range r.Results {
+		if result.Status == SelfCheckStatusError {
+			errs = append(errs, fmt.Errorf("%s: %s", result.Component, result.Message))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes every check result to the logger at a level matching its status
+func (r *SelfCheckReport) Log(logger *zap.Logger) {
+	for _, result := range r.Results {
+		fields := []zap.Field{
+			zap.String("component", result.Component),
+			zap.String("result", result.Message),
+		}
+		switch result.Status {
+		case SelfCheckStatusError:
+			logger.Error("startup self-check failed", fields...)
+		case SelfCheckStatusWarning:
+			logger.Warn("startup self-check warning", fields...)
+		default:
+			logger.Info("startup self-check passed", fields...)
+		}
+	}
+}
+
+// SelfCheckReport returns the report of the last startup self-check, or nil if the server was not started
+func (s *A2AServerImpl) SelfCheckReport() *SelfCheckReport {
+	return s.selfCheckReport.Load()
+}
+
+// runSelfCheck verifies the auth, TLS, rate limiting, budget and capabilities setup of the server
+func (s *A2AServerImpl) runSelfCheck(authErr error) *SelfCheckReport {
+	report := &SelfCheckReport{}
+	s.checkAuth(report, authErr)
+	s.checkTLS(report)
+	s.checkRateLimit(report)
+	s.checkBudget(report)
+	s.checkCapabilities(report)
+	return report
+}
+
+// checkAuth reports authentication and authorization misconfigurations
+func (s *A2AServerImpl) checkAuth(report *SelfCheckReport, authErr error) {
+	authConfig := s.cfg.AuthConfig
+
+	if !authConfig.Enable {
+		if !s.authorizationPolicy.IsEmpty() {
+			report.add("auth", SelfCheckStatusError, "method or skill authorization policies are configured but authentication is disabled")
+			return
+		}
+		report.add("auth", SelfCheckStatusWarning, "authentication is disabled, all requests are accepted without credentials")
+		return
+	}
+
+	if authErr != nil {
+		report.add("auth", SelfCheckStatusError, "authentication is enabled but misconfigured: %v", authErr)
+		return
+	}
+
+	schemes := s.authenticationSchemes()
+	report.add("auth", SelfCheckStatusOK, "authentication enabled with schemes: %s", strings.Join(schemes, ", "))
+
+	if s.cfg.ServerConfig.TLSConfig.Enable {
+		return
+	}
+	for _, scheme := range schemes {
+		if scheme == middlewares.AuthSchemeAPIKey || scheme == middlewares.AuthSchemeBasic {
+			report.add("auth", SelfCheckStatusWarning, "%s credentials are sent without TLS, terminate TLS in front of the server", scheme)
+		}
+	}
+}
+
+// checkTLS reports whether the configured certificate and key can be loaded
+func (s *A2AServerImpl) checkTLS(report *SelfCheckReport) {
+	tlsConfig := s.cfg.ServerConfig.TLSConfig
+
+	if !tlsConfig.Enable {
+		report.add("tls", SelfCheckStatusWarning, "tls is disabled, serving plain http")
+		return
+	}
+
+	if tlsConfig.CertPath == "" || tlsConfig.KeyPath == "" {
+		report.add("tls", SelfCheckStatusError, "tls is enabled but the certificate or key path is missing")
+		return
+	}
+
+	if _, err := tls.LoadX509KeyPair(tlsConfig.CertPath, tlsConfig.KeyPath); err != nil {
+		report.add("tls", SelfCheckStatusError, "failed to load tls certificate: %v", err)
+		return
+	}
+
+	report.add("tls", SelfCheckStatusOK, "tls enabled with certificate %s", tlsConfig.CertPath)
+}
+
+// checkRateLimit reports whether the rate limiter could be created from its configuration
+func (s *A2AServerImpl) checkRateLimit(report *SelfCheckReport) {
+	rateLimitConfig := s.cfg.RateLimitConfig
+
+	switch {
+	case !rateLimitConfig.Enable:
+		report.add("rate_limit", SelfCheckStatusWarning, "rate limiting is disabled")
+	case s.rateLimiterErr != nil:
+		report.add("rate_limit", SelfCheckStatusError, "rate limiting is enabled but misconfigured: %v", s.rateLimiterErr)
+	default:
+		report.add("rate_limit", SelfCheckStatusOK, "rate limiting by %s: %d requests/min, %d llm tokens/min",
+			rateLimitConfig.KeyBy, rateLimitConfig.RequestsPerMinute, rateLimitConfig.TokensPerMinute)
+	}
+}
+
+// checkBudget reports whether the budget tracker could be created from its configuration
+func (s *A2AServerImpl) checkBudget(report *SelfCheckReport) {
+	budgetConfig := s.cfg.BudgetConfig
+
+	switch {
+	case !budgetConfig.Enable:
+		return
+	case s.budgetErr != nil:
+		report.add("budget", SelfCheckStatusError, "budgets are enabled but misconfigured: %v", s.budgetErr)
+	default:
+		report.add("budget", SelfCheckStatusOK, "llm token budgets: %d per task, %d per context per day, %d per principal per month",
+			budgetConfig.MaxTokensPerTask, budgetConfig.MaxTokensPerContextPerDay, budgetConfig.MaxTokensPerPrincipalPerMonth)
+		s.checkModelPrices(report)
+	}
+}
+
+// checkModelPrices reports the configured models without a price when a cost limit is set
+// Tasks of an unpriced default model are rejected, unpriced fallback and routing models are charged at the price of the requested model
+func (s *A2AServerImpl) checkModelPrices(report *SelfCheckReport) {
+	if s.budget == nil || !hasCostLimit(s.cfg.BudgetConfig) {
+		return
+	}
+
+	agentConfig := s.cfg.AgentConfig
+	if !s.budget.Priced(agentConfig.Model) {
+		report.add("budget", SelfCheckStatusError, "cost limits are set but the default model %q has no price, its tasks are rejected", agentConfig.Model)
+	}
+
+	var unpriced []string
+	for _, model := range append(append([]string{}, agentConfig.FallbackModels...), agentConfig.Routing.ToolsModel, agentConfig.Routing.ShortPromptModel) {
+		if model != "" && !s.budget.Priced(model) {
+			unpriced = append(unpriced, model)
+		}
+	}
+	if len(unpriced) > 0 {
+		report.add("budget", SelfCheckStatusWarning, "cost limits are set but models %s have no price, their completions are charged at the price of the requested model",
+			strings.Join(unpriced, ", "))
+	}
+}
+
+// checkCapabilities reports capabilities that are advertised but not backed by the server setup
+func (s *A2AServerImpl) checkCapabilities(report *SelfCheckReport) {
+	capabilities := s.cfg.CapabilitiesConfig
+
+	if capabilities.Streaming && s.agent == nil && asStreamingTaskHandler(s.taskHandler) == nil {
+		report.add("capabilities", SelfCheckStatusWarning, "streaming is enabled but neither an agent nor a task handler is configured, message/stream answers with mock progress updates")
+	}
+
+	if s.customAgentCard != nil {
+		cardCapabilities := s.customAgentCard.Capabilities
+		if cardCapabilities.Streaming != nil && *cardCapabilities.Streaming != capabilities.Streaming {
+			report.add("capabilities", SelfCheckStatusWarning, "agent card advertises streaming=%t but the server is configured with streaming=%t",
+				*cardCapabilities.Streaming, capabilities.Streaming)
+		}
+		if cardCapabilities.PushNotifications != nil && *cardCapabilities.PushNotifications != capabilities.PushNotifications {
+			report.add("capabilities", SelfCheckStatusWarning, "agent card advertises pushNotifications=%t but the server is configured with pushNotifications=%t",
+				*cardCapabilities.PushNotifications, capabilities.PushNotifications)
+		}
+	}
+
+	report.add("capabilities", SelfCheckStatusOK, "streaming=%t pushNotifications=%t stateTransitionHistory=%t",
+		capabilities.Streaming, capabilities.PushNotifications, capabilities.StateTransitionHistory)
+}
@@ -0,0 +1,261 @@
+package server_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newSelfCheckServer(cfg *config.Config) *server.A2AServerImpl {
+	cfg.ServerConfig.Port = "0"
+	cfg.QueueConfig.MaxSize = 10
+	cfg.QueueConfig.CleanupInterval = time.Minute
+
+	a2aServer := server.NewA2AServer(cfg, zap.NewNop(), nil)
+	a2aServer.SetAgentCard(adk.AgentCard{
+		Name:    "self-check-agent",
+		Version: "1.0.0",
+		URL:     "http://localhost",
+	})
+	return a2aServer
+}
+
+func TestA2AServer_Start_StrictModeFailsOnMisconfiguration(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.Config
+		expectedError string
+	}{
+		{
+			name: "auth enabled without credentials for the scheme",
+			cfg: config.Config{
+				AuthConfig: config.AuthConfig{
+					Enable:     true,
+					Schemes:    []string{"apikey"},
+					APIKeyName: "X-API-Key",
+					APIKeyIn:   "header",
+				},
+			},
+			expectedError: "api key authentication requires at least one api key",
+		},
+		{
+			name: "oidc enabled without issuer",
+			cfg: config.Config{
+				AuthConfig: config.AuthConfig{
+					Enable:   true,
+					Schemes:  []string{"oidc"},
+					ClientID: "client",
+				},
+			},
+			expectedError: "oidc authentication requires an issuer url and a client id",
+		},
+		{
+			name: "authorization policy without authentication",
+			cfg: config.Config{
+				AuthConfig: config.AuthConfig{
+					MethodScopes: map[string]string{"message/send": "a2a:write"},
+				},
+			},
+			expectedError: "authentication is disabled",
+		},
+		{
+			name: "tls enabled without certificate",
+			cfg: config.Config{
+				ServerConfig: config.ServerConfig{
+					TLSConfig: config.TLSConfig{Enable: true},
+				},
+			},
+			expectedError: "certificate or key path is missing",
+		},
+		{
+			name: "cost budget without model prices",
+			cfg: config.Config{
+				BudgetConfig: config.BudgetConfig{Enable: true, MaxCostPerTask: 1},
+			},
+			expectedError: "cost limits require model prices",
+		},
+		{
+			name: "cost budget with an unpriced default model",
+			cfg: config.Config{
+				AgentConfig:  config.AgentConfig{Model: "openai/llama-3"},
+				BudgetConfig: config.BudgetConfig{Enable: true, MaxCostPerTask: 1, ModelPrices: map[string]string{"gpt-4o": "2.5/10"}},
+			},
+			expectedError: `default model "openai/llama-3" has no price`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			a2aServer := newSelfCheckServer(&cfg)
+
+			err := a2aServer.Start(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "startup self-check failed")
+			assert.Contains(t, err.Error(), tt.expectedError)
+
+			report := a2aServer.SelfCheckReport()
+			require.NotNil(t, report)
+			assert.Error(t, report.Err())
+		})
+	}
+}
+
+func TestA2AServer_Start_NonStrictModeStartsDespiteMisconfiguration(t *testing.T) {
+	cfg := config.Config{
+		AuthConfig: config.AuthConfig{
+			MethodScopes: map[string]string{"message/send": "a2a:write"},
+		},
+		ServerConfig: config.ServerConfig{
+			DisableStrictMode: true,
+		},
+	}
+	a2aServer := newSelfCheckServer(&cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a2aServer.Start(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("server stopped unexpectedly: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	report := a2aServer.SelfCheckReport()
+	require.NotNil(t, report)
+	assert.Error(t, report.Err())
+
+	components := make(map[string]bool)
+	for _, result := range report.Results {
+		components[result.Component] = true
+	}
+	assert.True(t, components["auth"])
+	assert.True(t, components["tls"])
+	assert.True(t, components["capabilities"])
+
+	_ = a2aServer.Stop(context.Background())
+}
+
+func TestA2AServer_Start_WarnsAboutUnpricedFallbackModels(t *testing.T) {
+	cfg := config.Config{
+		AgentConfig: config.AgentConfig{
+			Model:          "openai/gpt-4o",
+			FallbackModels: []string{"anthropic/claude-3-5-haiku", "openai/gpt-4o-mini"},
+			Routing:        config.LLMRoutingConfig{ToolsModel: "groq/llama-3"},
+		},
+		BudgetConfig: config.BudgetConfig{Enable: true, MaxCostPerTask: 1, ModelPrices: map[string]string{"gpt-4o": "2.5/10", "gpt-4o-mini": "0.15/0.6"}},
+	}
+	report := startSelfCheckServer(t, newSelfCheckServer(&cfg))
+
+	assert.Equal(t, []string{
+		"cost limits are set but models anthropic/claude-3-5-haiku, groq/llama-3 have no price, their completions are charged at the price of the requested model",
+	}, selfCheckWarnings(report, "budget"))
+}
+
+func TestA2AServer_Start_WarnsAboutMockStreaming(t *testing.T) {
+	tests := []struct {
+		name             string
+		taskHandler      server.TaskHandler
+		expectedWarnings []string
+	}{
+		{
+			name: "no agent or task handler",
+			expectedWarnings: []string{
+				"streaming is enabled but neither an agent nor a task handler is configured, message/stream answers with mock progress updates",
+			},
+		},
+		{
+			name:        "task handler",
+			taskHandler: &mocks.FakeTaskHandler{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{CapabilitiesConfig: config.CapabilitiesConfig{Streaming: true}}
+			a2aServer := newSelfCheckServer(&cfg)
+			if tt.taskHandler != nil {
+				a2aServer.SetTaskHandler(tt.taskHandler)
+			}
+
+			report := startSelfCheckServer(t, a2aServer)
+
+			var streamingWarnings []string
+			for _, warning := range selfCheckWarnings(report, "capabilities") {
+				if strings.HasPrefix(warning, "streaming is enabled") {
+					streamingWarnings = append(streamingWarnings, warning)
+				}
+			}
+			assert.Equal(t, tt.expectedWarnings, streamingWarnings)
+		})
+	}
+}
+
+// startSelfCheckServer starts the server and returns its self-check report, the server is stopped with the test
+func startSelfCheckServer(t *testing.T, a2aServer *server.A2AServerImpl) *server.SelfCheckReport {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		_ = a2aServer.Stop(context.Background())
+		cancel()
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a2aServer.Start(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("server stopped unexpectedly: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	report := a2aServer.SelfCheckReport()
+	require.NotNil(t, report)
+	return report
+}
+
+// selfCheckWarnings returns the warning messages of the component
+func selfCheckWarnings(report *server.SelfCheckReport, component string) []string {
+	var warnings []string
+	for _, result := range report.Results {
+		if result.Component == component && result.Status == server.SelfCheckStatusWarning {
+			warnings = append(warnings, result.Message)
+		}
+	}
+	return warnings
+}
+
+func TestSelfCheckReport_Err(t *testing.T) {
+	report := &server.SelfCheckReport{
+		Results: []server.SelfCheckResult{
+			{Component: "tls", Status: server.SelfCheckStatusWarning, Message: "tls is disabled"},
+			{Component: "capabilities", Status: server.SelfCheckStatusOK, Message: "streaming=true"},
+		},
+	}
+	assert.NoError(t, report.Err())
+
+	report.Results = append(report.Results, server.SelfCheckResult{
+		Component: "auth",
+		Status:    server.SelfCheckStatusError,
+		Message:   "misconfigured",
+	})
+	assert.EqualError(t, report.Err(), "auth: misconfigured")
+
+	var nilReport *server.SelfCheckReport
+	assert.NoError(t, nilReport.Err())
+}
@@ -2,11 +2,18 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	gin "github.com/gin-gonic/gin"
@@ -15,6 +22,7 @@ import (
 	config "github.com/inference-gateway/a2a/adk/server/config"
 	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
 	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
 	promhttp "github.com/prometheus/client_golang/prometheus/promhttp"
 	envconfig "github.com/sethvargo/go-envconfig"
 	zap "go.uber.org/zap"
@@ -87,12 +95,19 @@ const (
 	ErrInvalidParams  JRPCErrorCode = -32602
 	ErrInternalError  JRPCErrorCode = -32603
 	ErrServerError    JRPCErrorCode = -32000
+
+	// ErrPermissionDenied is returned when the authorization policy denies a request
+	ErrPermissionDenied JRPCErrorCode = -32010
 )
 
 // QueuedTask represents a task in the processing queue
 type QueuedTask struct {
 	Task      *adk.Task
 	RequestID interface{}
+
+	// RequestContext carries the values of the originating request, such as the authenticated principal.
+	// It is detached from the request cancellation since the task is processed after the response is sent.
+	RequestContext context.Context
 }
 
 type A2AServerImpl struct {
@@ -104,7 +119,8 @@ type A2AServerImpl struct {
 	responseSender ResponseSender
 	otel           otel.OpenTelemetry
 
-	// Server state
+	// Server state, the servers are guarded by serversMu since Stop runs while Start serves
+	serversMu     sync.Mutex
 	httpServer    *http.Server
 	metricsServer *http.Server
 	taskQueue     chan *QueuedTask
@@ -113,8 +129,34 @@ type A2AServerImpl struct {
 	taskResultProcessor TaskResultProcessor
 	agent               OpenAICompatibleAgent
 
+	// Whether the task handler was set with SetTaskHandler, it then keeps handling the streams of agents set later
+	customTaskHandler bool
+
 	// Custom agent card
 	customAgentCard *adk.AgentCard
+
+	// Method and skill level authorization
+	authorizationPolicy *middlewares.AuthorizationPolicy
+
+	// Authentication, custom authenticators take precedence over the configured schemes
+	authenticators     []middlewares.Authenticator
+	authenticatorChain *middlewares.AuthenticatorChain
+
+	// Result of the startup self-check, read while Start runs
+	selfCheckReport atomic.Pointer[SelfCheckReport]
+
+	// Per-client request and LLM token rate limiting
+	rateLimiter    *middlewares.RateLimiter
+	rateLimiterErr error
+
+	// Tokens used by the LLM per context and principal, and the budgets limiting them
+	tokenUsage *TokenUsageTracker
+	budget     *BudgetTracker
+	budgetErr  error
+
+	// Circuit breakers of the LLM providers, by provider
+	circuitBreakersMu sync.Mutex
+	circuitBreakers   map[string]*CircuitBreaker
 }
 
 var _ A2AServer = (*A2AServerImpl)(nil)
@@ -132,12 +174,17 @@ func NewA2AServer(cfg *config.Config, logger *zap.Logger, otel otel.OpenTelemetr
 	}
 
 	server := &A2AServerImpl{
-		cfg:       cfg,
-		logger:    logger,
-		otel:      otel,
-		taskQueue: make(chan *QueuedTask, cfg.QueueConfig.MaxSize),
+		cfg:                 cfg,
+		logger:              logger,
+		otel:                otel,
+		taskQueue:           make(chan *QueuedTask, cfg.QueueConfig.MaxSize),
+		authorizationPolicy: middlewares.NewAuthorizationPolicy(cfg.AuthConfig),
 	}
 
+	server.setupRateLimiter()
+	server.setupTokenUsage()
+	server.setupBudget()
+
 	maxConversationHistory := cfg.AgentConfig.MaxConversationHistory
 	server.taskManager = NewDefaultTaskManager(logger, maxConversationHistory)
 	server.messageHandler = NewDefaultMessageHandler(logger, server.taskManager, cfg)
@@ -152,8 +199,12 @@ func NewA2AServerWithAgent(cfg *config.Config, logger *zap.Logger, otel otel.Ope
 	server := NewA2AServer(cfg, logger, otel)
 
 	if agent != nil {
+		server.instrumentAgentLLMClient(agent)
+		server.wrapAgentCircuitBreaker(agent)
+		server.wrapAgentLLMClient(agent)
+		server.instrumentAgentToolBox(agent)
 		server.agent = agent
-		server.taskHandler = NewAgentTaskHandler(logger, agent)
+		server.taskHandler = NewAgentTaskHandlerWithConfig(logger, agent, &cfg.AgentConfig)
 		server.messageHandler = NewDefaultMessageHandlerWithAgent(logger, server.taskManager, agent, cfg)
 	}
 
@@ -204,12 +255,17 @@ func NewA2AServerEnvironmentAware(cfg *config.Config, logger *zap.Logger, otel o
 	}
 
 	server := &A2AServerImpl{
-		cfg:       cfg,
-		logger:    logger,
-		otel:      otel,
-		taskQueue: make(chan *QueuedTask, cfg.QueueConfig.MaxSize),
+		cfg:                 cfg,
+		logger:              logger,
+		otel:                otel,
+		taskQueue:           make(chan *QueuedTask, cfg.QueueConfig.MaxSize),
+		authorizationPolicy: middlewares.NewAuthorizationPolicy(cfg.AuthConfig),
 	}
 
+	server.setupRateLimiter()
+	server.setupTokenUsage()
+	server.setupBudget()
+
 	server.taskManager = NewDefaultTaskManager(logger, cfg.AgentConfig.MaxConversationHistory)
 	server.messageHandler = NewDefaultMessageHandler(logger, server.taskManager, cfg)
 	server.responseSender = NewDefaultResponseSender(logger)
@@ -218,9 +274,237 @@ func NewA2AServerEnvironmentAware(cfg *config.Config, logger *zap.Logger, otel o
 	return server
 }
 
-// SetTaskHandler allows injecting a custom task handler
+// setupRateLimiter creates the rate limiter when rate limiting is enabled
+// A configuration error is kept for the startup self-check
+func (s *A2AServerImpl) setupRateLimiter() {
+	if !s.cfg.RateLimitConfig.Enable {
+		return
+	}
+
+	rateLimiter, err := middlewares.NewRateLimiter(s.logger, *s.cfg)
+	if err != nil {
+		s.logger.Error("failed to create rate limiter", zap.Error(err))
+		s.rateLimiterErr = err
+		return
+	}
+	s.rateLimiter = rateLimiter
+}
+
+// setupTokenUsage creates the tracker rolling up the tokens used by the LLM and recording them as metrics
+func (s *A2AServerImpl) setupTokenUsage() {
+	s.tokenUsage = NewTokenUsageTracker(s.otel, otel.TelemetryAttributes{
+		Provider: s.cfg.AgentConfig.Provider,
+		Model:    s.cfg.AgentConfig.Model,
+	})
+}
+
+// setupBudget creates the budget tracker when budgets are enabled
+// A configuration error is kept for the startup self-check
+func (s *A2AServerImpl) setupBudget() {
+	if !s.cfg.BudgetConfig.Enable {
+		return
+	}
+
+	budget, err := NewBudgetTracker(s.cfg.BudgetConfig, s.cfg.AgentConfig.Model)
+	if err != nil {
+		s.logger.Error("failed to create budget tracker", zap.Error(err))
+		s.budgetErr = err
+		return
+	}
+	s.budget = budget
+}
+
+// withTokenAccounting returns a copy of ctx recording the tokens used by the LLM for a task and enforcing its budgets
+func (s *A2AServerImpl) withTokenAccounting(ctx context.Context) context.Context {
+	if s.budget == nil {
+		return WithTokenUsageReporter(ctx, s.tokenUsage.Record)
+	}
+
+	ctx = WithTokenBudget(ctx, s.budget)
+	return WithTokenUsageReporter(ctx, func(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage) {
+		s.tokenUsage.Record(ctx, task, usage)
+		s.budget.Record(ctx, task, usage)
+	})
+}
+
+// wrapAgentLLMClient charges the LLM tokens of the default agent to the rate limited client
+// The agents a skill router or workflow delegates to are wrapped individually
+func (s *A2AServerImpl) wrapAgentLLMClient(agent OpenAICompatibleAgent) {
+	if !s.rateLimiter.LimitsTokens() {
+		return
+	}
+
+	if composite, ok := agent.(compositeAgent); ok {
+		for _, delegate := range composite.agents() {
+			s.wrapAgentLLMClient(delegate)
+		}
+		return
+	}
+
+	defaultAgent, ok := agent.(*DefaultOpenAICompatibleAgent)
+	if !ok || defaultAgent.llmClient == nil {
+		return
+	}
+
+	if _, wrapped := defaultAgent.llmClient.(*RateLimitedLLMClient); !wrapped {
+		defaultAgent.llmClient = NewRateLimitedLLMClient(defaultAgent.llmClient, s.rateLimiter)
+	}
+}
+
+// wrapAgentCircuitBreaker sends the LLM requests of the agent through the circuit breaker of their provider
+// The models of a composite LLM client are wrapped individually, so that an open circuit falls back to the next model
+func (s *A2AServerImpl) wrapAgentCircuitBreaker(agent OpenAICompatibleAgent) {
+	if !s.cfg.CircuitBreakerConfig.Enable || agent == nil {
+		return
+	}
+
+	if composite, ok := agent.(compositeAgent); ok {
+		for _, delegate := range composite.agents() {
+			s.wrapAgentCircuitBreaker(delegate)
+		}
+		return
+	}
+
+	defaultAgent, ok := agent.(*DefaultOpenAICompatibleAgent)
+	if !ok || defaultAgent.llmClient == nil {
+		return
+	}
+
+	defaultAgent.llmClient = s.withCircuitBreaker(defaultAgent.llmClient, defaultAgent.config)
+}
+
+// withCircuitBreaker returns the client sending its requests through the circuit breaker of their provider
+// Cached responses are answered before the circuit breaker, so that they are served while a circuit is open
+func (s *A2AServerImpl) withCircuitBreaker(client LLMClient, cfg *config.AgentConfig) LLMClient {
+	switch client := client.(type) {
+	case *CircuitBreakerLLMClient, *RateLimitedLLMClient:
+		return client
+	case *CachingLLMClient:
+		client.client = s.withCircuitBreaker(client.client, cfg)
+		return client
+	case *CassetteLLMClient:
+		if client.mode == CassetteModeRecord {
+			client.client = s.withCircuitBreaker(client.client, cfg)
+		}
+		return client
+	case *CompositeLLMClient:
+		client.wrapClients(func(provider string, client LLMClient) LLMClient {
+			return NewCircuitBreakerLLMClient(client, s.circuitBreaker(provider))
+		})
+		return client
+	case *OpenAICompatibleLLMClient:
+		return NewCircuitBreakerLLMClient(client, s.circuitBreaker(string(client.provider)))
+	default:
+		if cfg != nil && cfg.Provider != "" {
+			return NewCircuitBreakerLLMClient(client, s.circuitBreaker(strings.ToLower(cfg.Provider)))
+		}
+		return client
+	}
+}
+
+// circuitBreaker returns the circuit breaker of the LLM provider, creating it on first use
+func (s *A2AServerImpl) circuitBreaker(provider string) *CircuitBreaker {
+	s.circuitBreakersMu.Lock()
+	defer s.circuitBreakersMu.Unlock()
+
+	if s.circuitBreakers == nil {
+		s.circuitBreakers = make(map[string]*CircuitBreaker)
+	}
+	breaker, ok := s.circuitBreakers[provider]
+	if !ok {
+		breaker = NewCircuitBreaker(provider, s.cfg.CircuitBreakerConfig, s.logger)
+		s.circuitBreakers[provider] = breaker
+	}
+	return breaker
+}
+
+// CircuitBreakerStatuses returns the state of the circuit breaker of each LLM provider, sorted by provider
+func (s *A2AServerImpl) CircuitBreakerStatuses() []CircuitBreakerStatus {
+	s.circuitBreakersMu.Lock()
+	defer s.circuitBreakersMu.Unlock()
+
+	statuses := make([]CircuitBreakerStatus, 0, len(s.circuitBreakers))
+	for _, breaker := range s.circuitBreakers {
+		statuses = append(statuses, breaker.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Provider < statuses[j].Provider
+	})
+	return statuses
+}
+
+// handleHealth reports the server as degraded while the circuit breaker of an LLM provider is not closed
+func (s *A2AServerImpl) handleHealth(c *gin.Context) {
+	if !s.cfg.CircuitBreakerConfig.Enable {
+		c.JSON(http.StatusOK, gin.H{"status": adk.HealthStatusHealthy})
+		return
+	}
+
+	status := adk.HealthStatusHealthy
+	breakers := s.CircuitBreakerStatuses()
+	for _, breaker := range breakers {
+		if breaker.State != CircuitStateClosed {
+			status = adk.HealthStatusDegraded
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": status, "circuit_breakers": breakers})
+}
+
+// instrumentAgentToolBox records the tool call failures of the default toolbox with the server telemetry
+func (s *A2AServerImpl) instrumentAgentToolBox(agent OpenAICompatibleAgent) {
+	if s.otel == nil || agent == nil {
+		return
+	}
+
+	if composite, ok := agent.(compositeAgent); ok {
+		for _, delegate := range composite.agents() {
+			s.instrumentAgentToolBox(delegate)
+		}
+		return
+	}
+
+	toolBox, ok := agent.GetToolBox().(*DefaultToolBox)
+	if !ok {
+		return
+	}
+
+	attrs := otel.TelemetryAttributes{}
+	if defaultAgent, ok := agent.(*DefaultOpenAICompatibleAgent); ok && defaultAgent.config != nil {
+		attrs.Provider = defaultAgent.config.Provider
+		attrs.Model = defaultAgent.config.Model
+	}
+	toolBox.SetTelemetry(s.otel, attrs)
+}
+
+// instrumentAgentLLMClient records the attempts of the LLM requests of the agent and the agents it delegates to
+func (s *A2AServerImpl) instrumentAgentLLMClient(agent OpenAICompatibleAgent) {
+	if s.otel == nil || agent == nil {
+		return
+	}
+
+	if composite, ok := agent.(compositeAgent); ok {
+		for _, delegate := range composite.agents() {
+			s.instrumentAgentLLMClient(delegate)
+		}
+		return
+	}
+
+	defaultAgent, ok := agent.(*DefaultOpenAICompatibleAgent)
+	if !ok {
+		return
+	}
+	if client, ok := defaultAgent.llmClient.(instrumentedLLMClient); ok {
+		client.SetTelemetry(s.otel)
+	}
+}
+
+// SetTaskHandler allows injecting a custom task handler, which also processes the tasks of message/stream
 func (s *A2AServerImpl) SetTaskHandler(handler TaskHandler) {
 	s.taskHandler = handler
+	s.customTaskHandler = handler != nil
+	if messageHandler, ok := s.messageHandler.(*DefaultMessageHandler); ok {
+		messageHandler.SetTaskHandler(handler)
+	}
 }
 
 // GetTaskHandler returns the configured task handler
@@ -229,9 +513,19 @@ func (s *A2AServerImpl) GetTaskHandler() TaskHandler {
 }
 
 // SetAgent sets the OpenAI-compatible agent for processing tasks
+// A task handler set earlier with SetTaskHandler keeps processing the tasks of message/stream
 func (s *A2AServerImpl) SetAgent(agent OpenAICompatibleAgent) {
+	s.instrumentAgentLLMClient(agent)
+	s.wrapAgentCircuitBreaker(agent)
+	s.wrapAgentLLMClient(agent)
+	s.instrumentAgentToolBox(agent)
 	s.agent = agent
-	s.messageHandler = NewDefaultMessageHandlerWithAgent(s.logger, s.taskManager, agent, s.cfg)
+
+	messageHandler := NewDefaultMessageHandlerWithAgent(s.logger, s.taskManager, agent, s.cfg)
+	if s.customTaskHandler {
+		messageHandler.SetTaskHandler(s.taskHandler)
+	}
+	s.messageHandler = messageHandler
 }
 
 // GetAgent returns the configured OpenAI-compatible agent
@@ -239,11 +533,31 @@ func (s *A2AServerImpl) GetAgent() OpenAICompatibleAgent {
 	return s.agent
 }
 
+// GetTokenUsageTracker returns the tokens used by the LLM per context and principal
+func (s *A2AServerImpl) GetTokenUsageTracker() *TokenUsageTracker {
+	return s.tokenUsage
+}
+
+// GetBudgetTracker returns the LLM budgets of tasks, contexts and principals, or nil if budgets are disabled
+func (s *A2AServerImpl) GetBudgetTracker() *BudgetTracker {
+	return s.budget
+}
+
 // SetTaskResultProcessor sets the task result processor for custom business logic
 func (s *A2AServerImpl) SetTaskResultProcessor(processor TaskResultProcessor) {
 	s.taskResultProcessor = processor
 }
 
+// SetAuthorizationPolicy replaces the method and skill level authorization policy built from the configuration
+func (s *A2AServerImpl) SetAuthorizationPolicy(policy *middlewares.AuthorizationPolicy) {
+	s.authorizationPolicy = policy
+}
+
+// SetAuthenticators replaces the authenticators created from the configured schemes
+func (s *A2AServerImpl) SetAuthenticators(authenticators ...middlewares.Authenticator) {
+	s.authenticators = authenticators
+}
+
 // SetAgentName sets the agent's name dynamically
 func (s *A2AServerImpl) SetAgentName(name string) {
 	s.cfg.AgentName = name
@@ -314,7 +628,8 @@ func (s *A2AServerImpl) LoadAgentCardFromFile(filePath string, overrides map[str
 }
 
 // SetupRouter configures the HTTP router with A2A endpoints
-func (s *A2AServerImpl) setupRouter(cfg *config.Config) *gin.Engine {
+// When authentication cannot be set up the /a2a endpoint is not registered and the error is returned
+func (s *A2AServerImpl) setupRouter(cfg *config.Config) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
 	if cfg.Debug {
 		gin.SetMode(gin.DebugMode)
@@ -325,45 +640,88 @@ func (s *A2AServerImpl) setupRouter(cfg *config.Config) *gin.Engine {
 	r.Use(gin.Recovery())
 	r.Use(middlewares.LoggingMiddleware(cfg.ServerConfig.DisableHealthcheckLog))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": adk.HealthStatusHealthy})
-	})
+	r.GET("/health", s.handleHealth)
 
 	r.GET("/.well-known/agent.json", s.handleAgentInfo)
 
-	var telemetryMiddleware gin.HandlerFunc
+	handlers := make([]gin.HandlerFunc, 0, 4)
 	if s.cfg.TelemetryConfig.Enable && s.otel != nil {
 		telemetryMw, err := middlewares.NewTelemetryMiddleware(*s.cfg, s.otel, s.logger)
 		if err != nil {
 			s.logger.Error("failed to create telemetry middleware", zap.Error(err))
 		} else {
-			telemetryMiddleware = telemetryMw.Middleware()
+			handlers = append(handlers, telemetryMw.Middleware())
 		}
 	}
 
 	if !cfg.AuthConfig.Enable {
-		if telemetryMiddleware != nil {
-			r.POST("/a2a", telemetryMiddleware, s.handleA2ARequest)
-		} else {
-			r.POST("/a2a", s.handleA2ARequest)
-		}
 		s.logger.Warn("authentication is disabled, oidcAuthenticator will be nil")
-		return r
+	} else {
+		authenticators := s.authenticators
+		if len(authenticators) == 0 {
+			var err error
+			authenticators, err = middlewares.NewAuthenticators(s.logger, *s.cfg)
+			if err != nil {
+				s.logger.Error("failed to create authenticators", zap.Error(err))
+				return r, err
+			}
+		}
+		s.authenticatorChain = middlewares.NewAuthenticatorChain(s.logger, authenticators...)
+
+		s.logger.Info("authentication enabled, setting up authenticators", zap.Strings("schemes", s.authenticationSchemes()))
+		handlers = append(handlers, s.authenticatorChain.Middleware())
 	}
-	oidcAuthenticator, err := middlewares.NewOIDCAuthenticatorMiddleware(s.logger, *s.cfg)
+
+	if s.rateLimiter != nil {
+		handlers = append(handlers, s.rateLimiter.Middleware())
+	}
+
+	handlers = append(handlers, s.handleA2ARequest)
+	r.POST("/a2a", handlers...)
+
+	return r, nil
+}
+
+// Handler returns the HTTP handler of the A2A endpoints, for example to serve the server with httptest
+// Unlike Start it neither runs the startup self-check nor starts the task processor
+func (s *A2AServerImpl) Handler() (http.Handler, error) {
+	router, err := s.setupRouter(s.cfg)
 	if err != nil {
-		s.logger.Error("failed to create OIDC authenticator", zap.Error(err))
-		return r
+		return nil, err
 	}
+	return router, nil
+}
 
-	s.logger.Info("oidcAuthenticator is valid, setting up authentication")
-	if telemetryMiddleware != nil {
-		r.POST("/a2a", telemetryMiddleware, oidcAuthenticator.Middleware(), s.handleA2ARequest)
-	} else {
-		r.POST("/a2a", oidcAuthenticator.Middleware(), s.handleA2ARequest)
+// authenticationSchemes returns the names of the active authentication schemes
+func (s *A2AServerImpl) authenticationSchemes() []string {
+	if s.authenticatorChain == nil {
+		return nil
+	}
+
+	schemes := make([]string, 0, len(s.authenticatorChain.Authenticators()))
+	for _, authenticator := range s.authenticatorChain.Authenticators() {
+		schemes = append(schemes, authenticator.SchemeName())
+	}
+	return schemes
+}
+
+// clientCertificateTLSConfig returns the TLS configuration requesting client certificates when an mTLS authenticator is active
+func (s *A2AServerImpl) clientCertificateTLSConfig() *tls.Config {
+	if s.authenticatorChain == nil {
+		return nil
 	}
 
-	return r
+	for _, authenticator := range s.authenticatorChain.Authenticators() {
+		mtls, ok := authenticator.(interface{ ClientCAs() *x509.CertPool })
+		if !ok {
+			continue
+		}
+		return &tls.Config{
+			ClientCAs:  mtls.ClientCAs(),
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}
+	}
+	return nil
 }
 
 // Start starts the A2A server
@@ -372,15 +730,29 @@ func (s *A2AServerImpl) Start(ctx context.Context) error {
 		return fmt.Errorf("agent card must be configured before starting the server - use SetAgentCard() or LoadAgentCardFromFile()")
 	}
 
-	router := s.setupRouter(s.cfg)
+	router, authErr := s.setupRouter(s.cfg)
 
-	s.httpServer = &http.Server{
+	report := s.runSelfCheck(authErr)
+	s.selfCheckReport.Store(report)
+	report.Log(s.logger)
+	if err := report.Err(); err != nil {
+		if !s.cfg.ServerConfig.DisableStrictMode {
+			return fmt.Errorf("startup self-check failed: %w", err)
+		}
+		s.logger.Warn("strict mode is disabled, starting despite failed self-check", zap.Error(err))
+	}
+
+	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%s", s.cfg.ServerConfig.Port),
 		Handler:      router,
 		ReadTimeout:  s.cfg.ServerConfig.ReadTimeout,
 		WriteTimeout: s.cfg.ServerConfig.WriteTimeout,
 		IdleTimeout:  s.cfg.ServerConfig.IdleTimeout,
+		TLSConfig:    s.clientCertificateTLSConfig(),
 	}
+	s.serversMu.Lock()
+	s.httpServer = httpServer
+	s.serversMu.Unlock()
 
 	s.logger.Info("starting A2A server", zap.String("port", s.cfg.ServerConfig.Port))
 
@@ -390,16 +762,19 @@ func (s *A2AServerImpl) Start(ctx context.Context) error {
 			metricsRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 			metricsAddr := s.cfg.TelemetryConfig.MetricsConfig.Host + ":" + s.cfg.TelemetryConfig.MetricsConfig.Port
-			s.metricsServer = &http.Server{
+			metricsServer := &http.Server{
 				Addr:         metricsAddr,
 				Handler:      metricsRouter,
 				ReadTimeout:  s.cfg.TelemetryConfig.MetricsConfig.ReadTimeout,
 				WriteTimeout: s.cfg.TelemetryConfig.MetricsConfig.WriteTimeout,
 				IdleTimeout:  s.cfg.TelemetryConfig.MetricsConfig.IdleTimeout,
 			}
+			s.serversMu.Lock()
+			s.metricsServer = metricsServer
+			s.serversMu.Unlock()
 
 			s.logger.Info("starting metrics server", zap.String("port", s.cfg.TelemetryConfig.MetricsConfig.Port))
-			if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 				s.logger.Error("metrics server failed", zap.Error(err))
 			}
 		}()
@@ -408,10 +783,10 @@ func (s *A2AServerImpl) Start(ctx context.Context) error {
 	go s.StartTaskProcessor(ctx)
 
 	if s.cfg.ServerConfig.TLSConfig.Enable {
-		return s.httpServer.ListenAndServeTLS(s.cfg.ServerConfig.TLSConfig.CertPath, s.cfg.ServerConfig.TLSConfig.KeyPath)
+		return httpServer.ListenAndServeTLS(s.cfg.ServerConfig.TLSConfig.CertPath, s.cfg.ServerConfig.TLSConfig.KeyPath)
 	}
 
-	return s.httpServer.ListenAndServe()
+	return httpServer.ListenAndServe()
 }
 
 // Stop gracefully stops the A2A server
@@ -420,15 +795,19 @@ func (s *A2AServerImpl) Stop(ctx context.Context) error {
 
 	var err error
 
-	if s.httpServer != nil {
-		if shutdownErr := s.httpServer.Shutdown(ctx); shutdownErr != nil {
+	s.serversMu.Lock()
+	httpServer, metricsServer := s.httpServer, s.metricsServer
+	s.serversMu.Unlock()
+
+	if httpServer != nil {
+		if shutdownErr := httpServer.Shutdown(ctx); shutdownErr != nil {
 			s.logger.Error("error stopping HTTP server", zap.Error(shutdownErr))
 			err = shutdownErr
 		}
 	}
 
-	if s.metricsServer != nil {
-		if shutdownErr := s.metricsServer.Shutdown(ctx); shutdownErr != nil {
+	if metricsServer != nil {
+		if shutdownErr := metricsServer.Shutdown(ctx); shutdownErr != nil {
 			s.logger.Error("error stopping metrics server", zap.Error(shutdownErr))
 			if err == nil {
 				err = shutdownErr
@@ -456,8 +835,29 @@ func (s *A2AServerImpl) Stop(ctx context.Context) error {
 
 // GetAgentCard returns the agent's capabilities and metadata
 // Returns nil if no agent card has been explicitly set
+// Once authentication is set up, the security schemes of the active authenticators are added to the card
 func (s *A2AServerImpl) GetAgentCard() *adk.AgentCard {
-	return s.customAgentCard
+	if s.customAgentCard == nil || s.authenticatorChain == nil {
+		return s.customAgentCard
+	}
+
+	agentCard := *s.customAgentCard
+	schemes := make(map[string]adk.SecurityScheme, len(agentCard.SecuritySchemes))
+	for name, scheme := range agentCard.SecuritySchemes {
+		schemes[name] = scheme
+	}
+	for name, scheme := range s.authenticatorChain.SecuritySchemes() {
+		if _, exists := schemes[name]; !exists {
+			schemes[name] = scheme
+		}
+	}
+	agentCard.SecuritySchemes = schemes
+
+	if len(agentCard.Security) == 0 {
+		agentCard.Security = s.authenticatorChain.Security()
+	}
+
+	return &agentCard
 }
 
 // ProcessTask processes a task with the given message
@@ -509,6 +909,15 @@ func (s *A2AServerImpl) processQueuedTask(ctx context.Context, queuedTask *Queue
 		zap.String("task_id", task.ID),
 		zap.String("context_id", task.ContextID))
 
+	ctx = withRequestValues(ctx, queuedTask.RequestContext)
+	ctx = s.withTokenAccounting(ctx)
+	ctx = WithTaskMetadataWriter(ctx, s.taskManager.SetTaskMetadata)
+	ctx = WithTaskStatusReporter(ctx, func(ctx context.Context, task *adk.Task, status adk.TaskStatus) {
+		if err := s.taskManager.UpdateTask(task.ID, status.State, status.Message); err != nil {
+			s.logger.Error("failed to report task status", zap.Error(err), zap.String("task_id", task.ID))
+		}
+	})
+
 	err := s.taskManager.UpdateTask(task.ID, adk.TaskStateWorking, nil)
 	if err != nil {
 		s.logger.Error("failed to update task state", zap.Error(err))
@@ -608,6 +1017,12 @@ func (s *A2AServerImpl) handleA2ARequest(c *gin.Context) {
 		zap.String("method", req.Method),
 		zap.Any("id", req.ID))
 
+	principal, _ := middlewares.PrincipalFromContext(c.Request.Context())
+	if err := s.authorizationPolicy.AuthorizeMethod(principal, req.Method); err != nil {
+		s.sendPermissionDenied(c, req.ID, principal, err)
+		return
+	}
+
 	switch req.Method {
 	case "message/send":
 		s.handleMessageSend(c, req)
@@ -649,18 +1064,34 @@ func (s *A2AServerImpl) handleMessageSend(c *gin.Context, req adk.JSONRPCRequest
 		return
 	}
 
+	if !s.authorizeSkill(c, req, params) {
+		return
+	}
+
 	task, err := s.messageHandler.HandleMessageSend(c.Request.Context(), params)
 	if err != nil {
 		s.logger.Error("failed to handle message send", zap.Error(err))
+		var contextDenied *ContextAccessDeniedError
+		var taskDenied *TaskAccessDeniedError
+		var invalidOptions *InvalidLLMOptionsError
+		var notResumable *TaskNotResumableError
+		if errors.As(err, &contextDenied) || errors.As(err, &taskDenied) || errors.As(err, &invalidOptions) || errors.As(err, &notResumable) {
+			s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), err.Error())
+			return
+		}
 		s.responseSender.SendError(c, req.ID, int(ErrInternalError), err.Error())
 		return
 	}
 
 	queuedTask := &QueuedTask{
-		Task:      task,
-		RequestID: req.ID,
+		Task:           task,
+		RequestID:      req.ID,
+		RequestContext: context.WithoutCancel(c.Request.Context()),
 	}
 
+	// The task is answered as submitted, the task processor updates it once it is queued
+	response := *task
+
 	select {
 	case s.taskQueue <- queuedTask:
 		s.logger.Info("task queued for processing",
@@ -685,9 +1116,10 @@ func (s *A2AServerImpl) handleMessageSend(c *gin.Context, req adk.JSONRPCRequest
 				zap.String("task_id", task.ID),
 				zap.String("context_id", task.ContextID))
 		}
+		response = *task
 	}
 
-	s.responseSender.SendSuccess(c, req.ID, *task)
+	s.responseSender.SendSuccess(c, req.ID, response)
 }
 
 // handleMessageStream processes message/stream requests
@@ -706,13 +1138,18 @@ func (s *A2AServerImpl) handleMessageStream(c *gin.Context, req adk.JSONRPCReque
 		return
 	}
 
+	if !s.authorizeSkill(c, req, params) {
+		return
+	}
+
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("Access-Control-Allow-Headers", "Cache-Control")
 
-	ctx := c.Request.Context()
+	ctx := s.withTokenAccounting(c.Request.Context())
+	ctx = WithTaskMetadataWriter(ctx, s.taskManager.SetTaskMetadata)
 
 	responseChan := make(chan adk.SendStreamingMessageResponse, 10)
 
@@ -757,11 +1194,18 @@ func (s *A2AServerImpl) handleMessageStream(c *gin.Context, req adk.JSONRPCReque
 	if err != nil {
 		s.logger.Error("failed to handle message stream", zap.Error(err))
 
+		code := ErrInternalError
+		var invalidOptions *InvalidLLMOptionsError
+		var notResumable *TaskNotResumableError
+		if errors.As(err, &invalidOptions) || errors.As(err, &notResumable) {
+			code = ErrInvalidParams
+		}
+
 		errorResponse := adk.JSONRPCErrorResponse{
 			JSONRPC: "2.0",
 			ID:      req.ID,
 			Error: &adk.JSONRPCError{
-				Code:    int(ErrInternalError),
+				Code:    int(code),
 				Message: err.Error(),
 			},
 		}
@@ -855,6 +1299,11 @@ func (s *A2AServerImpl) handleTaskGet(c *gin.Context, req adk.JSONRPCRequest) {
 
 	s.logger.Info("retrieving task", zap.String("task_id", params.ID))
 
+	if !s.authorizeTaskAccess(c, params.ID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	task, exists := s.taskManager.GetTask(params.ID)
 	if !exists {
 		s.logger.Error("task not found", zap.String("task_id", params.ID))
@@ -887,6 +1336,11 @@ func (s *A2AServerImpl) handleTaskCancel(c *gin.Context, req adk.JSONRPCRequest)
 
 	s.logger.Info("canceling task", zap.String("task_id", params.ID))
 
+	if !s.authorizeTaskAccess(c, params.ID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	err = s.taskManager.CancelTask(params.ID)
 	if err != nil {
 		s.logger.Error("failed to cancel task",
@@ -918,7 +1372,8 @@ func (s *A2AServerImpl) handleTaskList(c *gin.Context, req adk.JSONRPCRequest) {
 
 	s.logger.Info("listing tasks")
 
-	taskList, err := s.taskManager.ListTasks(params)
+	principal, _ := middlewares.PrincipalFromContext(c.Request.Context())
+	taskList, err := s.taskManager.ListTasksForPrincipal(params, principal)
 	if err != nil {
 		s.logger.Error("failed to list tasks", zap.Error(err))
 		s.responseSender.SendError(c, req.ID, int(ErrInternalError), err.Error())
@@ -949,6 +1404,11 @@ func (s *A2AServerImpl) handleTaskPushNotificationConfigSet(c *gin.Context, req
 		zap.String("task_id", params.TaskID),
 		zap.String("url", params.PushNotificationConfig.URL))
 
+	if !s.authorizeTaskAccess(c, params.TaskID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	config, err := s.taskManager.SetTaskPushNotificationConfig(params)
 	if err != nil {
 		s.logger.Error("failed to set push notification config", zap.Error(err))
@@ -978,6 +1438,11 @@ func (s *A2AServerImpl) handleTaskPushNotificationConfigGet(c *gin.Context, req
 
 	s.logger.Info("getting push notification config for task", zap.String("task_id", params.ID))
 
+	if !s.authorizeTaskAccess(c, params.ID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	config, err := s.taskManager.GetTaskPushNotificationConfig(params)
 	if err != nil {
 		s.logger.Error("failed to get push notification config", zap.Error(err))
@@ -1007,6 +1472,11 @@ func (s *A2AServerImpl) handleTaskPushNotificationConfigList(c *gin.Context, req
 
 	s.logger.Info("listing push notification configs for task", zap.String("task_id", params.ID))
 
+	if !s.authorizeTaskAccess(c, params.ID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	configs, err := s.taskManager.ListTaskPushNotificationConfigs(params)
 	if err != nil {
 		s.logger.Error("failed to list push notification configs", zap.Error(err))
@@ -1020,6 +1490,55 @@ func (s *A2AServerImpl) handleTaskPushNotificationConfigList(c *gin.Context, req
 	s.responseSender.SendSuccess(c, req.ID, configs)
 }
 
+// authorizeSkill checks whether the authenticated principal of the request may invoke the requested agent skill
+// The request context carries the policy on so the skill a skill router selects is authorized too
+func (s *A2AServerImpl) authorizeSkill(c *gin.Context, req adk.JSONRPCRequest, params adk.MessageSendParams) bool {
+	principal, _ := middlewares.PrincipalFromContext(c.Request.Context())
+	if err := s.authorizationPolicy.AuthorizeSkill(principal, req.Method, RequestedSkillID(params)); err != nil {
+		s.sendPermissionDenied(c, req.ID, principal, err)
+		return false
+	}
+
+	c.Request = c.Request.WithContext(WithSkillAuthorizer(c.Request.Context(), func(ctx context.Context, skillID string) error {
+		principal, _ := middlewares.PrincipalFromContext(ctx)
+		return s.authorizationPolicy.AuthorizeSkill(principal, req.Method, skillID)
+	}))
+	return true
+}
+
+// sendPermissionDenied responds with a JSON-RPC error describing the unmet authorization requirement
+func (s *A2AServerImpl) sendPermissionDenied(c *gin.Context, id interface{}, principal *middlewares.Principal, err error) {
+	s.logger.Warn("request denied by authorization policy",
+		zap.Error(err),
+		zap.String("subject", principal.Owner()))
+
+	var authErr *middlewares.AuthorizationError
+	if errors.As(err, &authErr) {
+		s.responseSender.SendErrorWithData(c, id, int(ErrPermissionDenied), err.Error(), authErr)
+		return
+	}
+	s.responseSender.SendError(c, id, int(ErrPermissionDenied), err.Error())
+}
+
+// authorizeTaskAccess checks whether the authenticated principal of the request may access the task.
+// Tasks that do not exist are left to the task manager so that existing error responses are preserved.
+func (s *A2AServerImpl) authorizeTaskAccess(c *gin.Context, taskID string) bool {
+	principal, ok := middlewares.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		return true
+	}
+
+	err := s.taskManager.AuthorizeTask(taskID, principal)
+	var accessDenied *TaskAccessDeniedError
+	if errors.As(err, &accessDenied) {
+		s.logger.Warn("task access denied",
+			zap.String("task_id", taskID),
+			zap.String("subject", principal.Subject))
+		return false
+	}
+	return true
+}
+
 // handleTaskPushNotificationConfigDelete processes tasks/pushNotificationConfig/delete requests
 func (s *A2AServerImpl) handleTaskPushNotificationConfigDelete(c *gin.Context, req adk.JSONRPCRequest) {
 	var params adk.DeleteTaskPushNotificationConfigParams
@@ -1040,6 +1559,11 @@ func (s *A2AServerImpl) handleTaskPushNotificationConfigDelete(c *gin.Context, r
 		zap.String("task_id", params.ID),
 		zap.String("config_id", params.PushNotificationConfigID))
 
+	if !s.authorizeTaskAccess(c, params.ID) {
+		s.responseSender.SendError(c, req.ID, int(ErrInvalidParams), "task not found")
+		return
+	}
+
 	err = s.taskManager.DeleteTaskPushNotificationConfig(params)
 	if err != nil {
 		s.logger.Error("failed to delete push notification config", zap.Error(err))
@@ -23,6 +23,7 @@ import (
 //	  Build()
 type A2AServerBuilder interface {
 	// WithTaskHandler sets a custom task handler for processing A2A tasks.
+	// The handler also processes message/stream, streaming its events if it implements StreamingTaskHandler.
 	// If not set, a default task handler will be used.
 	WithTaskHandler(handler TaskHandler) A2AServerBuilder
 
@@ -231,7 +232,7 @@ func (b *A2AServerBuilderImpl) Build() (A2AServer, error) {
 		server.SetAgent(b.agent)
 
 		if b.taskHandler == nil {
-			server.SetTaskHandler(NewAgentTaskHandler(b.logger, b.agent))
+			server.SetTaskHandler(NewAgentTaskHandlerWithConfig(b.logger, b.agent, &b.cfg.AgentConfig))
 			b.logger.Info("configured agent task handler with openai-compatible agent")
 		}
 	}
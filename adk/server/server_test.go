@@ -9,6 +9,7 @@ import (
 
 	gin "github.com/gin-gonic/gin"
 	adk "github.com/inference-gateway/a2a/adk"
+	client "github.com/inference-gateway/a2a/adk/client"
 	server "github.com/inference-gateway/a2a/adk/server"
 	config "github.com/inference-gateway/a2a/adk/server/config"
 	mocks "github.com/inference-gateway/a2a/adk/server/mocks"
@@ -311,6 +312,35 @@ func TestDefaultA2AServer_SetDependencies(t *testing.T) {
 	assert.Nil(t, agentCard, "Expected no agent card to be set by default")
 }
 
+func TestA2AServer_SetAgent_KeepsTaskHandler(t *testing.T) {
+	a2aServer := server.NewA2AServer(&config.Config{}, zap.NewNop(), nil)
+
+	handled := 0
+	a2aServer.SetTaskHandler(taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		handled++
+		task.Status.State = adk.TaskStateCompleted
+		task.Status.Message = textMessage("assistant", "Handled")
+		return task, nil
+	}))
+
+	llmClient := &mocks.FakeLLMClient{}
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).Build()
+	require.NoError(t, err)
+	a2aServer.SetAgent(agent)
+
+	handler, err := a2aServer.Handler()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	eventChan := make(chan interface{}, 10)
+	message := textMessage("user", "Hi")
+	require.NoError(t, client.NewClient(httpServer.URL).SendTaskStreaming(context.Background(), adk.MessageSendParams{Message: *message}, eventChan))
+
+	assert.Equal(t, 1, handled, "the task handler set before the agent handles the stream")
+	assert.Zero(t, llmClient.CreateStreamingChatCompletionCallCount())
+}
+
 func TestA2AServerBuilder_UsesProvidedConfiguration(t *testing.T) {
 	partialCfg := &config.Config{
 		AgentName:        "test-custom-agent",
@@ -0,0 +1,405 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+var (
+	_ TaskHandler           = (*SkillRouter)(nil)
+	_ OpenAICompatibleAgent = (*SkillRouter)(nil)
+	_ compositeAgent        = (*SkillRouter)(nil)
+)
+
+// MetadataSkillRoutingKey is the task metadata key recording how the skill of the task was selected
+const MetadataSkillRoutingKey = "skillRouting"
+
+// Ways a skill is selected, recorded in the task metadata
+const (
+	SkillRoutingTask       = "task"
+	SkillRoutingMetadata   = "metadata"
+	SkillRoutingKeyword    = "keyword"
+	SkillRoutingClassifier = "classifier"
+	SkillRoutingDefault    = "default"
+)
+
+// SkillRoute describes where the messages of a skill are sent
+// Handler takes precedence over Agent. Without either, SystemPrompt and Model derive an agent
+// from the default agent of the router, and a route with nothing set uses the default agent.
+type SkillRoute struct {
+	Handler      TaskHandler
+	Agent        OpenAICompatibleAgent
+	SystemPrompt string
+	Model        string
+	// Keywords select the skill when they appear in the message, in addition to the skill tags
+	Keywords []string
+}
+
+// SkillClassifier picks the skill of a message when neither metadata nor keywords select one
+// It returns an empty skill ID when no skill fits
+type SkillClassifier interface {
+	ClassifySkill(ctx context.Context, message *adk.Message, skills []adk.AgentSkill) (string, error)
+}
+
+// SkillRouterOption configures a SkillRouter
+type SkillRouterOption func(*SkillRouter)
+
+// WithSkillRoute sets where the messages of the skill are sent
+func WithSkillRoute(skillID string, route SkillRoute) SkillRouterOption {
+	return func(r *SkillRouter) {
+		r.routes[skillID] = route
+	}
+}
+
+// WithSkillClassifier sets the classifier used when neither metadata nor keywords select a skill
+func WithSkillClassifier(classifier SkillClassifier) SkillRouterOption {
+	return func(r *SkillRouter) {
+		r.classifier = classifier
+	}
+}
+
+// SkillAuthorizer checks that the principal of the request may invoke the skill
+type SkillAuthorizer func(ctx context.Context, skillID string) error
+
+// skillAuthorizerContextKey is the context key of the SkillAuthorizer of the request
+type skillAuthorizerContextKey struct{}
+
+// WithSkillAuthorizer returns a copy of ctx carrying the authorizer of the skills routed for the request
+func WithSkillAuthorizer(ctx context.Context, authorizer SkillAuthorizer) context.Context {
+	return context.WithValue(ctx, skillAuthorizerContextKey{}, authorizer)
+}
+
+// authorizeRoutedSkill checks the skill with the SkillAuthorizer of ctx, any skill is allowed without one
+func authorizeRoutedSkill(ctx context.Context, skillID string) error {
+	authorizer, ok := ctx.Value(skillAuthorizerContextKey{}).(SkillAuthorizer)
+	if !ok || authorizer == nil || skillID == "" {
+		return nil
+	}
+	return authorizer(ctx, skillID)
+}
+
+// skillTarget is the resolved target of a skill
+type skillTarget struct {
+	handler TaskHandler
+	agent   OpenAICompatibleAgent
+}
+
+// SkillRouter dispatches each message to the handler or agent of the agent card skill it targets
+// The skill is taken from the task if it was routed before, from the skillId message metadata,
+// from the skill tags and route keywords found in the message, or from the classifier, in that order.
+// Messages without a skill go to the default agent. The decision is recorded in the task metadata.
+type SkillRouter struct {
+	logger       *zap.Logger
+	skills       []adk.AgentSkill
+	defaultAgent OpenAICompatibleAgent
+	routes       map[string]SkillRoute
+	targets      map[string]skillTarget
+	classifier   SkillClassifier
+}
+
+// NewSkillRouter creates a router for the skills of an agent card
+// The default agent, which may be nil, handles messages without a skill or skill route
+func NewSkillRouter(logger *zap.Logger, skills []adk.AgentSkill, defaultAgent OpenAICompatibleAgent, opts ...SkillRouterOption) (*SkillRouter, error) {
+	r := &SkillRouter{
+		logger:       logger,
+		skills:       skills,
+		defaultAgent: defaultAgent,
+		routes:       make(map[string]SkillRoute),
+		targets:      make(map[string]skillTarget),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	for skillID, route := range r.routes {
+		if _, exists := r.skill(skillID); !exists {
+			return nil, fmt.Errorf("route for unknown skill %q", skillID)
+		}
+
+		target, err := r.resolveRoute(route)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route for skill %q: %w", skillID, err)
+		}
+		r.targets[skillID] = target
+	}
+
+	return r, nil
+}
+
+// resolveRoute returns the target of a route, deriving an agent from the default agent if needed
+func (r *SkillRouter) resolveRoute(route SkillRoute) (skillTarget, error) {
+	switch {
+	case route.Handler != nil:
+		return skillTarget{handler: route.Handler}, nil
+	case route.Agent != nil:
+		return skillTarget{agent: route.Agent}, nil
+	case route.SystemPrompt == "" && route.Model == "":
+		return skillTarget{agent: r.defaultAgent}, nil
+	}
+
+	defaultAgent, ok := r.defaultAgent.(*DefaultOpenAICompatibleAgent)
+	if !ok {
+		return skillTarget{}, fmt.Errorf("a system prompt or model override requires a default agent built with the AgentBuilder")
+	}
+
+	agent, err := defaultAgent.withOverrides(route.SystemPrompt, route.Model)
+	if err != nil {
+		return skillTarget{}, err
+	}
+	return skillTarget{agent: agent}, nil
+}
+
+// skill returns the skill with the given ID
+func (r *SkillRouter) skill(skillID string) (adk.AgentSkill, bool) {
+	for _, skill := range r.skills {
+		if skill.ID == skillID {
+			return skill, true
+		}
+	}
+	return adk.AgentSkill{}, false
+}
+
+// Route selects the skill of the message and records the decision in the task metadata
+// It returns the selected skill ID, empty if the message goes to the default agent,
+// or the error of the SkillAuthorizer of ctx if the principal may not invoke the skill
+func (r *SkillRouter) Route(ctx context.Context, task *adk.Task, message *adk.Message) (string, error) {
+	skillID, routing := r.selectSkill(ctx, task, message)
+
+	if err := authorizeRoutedSkill(ctx, skillID); err != nil {
+		r.logger.Warn("routed skill denied",
+			zap.Error(err),
+			zap.String("task_id", task.ID),
+			zap.String("skill_id", skillID),
+			zap.String("routing", routing))
+		return "", err
+	}
+
+	if skillID != "" {
+		SetTaskMetadata(ctx, task, MetadataSkillIDKey, skillID)
+	}
+	SetTaskMetadata(ctx, task, MetadataSkillRoutingKey, routing)
+
+	r.logger.Debug("message routed",
+		zap.String("task_id", task.ID),
+		zap.String("skill_id", skillID),
+		zap.String("routing", routing))
+	return skillID, nil
+}
+
+// selectSkill returns the skill of the message and how it was selected
+func (r *SkillRouter) selectSkill(ctx context.Context, task *adk.Task, message *adk.Message) (string, string) {
+	if skillID, ok := task.Metadata[MetadataSkillIDKey].(string); ok && skillID != "" {
+		if _, exists := r.skill(skillID); exists {
+			return skillID, SkillRoutingTask
+		}
+	}
+
+	if message == nil {
+		return "", SkillRoutingDefault
+	}
+
+	if skillID := messageSkillID(message); skillID != "" {
+		if _, exists := r.skill(skillID); exists {
+			return skillID, SkillRoutingMetadata
+		}
+		r.logger.Warn("requested skill not found, routing by content", zap.String("skill_id", skillID))
+	}
+
+	if skillID := r.matchKeywords(messageText(message)); skillID != "" {
+		return skillID, SkillRoutingKeyword
+	}
+
+	if r.classifier != nil && len(r.skills) > 0 {
+		skillID, err := r.classifier.ClassifySkill(ctx, message, r.skills)
+		if err != nil {
+			r.logger.Error("skill classification failed", zap.Error(err))
+		} else if _, exists := r.skill(skillID); exists {
+			return skillID, SkillRoutingClassifier
+		}
+	}
+
+	return "", SkillRoutingDefault
+}
+
+// wordPattern matches the words keywords are compared on
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// normalizeWords lowercases the text and separates its words by single spaces, padded on both ends
+func normalizeWords(text string) string {
+	return " " + strings.Join(wordPattern.FindAllString(strings.ToLower(text), -1), " ") + " "
+}
+
+// matchKeywords returns the skill whose tags and keywords appear most often in the text
+// No skill is returned when none matches or several match equally well
+func (r *SkillRouter) matchKeywords(text string) string {
+	words := normalizeWords(text)
+
+	bestSkill, bestScore, tied := "", 0, false
+	for _, skill := range r.skills {
+		terms := append(append([]string{}, skill.Tags...), r.routes[skill.ID].Keywords...)
+		score := 0
+		for _, term := range terms {
+			normalized := normalizeWords(term)
+			if strings.TrimSpace(normalized) != "" && strings.Contains(words, normalized) {
+				score++
+			}
+		}
+
+		switch {
+		case score > bestScore:
+			bestSkill, bestScore, tied = skill.ID, score, false
+		case score > 0 && score == bestScore:
+			tied = true
+		}
+	}
+
+	if tied {
+		return ""
+	}
+	return bestSkill
+}
+
+// target returns the handler or agent of a skill, the default agent if the skill has no route
+func (r *SkillRouter) target(skillID string) skillTarget {
+	if target, exists := r.targets[skillID]; exists {
+		return target
+	}
+	return skillTarget{agent: r.defaultAgent}
+}
+
+// HandleTask routes the message and processes the task with the handler or agent of the selected skill
+// A task routed to a skill the principal may not invoke fails with the permission error
+func (r *SkillRouter) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	skillID, err := r.Route(ctx, task, message)
+	if err != nil {
+		return failedTask(task, err.Error()), nil
+	}
+	target := r.target(skillID)
+
+	switch {
+	case target.handler != nil:
+		return target.handler.HandleTask(ctx, task, message)
+	case target.agent != nil:
+		return target.agent.ProcessTask(ctx, task, message)
+	}
+
+	r.logger.Error("no handler for skill", zap.String("skill_id", skillID), zap.String("task_id", task.ID))
+	return failedTask(task, fmt.Sprintf("No handler configured for skill %q", skillID)), nil
+}
+
+// ProcessTask routes the message like HandleTask, so the router can be used as the server agent
+func (r *SkillRouter) ProcessTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return r.HandleTask(ctx, task, message)
+}
+
+// RouteAgent routes the message and returns the agent streaming the response
+// Skills routed to a TaskHandler cannot stream and use the default agent
+func (r *SkillRouter) RouteAgent(ctx context.Context, task *adk.Task, message *adk.Message) (OpenAICompatibleAgent, error) {
+	skillID, err := r.Route(ctx, task, message)
+	if err != nil {
+		return nil, err
+	}
+
+	target := r.target(skillID)
+	if target.agent == nil {
+		r.logger.Debug("skill has no agent to stream with, using the default agent", zap.String("skill_id", skillID))
+		return r.defaultAgent, nil
+	}
+	return target.agent, nil
+}
+
+// agents returns the default agent and the agents of the skill routes
+func (r *SkillRouter) agents() []OpenAICompatibleAgent {
+	agents := make([]OpenAICompatibleAgent, 0, len(r.targets)+1)
+	if r.defaultAgent != nil {
+		agents = append(agents, r.defaultAgent)
+	}
+
+	skillIDs := make([]string, 0, len(r.targets))
+	for skillID := range r.targets {
+		skillIDs = append(skillIDs, skillID)
+	}
+	sort.Strings(skillIDs)
+	for _, skillID := range skillIDs {
+		if agent := r.targets[skillID].agent; agent != nil && agent != r.defaultAgent {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
+// GetLLMClient returns the LLM client of the default agent
+func (r *SkillRouter) GetLLMClient() LLMClient {
+	if r.defaultAgent == nil {
+		return nil
+	}
+	return r.defaultAgent.GetLLMClient()
+}
+
+// GetToolBox returns the tool box of the default agent
+func (r *SkillRouter) GetToolBox() ToolBox {
+	if r.defaultAgent == nil {
+		return nil
+	}
+	return r.defaultAgent.GetToolBox()
+}
+
+// GetSystemPrompt returns the system prompt of the default agent
+func (r *SkillRouter) GetSystemPrompt() string {
+	if r.defaultAgent == nil {
+		return ""
+	}
+	return r.defaultAgent.GetSystemPrompt()
+}
+
+// LLMSkillClassifier asks an LLM which skill a message targets
+type LLMSkillClassifier struct {
+	logger    *zap.Logger
+	llmClient LLMClient
+}
+
+// NewLLMSkillClassifier creates a skill classifier using the LLM client
+func NewLLMSkillClassifier(logger *zap.Logger, llmClient LLMClient) *LLMSkillClassifier {
+	return &LLMSkillClassifier{
+		logger:    logger,
+		llmClient: llmClient,
+	}
+}
+
+// ClassifySkill asks the LLM to answer with the ID of the skill fitting the message, or none
+func (c *LLMSkillClassifier) ClassifySkill(ctx context.Context, message *adk.Message, skills []adk.AgentSkill) (string, error) {
+	var prompt strings.Builder
+	prompt.WriteString("Classify the user message into one of the following skills. ")
+	prompt.WriteString("Answer with the skill id only, or none if no skill fits.\n\nSkills:\n")
+	for _, skill := range skills {
+		fmt.Fprintf(&prompt, "- %s: %s - %s\n", skill.ID, skill.Name, skill.Description)
+	}
+
+	response, err := c.llmClient.CreateChatCompletion(ctx, []sdk.Message{
+		{Role: sdk.System, Content: prompt.String()},
+		{Role: sdk.User, Content: messageText(message)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to classify skill: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("failed to classify skill: no response received from llm")
+	}
+
+	answer := strings.Trim(strings.TrimSpace(response.Choices[0].Message.Content), "`'\".")
+	for _, skill := range skills {
+		if strings.EqualFold(answer, skill.ID) {
+			return skill.ID, nil
+		}
+	}
+
+	c.logger.Debug("llm selected no skill", zap.String("answer", answer))
+	return "", nil
+}
@@ -0,0 +1,311 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var routerSkills = []adk.AgentSkill{
+	{ID: "weather", Name: "Weather", Description: "Weather forecasts", Tags: []string{"weather", "forecast"}},
+	{ID: "travel", Name: "Travel", Description: "Book flights and hotels", Tags: []string{"flight", "hotel"}},
+	{ID: "support", Name: "Support", Description: "Answer support questions", Tags: []string{"support"}},
+}
+
+func routerMessage(text string, metadata map[string]interface{}) *adk.Message {
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: "msg-1",
+		Role:      "user",
+		Metadata:  metadata,
+		Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": text}},
+	}
+}
+
+func completionResponse(content string) *sdk.CreateChatCompletionResponse {
+	return &sdk.CreateChatCompletionResponse{
+		Choices: []sdk.ChatCompletionChoice{{Message: sdk.Message{Role: sdk.Assistant, Content: content}}},
+	}
+}
+
+func TestSkillRouter_Route(t *testing.T) {
+	tests := []struct {
+		name            string
+		taskMetadata    map[string]interface{}
+		message         *adk.Message
+		classifierSkill string
+		classifierErr   error
+		expectedSkill   string
+		expectedRouting string
+	}{
+		{
+			name:            "explicit skill metadata",
+			message:         routerMessage("Is it going to rain?", map[string]interface{}{server.MetadataSkillIDKey: "travel"}),
+			expectedSkill:   "travel",
+			expectedRouting: server.SkillRoutingMetadata,
+		},
+		{
+			name:            "unknown skill metadata falls back to keywords",
+			message:         routerMessage("What is the weather forecast?", map[string]interface{}{server.MetadataSkillIDKey: "unknown"}),
+			expectedSkill:   "weather",
+			expectedRouting: server.SkillRoutingKeyword,
+		},
+		{
+			name:            "matches skill tags",
+			message:         routerMessage("Book a FLIGHT to Paris", nil),
+			expectedSkill:   "travel",
+			expectedRouting: server.SkillRoutingKeyword,
+		},
+		{
+			name:            "matches route keywords",
+			message:         routerMessage("I need a refund", nil),
+			expectedSkill:   "support",
+			expectedRouting: server.SkillRoutingKeyword,
+		},
+		{
+			name:            "matches whole words only",
+			message:         routerMessage("The weatherman called", nil),
+			expectedRouting: server.SkillRoutingDefault,
+		},
+		{
+			name:            "tie is resolved by the classifier",
+			message:         routerMessage("Weather for my flight", nil),
+			classifierSkill: "travel",
+			expectedSkill:   "travel",
+			expectedRouting: server.SkillRoutingClassifier,
+		},
+		{
+			name:            "classifier without a fitting skill",
+			message:         routerMessage("Tell me a joke", nil),
+			expectedRouting: server.SkillRoutingDefault,
+		},
+		{
+			name:            "classifier failure",
+			message:         routerMessage("Tell me a joke", nil),
+			classifierErr:   errors.New("llm unavailable"),
+			expectedRouting: server.SkillRoutingDefault,
+		},
+		{
+			name:            "task keeps its skill",
+			taskMetadata:    map[string]interface{}{server.MetadataSkillIDKey: "weather"},
+			message:         routerMessage("Book a flight", nil),
+			expectedSkill:   "weather",
+			expectedRouting: server.SkillRoutingTask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classifier := &fakeSkillClassifier{skillID: tt.classifierSkill, err: tt.classifierErr}
+			router, err := server.NewSkillRouter(zap.NewNop(), routerSkills, nil,
+				server.WithSkillRoute("support", server.SkillRoute{Keywords: []string{"refund"}}),
+				server.WithSkillClassifier(classifier))
+			require.NoError(t, err)
+
+			task := &adk.Task{ID: "task-1", Metadata: tt.taskMetadata}
+			skillID, err := router.Route(context.Background(), task, tt.message)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedSkill, skillID)
+			assert.Equal(t, tt.expectedRouting, task.Metadata[server.MetadataSkillRoutingKey])
+			if tt.expectedSkill != "" {
+				assert.Equal(t, tt.expectedSkill, task.Metadata[server.MetadataSkillIDKey])
+			}
+		})
+	}
+}
+
+type fakeSkillClassifier struct {
+	skillID string
+	err     error
+}
+
+func (c *fakeSkillClassifier) ClassifySkill(ctx context.Context, message *adk.Message, skills []adk.AgentSkill) (string, error) {
+	return c.skillID, c.err
+}
+
+func TestSkillRouter_HandleTask(t *testing.T) {
+	travelHandler := &mocks.FakeTaskHandler{}
+	travelHandler.HandleTaskStub = func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		task.Status.State = adk.TaskStateCompleted
+		return task, nil
+	}
+
+	defaultLLM := &mocks.FakeLLMClient{}
+	defaultLLM.CreateChatCompletionReturns(completionResponse("default answer"), nil)
+	defaultAgent, err := server.NewAgentBuilder(zap.NewNop()).
+		WithLLMClient(defaultLLM).
+		WithSystemPrompt("You are a generalist.").
+		Build()
+	require.NoError(t, err)
+
+	router, err := server.NewSkillRouter(zap.NewNop(), routerSkills, defaultAgent,
+		server.WithSkillRoute("travel", server.SkillRoute{Handler: travelHandler}),
+		server.WithSkillRoute("weather", server.SkillRoute{SystemPrompt: "You are a meteorologist."}))
+	require.NoError(t, err)
+
+	t.Run("handler route", func(t *testing.T) {
+		task := &adk.Task{ID: "task-1"}
+		result, err := router.HandleTask(context.Background(), task, routerMessage("Book a hotel", nil))
+		require.NoError(t, err)
+		assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+		assert.Equal(t, 1, travelHandler.HandleTaskCallCount())
+		assert.Equal(t, "travel", result.Metadata[server.MetadataSkillIDKey])
+	})
+
+	t.Run("system prompt override", func(t *testing.T) {
+		task := &adk.Task{ID: "task-2"}
+		_, err := router.ProcessTask(context.Background(), task, routerMessage("Weather in Oslo?", nil))
+		require.NoError(t, err)
+
+		_, messages, _ := defaultLLM.CreateChatCompletionArgsForCall(defaultLLM.CreateChatCompletionCallCount() - 1)
+		assert.Equal(t, "You are a meteorologist.", messages[0].Content)
+		assert.Equal(t, "You are a generalist.", defaultAgent.GetSystemPrompt(), "the default agent is not modified")
+	})
+
+	t.Run("default agent", func(t *testing.T) {
+		task := &adk.Task{ID: "task-3"}
+		result, err := router.HandleTask(context.Background(), task, routerMessage("Hello", nil))
+		require.NoError(t, err)
+		assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+		assert.Equal(t, server.SkillRoutingDefault, result.Metadata[server.MetadataSkillRoutingKey])
+
+		_, messages, _ := defaultLLM.CreateChatCompletionArgsForCall(defaultLLM.CreateChatCompletionCallCount() - 1)
+		assert.Equal(t, "You are a generalist.", messages[0].Content)
+	})
+}
+
+func TestSkillRouter_AuthorizesRoutedSkill(t *testing.T) {
+	travelHandler := &mocks.FakeTaskHandler{}
+	travelHandler.HandleTaskStub = func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		task.Status.State = adk.TaskStateCompleted
+		return task, nil
+	}
+	router, err := server.NewSkillRouter(zap.NewNop(), routerSkills, nil,
+		server.WithSkillRoute("travel", server.SkillRoute{Handler: travelHandler}))
+	require.NoError(t, err)
+
+	policy := middlewares.NewAuthorizationPolicy(config.AuthConfig{SkillScopes: map[string]string{"travel": "travel:book"}})
+	authorizedContext := func(principal *middlewares.Principal) context.Context {
+		ctx := middlewares.WithPrincipal(context.Background(), principal)
+		return server.WithSkillAuthorizer(ctx, func(ctx context.Context, skillID string) error {
+			principal, _ := middlewares.PrincipalFromContext(ctx)
+			return policy.AuthorizeSkill(principal, "message/send", skillID)
+		})
+	}
+
+	tests := []struct {
+		name          string
+		principal     *middlewares.Principal
+		expectedState adk.TaskState
+		expectedText  string
+	}{
+		{
+			name:          "principal without the skill scope",
+			principal:     &middlewares.Principal{Subject: "alice"},
+			expectedState: adk.TaskStateFailed,
+			expectedText:  "permission denied for skill travel",
+		},
+		{
+			name:          "principal with the skill scope",
+			principal:     &middlewares.Principal{Subject: "bob", Scopes: []string{"travel:book"}},
+			expectedState: adk.TaskStateCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := travelHandler.HandleTaskCallCount()
+			ctx := authorizedContext(tt.principal)
+
+			result, err := router.HandleTask(ctx, &adk.Task{ID: "task-1"}, routerMessage("Book a hotel", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedState, result.Status.State)
+
+			streamed, err := server.NewAgentTaskHandler(zap.NewNop(), router).
+				HandleStreamingTask(ctx, &adk.Task{ID: "task-2"}, routerMessage("Book a hotel", nil), server.NopStreamEventEmitter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedState, streamed.Status.State)
+
+			if tt.expectedText != "" {
+				assert.Equal(t, tt.expectedText, textOf(result.Status.Message))
+				assert.Equal(t, tt.expectedText, textOf(streamed.Status.Message))
+				assert.Equal(t, calls, travelHandler.HandleTaskCallCount(), "the handler of a denied skill is not called")
+			}
+		})
+	}
+}
+
+func TestSkillRouter_NoDefaultAgent(t *testing.T) {
+	router, err := server.NewSkillRouter(zap.NewNop(), routerSkills, nil)
+	require.NoError(t, err)
+
+	result, err := router.HandleTask(context.Background(), &adk.Task{ID: "task-1"}, routerMessage("Hello", nil))
+	require.NoError(t, err)
+	assert.Equal(t, adk.TaskStateFailed, result.Status.State)
+}
+
+func TestNewSkillRouter_InvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name          string
+		option        server.SkillRouterOption
+		expectedError string
+	}{
+		{
+			name:          "unknown skill",
+			option:        server.WithSkillRoute("cooking", server.SkillRoute{SystemPrompt: "You are a chef."}),
+			expectedError: `route for unknown skill "cooking"`,
+		},
+		{
+			name:          "override without default agent",
+			option:        server.WithSkillRoute("weather", server.SkillRoute{Model: "gpt-4o"}),
+			expectedError: "requires a default agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.NewSkillRouter(zap.NewNop(), routerSkills, nil, tt.option)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestLLMSkillClassifier(t *testing.T) {
+	tests := []struct {
+		name          string
+		answer        string
+		expectedSkill string
+	}{
+		{name: "skill id", answer: "travel", expectedSkill: "travel"},
+		{name: "quoted and cased", answer: " `Weather`.", expectedSkill: "weather"},
+		{name: "none", answer: "none", expectedSkill: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llmClient := &mocks.FakeLLMClient{}
+			llmClient.CreateChatCompletionReturns(completionResponse(tt.answer), nil)
+
+			classifier := server.NewLLMSkillClassifier(zap.NewNop(), llmClient)
+			skillID, err := classifier.ClassifySkill(context.Background(), routerMessage("Plan my trip", nil), routerSkills)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSkill, skillID)
+
+			_, messages, _ := llmClient.CreateChatCompletionArgsForCall(0)
+			assert.Contains(t, messages[0].Content, "- travel: Travel - Book flights and hotels")
+			assert.Equal(t, "Plan my trip", messages[1].Content)
+		})
+	}
+}
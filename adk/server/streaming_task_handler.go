@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	adk "github.com/inference-gateway/a2a/adk"
+)
+
+// StreamEventEmitter sends the events of a task processed for message/stream to the client
+type StreamEventEmitter interface {
+	// EmitStatus sends a status update of the task, a final status ends the stream
+	EmitStatus(ctx context.Context, status adk.TaskStatus, final bool) error
+
+	// EmitArtifact sends an artifact of the task, lastChunk is false while more of the artifact follows
+	EmitArtifact(ctx context.Context, artifact adk.Artifact, lastChunk bool) error
+
+	// EmitChunk sends content generated by the agent as a working status update
+	EmitChunk(ctx context.Context, text string) error
+}
+
+// StreamingTaskHandler is a TaskHandler that also streams the progress of the tasks of message/stream
+// Handlers implementing only TaskHandler are streamed through the status updates they report with
+// ReportTaskStatus and the artifacts and final state of the task they return
+type StreamingTaskHandler interface {
+	TaskHandler
+
+	// HandleStreamingTask processes a task, emitting its progress, and returns the updated task
+	// The final status update is sent from the returned task unless the handler emitted one,
+	// artifacts of the returned task that were not emitted are sent before it
+	HandleStreamingTask(ctx context.Context, task *adk.Task, message *adk.Message, emitter StreamEventEmitter) (*adk.Task, error)
+}
+
+// NopStreamEventEmitter discards the events, a StreamingTaskHandler can process tasks of message/send with it
+var NopStreamEventEmitter StreamEventEmitter = nopStreamEventEmitter{}
+
+type nopStreamEventEmitter struct{}
+
+func (nopStreamEventEmitter) EmitStatus(ctx context.Context, status adk.TaskStatus, final bool) error {
+	return nil
+}
+
+func (nopStreamEventEmitter) EmitArtifact(ctx context.Context, artifact adk.Artifact, lastChunk bool) error {
+	return nil
+}
+
+func (nopStreamEventEmitter) EmitChunk(ctx context.Context, text string) error {
+	return nil
+}
+
+// taskHandlerStreamer streams a TaskHandler that does not implement StreamingTaskHandler
+type taskHandlerStreamer struct {
+	TaskHandler
+}
+
+// HandleStreamingTask processes the task with HandleTask, emitting the status updates it reports
+func (s taskHandlerStreamer) HandleStreamingTask(ctx context.Context, task *adk.Task, message *adk.Message, emitter StreamEventEmitter) (*adk.Task, error) {
+	reporter := func(ctx context.Context, _ *adk.Task, status adk.TaskStatus) {
+		_ = emitter.EmitStatus(ctx, status, false)
+	}
+	return s.HandleTask(WithTaskStatusReporter(ctx, reporter), task, message)
+}
+
+// asStreamingTaskHandler returns the handler streaming the tasks of the task handler,
+// nil if no task handler is configured
+func asStreamingTaskHandler(handler TaskHandler) StreamingTaskHandler {
+	switch handler := handler.(type) {
+	case nil, *DefaultTaskHandler:
+		return nil
+	case StreamingTaskHandler:
+		return handler
+	default:
+		return taskHandlerStreamer{TaskHandler: handler}
+	}
+}
+
+var _ StreamEventEmitter = (*channelStreamEventEmitter)(nil)
+
+// channelStreamEventEmitter sends the events of a task to the response channel of message/stream
+type channelStreamEventEmitter struct {
+	task         *adk.Task
+	responseChan chan<- adk.SendStreamingMessageResponse
+	timestamp    func() string
+
+	mu        sync.Mutex
+	final     *adk.TaskStatus
+	artifacts map[string]bool
+}
+
+// newChannelStreamEventEmitter creates an emitter sending the events of the task to the response channel
+func newChannelStreamEventEmitter(task *adk.Task, responseChan chan<- adk.SendStreamingMessageResponse, timestamp func() string) *channelStreamEventEmitter {
+	return &channelStreamEventEmitter{
+		task:         task,
+		responseChan: responseChan,
+		timestamp:    timestamp,
+		artifacts:    make(map[string]bool),
+	}
+}
+
+// EmitStatus implements StreamEventEmitter.EmitStatus, the current time is used if the status has no timestamp
+func (e *channelStreamEventEmitter) EmitStatus(ctx context.Context, status adk.TaskStatus, final bool) error {
+	if status.Timestamp == nil {
+		status.Timestamp = StringPtr(e.timestamp())
+	}
+
+	e.mu.Lock()
+	if e.final != nil {
+		e.mu.Unlock()
+		return fmt.Errorf("stream of task %s already ended", e.task.ID)
+	}
+	if final {
+		e.final = &status
+	}
+	e.mu.Unlock()
+
+	return e.send(ctx, adk.TaskStatusUpdateEvent{
+		Kind:      "status-update",
+		TaskID:    e.task.ID,
+		ContextID: e.task.ContextID,
+		Status:    status,
+		Final:     final,
+	})
+}
+
+// EmitArtifact implements StreamEventEmitter.EmitArtifact
+func (e *channelStreamEventEmitter) EmitArtifact(ctx context.Context, artifact adk.Artifact, lastChunk bool) error {
+	e.mu.Lock()
+	if e.final != nil {
+		e.mu.Unlock()
+		return fmt.Errorf("stream of task %s already ended", e.task.ID)
+	}
+	e.artifacts[artifact.ArtifactID] = true
+	e.mu.Unlock()
+
+	return e.send(ctx, adk.TaskArtifactUpdateEvent{
+		Kind:      "artifact-update",
+		TaskID:    e.task.ID,
+		ContextID: e.task.ContextID,
+		Artifact:  artifact,
+		LastChunk: &lastChunk,
+	})
+}
+
+// EmitChunk implements StreamEventEmitter.EmitChunk
+func (e *channelStreamEventEmitter) EmitChunk(ctx context.Context, text string) error {
+	return e.EmitStatus(ctx, adk.TaskStatus{
+		State: adk.TaskStateWorking,
+		Message: &adk.Message{
+			Kind:      "message",
+			MessageID: fmt.Sprintf("stream-chunk-%s", e.task.ID),
+			Role:      "assistant",
+			Parts: []adk.Part{
+				map[string]interface{}{
+					"kind": "text",
+					"text": text,
+				},
+			},
+			TaskID:    &e.task.ID,
+			ContextID: &e.task.ContextID,
+		},
+	}, false)
+}
+
+// finalStatus returns the final status emitted, nil if the stream did not end yet
+func (e *channelStreamEventEmitter) finalStatus() *adk.TaskStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.final
+}
+
+// emitted reports whether the artifact was emitted
+func (e *channelStreamEventEmitter) emitted(artifactID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.artifacts[artifactID]
+}
+
+// send sends the event unless the request is canceled
+func (e *channelStreamEventEmitter) send(ctx context.Context, event adk.SendStreamingMessageResponse) error {
+	select {
+	case e.responseChan <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,164 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// streamWithHandler streams a message with the task handler and returns the events and the stored task
+func streamWithHandler(t *testing.T, handler server.TaskHandler) ([]adk.SendStreamingMessageResponse, *adk.Task) {
+	logger := zap.NewNop()
+	taskManager := server.NewDefaultTaskManager(logger, 10)
+	messageHandler := server.NewDefaultMessageHandler(logger, taskManager, &config.Config{})
+	messageHandler.SetTaskHandler(handler)
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 20)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	contextID := "test-context"
+	message := textMessage("user", "Summarize the report")
+	message.ContextID = &contextID
+	require.NoError(t, messageHandler.HandleMessageStream(ctx, adk.MessageSendParams{Message: *message}, responseChan))
+	close(responseChan)
+
+	var events []adk.SendStreamingMessageResponse
+	for event := range responseChan {
+		events = append(events, event)
+	}
+	require.NotEmpty(t, events)
+
+	first, ok := events[0].(adk.TaskStatusUpdateEvent)
+	require.True(t, ok)
+	task, exists := taskManager.GetTask(first.TaskID)
+	require.True(t, exists)
+	return events, task
+}
+
+// eventLabels names the events by kind, with the state of status updates and the text or artifact they carry
+func eventLabels(events []adk.SendStreamingMessageResponse) []string {
+	labels := make([]string, 0, len(events))
+	for _, event := range events {
+		switch event := event.(type) {
+		case adk.TaskStatusUpdateEvent:
+			label := string(event.Status.State)
+			if text := textOf(event.Status.Message); text != "" {
+				label += ":" + text
+			}
+			if event.Final {
+				label += " (final)"
+			}
+			labels = append(labels, label)
+		case adk.TaskArtifactUpdateEvent:
+			labels = append(labels, "artifact:"+event.Artifact.ArtifactID)
+		}
+	}
+	return labels
+}
+
+func TestDefaultMessageHandler_HandleMessageStream_StreamingTaskHandler(t *testing.T) {
+	report := adk.Artifact{ArtifactID: "report", Parts: []adk.Part{map[string]interface{}{"kind": "text", "text": "Q3 summary"}}}
+	summary := adk.Artifact{ArtifactID: "summary", Parts: []adk.Part{map[string]interface{}{"kind": "text", "text": "Revenue grew"}}}
+
+	tests := []struct {
+		name           string
+		handle         func(ctx context.Context, task *adk.Task, message *adk.Message, emitter server.StreamEventEmitter) (*adk.Task, error)
+		expectedEvents []string
+		expectedState  adk.TaskState
+	}{
+		{
+			name: "final status is sent from the returned task",
+			handle: func(ctx context.Context, task *adk.Task, message *adk.Message, emitter server.StreamEventEmitter) (*adk.Task, error) {
+				require.NoError(t, emitter.EmitChunk(ctx, "Reading "))
+				require.NoError(t, emitter.EmitChunk(ctx, "the report"))
+				require.NoError(t, emitter.EmitArtifact(ctx, report, true))
+				task.Artifacts = append(task.Artifacts, report, summary)
+				task.Status.Message = textMessage("assistant", "Done")
+				return task, nil
+			},
+			expectedEvents: []string{
+				"working:Summarize the report",
+				"working:Reading ",
+				"working:the report",
+				"artifact:report",
+				"artifact:summary",
+				"completed:Done (final)",
+			},
+			expectedState: adk.TaskStateCompleted,
+		},
+		{
+			name: "final status emitted by the handler ends the stream",
+			handle: func(ctx context.Context, task *adk.Task, message *adk.Message, emitter server.StreamEventEmitter) (*adk.Task, error) {
+				require.NoError(t, emitter.EmitStatus(ctx, adk.TaskStatus{
+					State:   adk.TaskStateInputRequired,
+					Message: textMessage("assistant", "Which quarter?"),
+				}, true))
+				assert.Error(t, emitter.EmitChunk(ctx, "too late"), "nothing is sent after the final status")
+				task.Artifacts = append(task.Artifacts, summary)
+				return task, nil
+			},
+			expectedEvents: []string{
+				"working:Summarize the report",
+				"input-required:Which quarter? (final)",
+			},
+			expectedState: adk.TaskStateInputRequired,
+		},
+		{
+			name: "handler error fails the task",
+			handle: func(ctx context.Context, task *adk.Task, message *adk.Message, emitter server.StreamEventEmitter) (*adk.Task, error) {
+				require.NoError(t, emitter.EmitChunk(ctx, "Reading"))
+				return nil, errors.New("report not found")
+			},
+			expectedEvents: []string{
+				"working:Summarize the report",
+				"working:Reading",
+				"failed:Task processing failed: report not found (final)",
+			},
+			expectedState: adk.TaskStateFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &mocks.FakeStreamingTaskHandler{}
+			handler.HandleStreamingTaskStub = tt.handle
+
+			events, task := streamWithHandler(t, handler)
+
+			assert.Equal(t, tt.expectedEvents, eventLabels(events))
+			assert.Equal(t, tt.expectedState, task.Status.State)
+			assert.Equal(t, 1, handler.HandleStreamingTaskCallCount())
+			assert.Zero(t, handler.HandleTaskCallCount(), "streaming handlers are not asked to process the task again")
+		})
+	}
+}
+
+func TestDefaultMessageHandler_HandleMessageStream_TaskHandler(t *testing.T) {
+	handler := taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		server.ReportTaskStatus(ctx, task, adk.TaskStatus{State: adk.TaskStateWorking, Message: textMessage("assistant", "Reading the report")})
+		task.Artifacts = append(task.Artifacts, adk.Artifact{ArtifactID: "summary"})
+		task.Status.State = adk.TaskStateCompleted
+		task.Status.Message = textMessage("assistant", "Revenue grew")
+		return task, nil
+	})
+
+	events, task := streamWithHandler(t, handler)
+
+	assert.Equal(t, []string{
+		"working:Summarize the report",
+		"working:Reading the report",
+		"artifact:summary",
+		"completed:Revenue grew (final)",
+	}, eventLabels(events), "handlers without streaming support stream their reported status and result")
+	assert.Equal(t, adk.TaskStateCompleted, task.Status.State)
+}
@@ -39,3 +39,61 @@ func (th *DefaultTaskHandler) HandleTask(ctx context.Context, task *adk.Task, me
 
 	return nil, fmt.Errorf("no task handler configured: use AgentTaskHandler with an OpenAI-compatible agent or implement a custom TaskHandler")
 }
+
+// TaskStatusReporter publishes an intermediate status of a task while it is being processed
+// Streaming requests send it as a status update event, other requests update the stored task
+type TaskStatusReporter func(ctx context.Context, task *adk.Task, status adk.TaskStatus)
+
+// taskStatusReporterContextKey is the context key of the status reporter of the task being processed
+type taskStatusReporterContextKey struct{}
+
+// WithTaskStatusReporter returns a copy of ctx carrying the status reporter of the task being processed
+func WithTaskStatusReporter(ctx context.Context, reporter TaskStatusReporter) context.Context {
+	return context.WithValue(ctx, taskStatusReporterContextKey{}, reporter)
+}
+
+// ReportTaskStatus publishes an intermediate status of the task, it does nothing if ctx carries no reporter
+func ReportTaskStatus(ctx context.Context, task *adk.Task, status adk.TaskStatus) {
+	reporter, ok := ctx.Value(taskStatusReporterContextKey{}).(TaskStatusReporter)
+	if !ok || reporter == nil {
+		return
+	}
+	reporter(ctx, task, status)
+}
+
+// TaskMetadataWriter sets a metadata value of a task while it is being processed
+// The server sets it with the task manager, which guards the metadata of the tasks it stores with its lock
+type TaskMetadataWriter func(task *adk.Task, key string, value interface{})
+
+// taskMetadataWriterContextKey is the context key of the metadata writer of the task being processed
+type taskMetadataWriterContextKey struct{}
+
+// WithTaskMetadataWriter returns a copy of ctx carrying the metadata writer of the task being processed
+func WithTaskMetadataWriter(ctx context.Context, writer TaskMetadataWriter) context.Context {
+	return context.WithValue(ctx, taskMetadataWriterContextKey{}, writer)
+}
+
+// SetTaskMetadata sets a metadata value of the task with the metadata writer of ctx, a nil value removes the key
+// The value is set on a copy of the metadata of the task if ctx carries no writer
+func SetTaskMetadata(ctx context.Context, task *adk.Task, key string, value interface{}) {
+	writer, ok := ctx.Value(taskMetadataWriterContextKey{}).(TaskMetadataWriter)
+	if !ok || writer == nil {
+		setTaskMetadata(task, key, value)
+		return
+	}
+	writer(task, key, value)
+}
+
+// setTaskMetadata replaces the metadata of the task by a copy holding the value, or without the key for a nil value
+func setTaskMetadata(task *adk.Task, key string, value interface{}) {
+	metadata := make(map[string]interface{}, len(task.Metadata)+1)
+	for k, v := range task.Metadata {
+		metadata[k] = v
+	}
+	if value == nil {
+		delete(metadata, key)
+	} else {
+		metadata[key] = value
+	}
+	task.Metadata = metadata
+}
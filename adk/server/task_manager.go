@@ -8,6 +8,7 @@ import (
 
 	uuid "github.com/google/uuid"
 	adk "github.com/inference-gateway/a2a/adk"
+	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
 	zap "go.uber.org/zap"
 )
 
@@ -16,6 +17,23 @@ type TaskManager interface {
 	// CreateTask creates a new task and stores it
 	CreateTask(contextID string, state adk.TaskState, message *adk.Message) *adk.Task
 
+	// CreateTaskForPrincipal creates a new task owned by the principal
+	// Fails when the context is owned by another principal
+	CreateTaskForPrincipal(principal *middlewares.Principal, contextID string, state adk.TaskState, message *adk.Message) (*adk.Task, error)
+
+	// AuthorizeTask checks that the principal owns the task or holds the admin role
+	AuthorizeTask(taskID string, principal *middlewares.Principal) error
+
+	// TaskOwner returns the owner of the task, or an empty string for unowned tasks
+	TaskOwner(taskID string) string
+
+	// SetTaskMetadata sets a metadata value of the task under the lock of the task manager, a nil value removes the key
+	SetTaskMetadata(task *adk.Task, key string, value interface{})
+
+	// ResumeTask records the reply in the history of an input-required task and moves it to the state
+	// Fails when the task does not exist or is not waiting for input
+	ResumeTask(taskID string, state adk.TaskState, message *adk.Message) (*adk.Task, error)
+
 	// UpdateTask updates an existing task
 	UpdateTask(taskID string, state adk.TaskState, message *adk.Message) error
 
@@ -25,10 +43,13 @@ type TaskManager interface {
 	// ListTasks retrieves a list of tasks based on the provided parameters
 	ListTasks(params adk.TaskListParams) (*adk.TaskList, error)
 
+	// ListTasksForPrincipal retrieves a list of the tasks visible to the principal
+	ListTasksForPrincipal(params adk.TaskListParams, principal *middlewares.Principal) (*adk.TaskList, error)
+
 	// CancelTask cancels a task
 	CancelTask(taskID string) error
 
-	// CleanupCompletedTasks removes old completed tasks from memory
+	// CleanupCompletedTasks removes old completed tasks from memory, and the owners of contexts left without tasks and history
 	CleanupCompletedTasks()
 
 	// PollTaskStatus periodically checks the status of a task until it is completed or failed
@@ -37,7 +58,7 @@ type TaskManager interface {
 	// GetConversationHistory retrieves conversation history for a context ID
 	GetConversationHistory(contextID string) []adk.Message
 
-	// UpdateConversationHistory updates conversation history for a context ID
+	// UpdateConversationHistory updates conversation history for a context ID, empty messages clear the history
 	UpdateConversationHistory(contextID string, messages []adk.Message)
 
 	// SetTaskPushNotificationConfig sets push notification configuration for a task
@@ -57,8 +78,10 @@ type TaskManager interface {
 type DefaultTaskManager struct {
 	logger                    *zap.Logger
 	tasks                     map[string]*adk.Task
+	taskOwners                map[string]string                                     // taskID -> owner
 	pushNotificationConfigs   map[string]map[string]*adk.TaskPushNotificationConfig // taskID -> configID -> config
 	conversationHistory       map[string][]adk.Message                              // contextID -> conversation history
+	contextOwners             map[string]string                                     // contextID -> owner
 	maxConversationHistory    int                                                   // maximum number of messages to keep in history
 	notificationSender        PushNotificationSender                                // for sending push notifications
 	tasksMu                   sync.RWMutex
@@ -71,8 +94,10 @@ func NewDefaultTaskManager(logger *zap.Logger, maxConversationHistory int) *Defa
 	return &DefaultTaskManager{
 		logger:                  logger,
 		tasks:                   make(map[string]*adk.Task),
+		taskOwners:              make(map[string]string),
 		pushNotificationConfigs: make(map[string]map[string]*adk.TaskPushNotificationConfig),
 		conversationHistory:     make(map[string][]adk.Message),
+		contextOwners:           make(map[string]string),
 		maxConversationHistory:  maxConversationHistory,
 		notificationSender:      nil, // Can be set later with SetNotificationSender
 	}
@@ -83,8 +108,10 @@ func NewDefaultTaskManagerWithNotifications(logger *zap.Logger, maxConversationH
 	return &DefaultTaskManager{
 		logger:                  logger,
 		tasks:                   make(map[string]*adk.Task),
+		taskOwners:              make(map[string]string),
 		pushNotificationConfigs: make(map[string]map[string]*adk.TaskPushNotificationConfig),
 		conversationHistory:     make(map[string][]adk.Message),
+		contextOwners:           make(map[string]string),
 		maxConversationHistory:  maxConversationHistory,
 		notificationSender:      notificationSender,
 	}
@@ -97,6 +124,46 @@ func (tm *DefaultTaskManager) SetNotificationSender(sender PushNotificationSende
 
 // CreateTask creates a new task and stores it
 func (tm *DefaultTaskManager) CreateTask(contextID string, state adk.TaskState, message *adk.Message) *adk.Task {
+	return tm.createTask(contextID, state, message, "")
+}
+
+// CreateTaskForPrincipal creates a new task owned by the principal
+// The first principal using a context becomes its owner, other principals are denied access to it
+func (tm *DefaultTaskManager) CreateTaskForPrincipal(principal *middlewares.Principal, contextID string, state adk.TaskState, message *adk.Message) (*adk.Task, error) {
+	if principal == nil {
+		return tm.createTask(contextID, state, message, ""), nil
+	}
+
+	if err := tm.claimContext(contextID, principal); err != nil {
+		return nil, err
+	}
+
+	return tm.createTask(contextID, state, message, principal.Owner()), nil
+}
+
+// claimContext assigns an unowned context to the principal or verifies the principal may access it
+func (tm *DefaultTaskManager) claimContext(contextID string, principal *middlewares.Principal) error {
+	tm.conversationMu.Lock()
+	defer tm.conversationMu.Unlock()
+
+	owner, exists := tm.contextOwners[contextID]
+	if !exists || owner == "" {
+		tm.contextOwners[contextID] = principal.Owner()
+		return nil
+	}
+
+	if !principal.CanAccess(owner) {
+		tm.logger.Warn("principal denied access to context",
+			zap.String("context_id", contextID),
+			zap.String("subject", principal.Subject))
+		return NewContextAccessDeniedError(contextID)
+	}
+
+	return nil
+}
+
+// createTask creates a new task owned by the given owner and stores it
+func (tm *DefaultTaskManager) createTask(contextID string, state adk.TaskState, message *adk.Message, owner string) *adk.Task {
 	tm.tasksMu.Lock()
 	defer tm.tasksMu.Unlock()
 
@@ -123,6 +190,9 @@ func (tm *DefaultTaskManager) CreateTask(contextID string, state adk.TaskState,
 	}
 
 	tm.tasks[task.ID] = task
+	if owner != "" {
+		tm.taskOwners[task.ID] = owner
+	}
 	tm.logger.Debug("task created",
 		zap.String("task_id", task.ID),
 		zap.String("context_id", contextID),
@@ -132,6 +202,79 @@ func (tm *DefaultTaskManager) CreateTask(contextID string, state adk.TaskState,
 	return task
 }
 
+// AuthorizeTask checks that the principal owns the task or holds the admin role
+func (tm *DefaultTaskManager) AuthorizeTask(taskID string, principal *middlewares.Principal) error {
+	tm.tasksMu.RLock()
+	defer tm.tasksMu.RUnlock()
+
+	if _, exists := tm.tasks[taskID]; !exists {
+		return NewTaskNotFoundError(taskID)
+	}
+
+	if !principal.CanAccess(tm.taskOwners[taskID]) {
+		tm.logger.Warn("principal denied access to task",
+			zap.String("task_id", taskID),
+			zap.String("subject", principal.Subject))
+		return NewTaskAccessDeniedError(taskID)
+	}
+
+	return nil
+}
+
+// TaskOwner returns the owner of the task, or an empty string for unowned tasks
+func (tm *DefaultTaskManager) TaskOwner(taskID string) string {
+	tm.tasksMu.RLock()
+	defer tm.tasksMu.RUnlock()
+
+	return tm.taskOwners[taskID]
+}
+
+// SetTaskMetadata sets a metadata value of the task under the lock of the task manager, a nil value removes the key
+// The metadata map is replaced by an updated copy, so that tasks read by other requests never see it change
+func (tm *DefaultTaskManager) SetTaskMetadata(task *adk.Task, key string, value interface{}) {
+	tm.tasksMu.Lock()
+	defer tm.tasksMu.Unlock()
+
+	setTaskMetadata(task, key, value)
+}
+
+// ResumeTask records the reply in the history of an input-required task and moves it to the state
+// The state is checked and changed under the lock, so that only one of concurrent replies resumes the task
+func (tm *DefaultTaskManager) ResumeTask(taskID string, state adk.TaskState, message *adk.Message) (*adk.Task, error) {
+	tm.tasksMu.Lock()
+	defer tm.tasksMu.Unlock()
+
+	task, exists := tm.tasks[taskID]
+	if !exists {
+		return nil, NewTaskNotFoundError(taskID)
+	}
+
+	if task.Status.State != adk.TaskStateInputRequired {
+		return nil, NewTaskNotResumableError(taskID, task.Status.State)
+	}
+
+	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	task.History = append(task.History, *message)
+	task.Status.State = state
+	task.Status.Message = message
+	task.Status.Timestamp = &timestamp
+	if task.ContextID != "" {
+		tm.UpdateConversationHistory(task.ContextID, task.History)
+	}
+
+	tm.logger.Debug("task resumed",
+		zap.String("task_id", taskID),
+		zap.String("context_id", task.ContextID),
+		zap.String("state", string(state)),
+		zap.Int("history_count", len(task.History)))
+
+	if tm.notificationSender != nil {
+		go tm.sendPushNotifications(taskID, task)
+	}
+
+	return task, nil
+}
+
 // UpdateTask updates an existing task
 func (tm *DefaultTaskManager) UpdateTask(taskID string, state adk.TaskState, message *adk.Message) error {
 	tm.tasksMu.Lock()
@@ -211,12 +354,22 @@ func (tm *DefaultTaskManager) GetTask(taskID string) (*adk.Task, bool) {
 
 // ListTasks retrieves a list of tasks based on the provided parameters
 func (tm *DefaultTaskManager) ListTasks(params adk.TaskListParams) (*adk.TaskList, error) {
+	return tm.ListTasksForPrincipal(params, nil)
+}
+
+// ListTasksForPrincipal retrieves a list of the tasks visible to the principal
+// A nil principal sees all tasks
+func (tm *DefaultTaskManager) ListTasksForPrincipal(params adk.TaskListParams, principal *middlewares.Principal) (*adk.TaskList, error) {
 	tm.tasksMu.RLock()
 	defer tm.tasksMu.RUnlock()
 
 	var allTasks []*adk.Task
 
 	for _, task := range tm.tasks {
+		if !principal.CanAccess(tm.taskOwners[task.ID]) {
+			continue
+		}
+
 		if params.State != nil && task.Status.State != *params.State {
 			continue
 		}
@@ -294,7 +447,7 @@ func (tm *DefaultTaskManager) CancelTask(taskID string) error {
 	return nil
 }
 
-// CleanupCompletedTasks removes old completed tasks from memory
+// CleanupCompletedTasks removes old completed tasks from memory, and the owners of contexts left without tasks and history
 func (tm *DefaultTaskManager) CleanupCompletedTasks() {
 	tm.tasksMu.Lock()
 	defer tm.tasksMu.Unlock()
@@ -310,11 +463,43 @@ func (tm *DefaultTaskManager) CleanupCompletedTasks() {
 
 	for _, taskID := range toRemove {
 		delete(tm.tasks, taskID)
+		delete(tm.taskOwners, taskID)
 	}
 
 	if len(toRemove) > 0 {
 		tm.logger.Info("cleaned up completed tasks", zap.Int("count", len(toRemove)))
 	}
+
+	tm.cleanupContextOwners()
+}
+
+// cleanupContextOwners removes the owners of contexts left without tasks and conversation history
+// The owner of a context with history is kept, so that its conversation stays private to the owner
+// The caller must hold tasksMu
+func (tm *DefaultTaskManager) cleanupContextOwners() {
+	activeContexts := make(map[string]struct{}, len(tm.tasks))
+	for _, task := range tm.tasks {
+		activeContexts[task.ContextID] = struct{}{}
+	}
+
+	tm.conversationMu.Lock()
+	defer tm.conversationMu.Unlock()
+
+	removed := 0
+	for contextID := range tm.contextOwners {
+		if _, active := activeContexts[contextID]; active {
+			continue
+		}
+		if len(tm.conversationHistory[contextID]) > 0 {
+			continue
+		}
+		delete(tm.contextOwners, contextID)
+		removed++
+	}
+
+	if removed > 0 {
+		tm.logger.Debug("cleaned up context owners", zap.Int("count", removed))
+	}
 }
 
 // PollTaskStatus periodically checks the status of a task until it is completed or failed
@@ -357,7 +542,7 @@ func (tm *DefaultTaskManager) GetConversationHistory(contextID string) []adk.Mes
 	return []adk.Message{}
 }
 
-// UpdateConversationHistory updates conversation history for a context ID
+// UpdateConversationHistory updates conversation history for a context ID, empty messages clear the history
 func (tm *DefaultTaskManager) UpdateConversationHistory(contextID string, messages []adk.Message) {
 	tm.conversationMu.Lock()
 	defer tm.conversationMu.Unlock()
@@ -366,7 +551,11 @@ func (tm *DefaultTaskManager) UpdateConversationHistory(contextID string, messag
 	copy(history, messages)
 
 	trimmedHistory := tm.trimConversationHistory(history)
-	tm.conversationHistory[contextID] = trimmedHistory
+	if len(trimmedHistory) == 0 {
+		delete(tm.conversationHistory, contextID)
+	} else {
+		tm.conversationHistory[contextID] = trimmedHistory
+	}
 
 	tm.logger.Debug("conversation history updated",
 		zap.String("context_id", contextID),
@@ -490,3 +679,46 @@ func (e *TaskNotFoundError) Error() string {
 func NewTaskNotFoundError(taskID string) error {
 	return &TaskNotFoundError{TaskID: taskID}
 }
+
+// TaskAccessDeniedError represents an error when a principal may not access a task
+type TaskAccessDeniedError struct {
+	TaskID string
+}
+
+func (e *TaskAccessDeniedError) Error() string {
+	return "access denied to task: " + e.TaskID
+}
+
+// NewTaskAccessDeniedError creates a new TaskAccessDeniedError
+func NewTaskAccessDeniedError(taskID string) error {
+	return &TaskAccessDeniedError{TaskID: taskID}
+}
+
+// TaskNotResumableError represents an error when a message replies to a task that is not waiting for input
+type TaskNotResumableError struct {
+	TaskID string
+	State  adk.TaskState
+}
+
+func (e *TaskNotResumableError) Error() string {
+	return fmt.Sprintf("task %s is not waiting for input, its state is %s", e.TaskID, e.State)
+}
+
+// NewTaskNotResumableError creates a new TaskNotResumableError
+func NewTaskNotResumableError(taskID string, state adk.TaskState) error {
+	return &TaskNotResumableError{TaskID: taskID, State: state}
+}
+
+// ContextAccessDeniedError represents an error when a principal may not access a context
+type ContextAccessDeniedError struct {
+	ContextID string
+}
+
+func (e *ContextAccessDeniedError) Error() string {
+	return "access denied to context: " + e.ContextID
+}
+
+// NewContextAccessDeniedError creates a new ContextAccessDeniedError
+func NewContextAccessDeniedError(contextID string) error {
+	return &ContextAccessDeniedError{ContextID: contextID}
+}
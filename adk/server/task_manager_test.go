@@ -7,6 +7,7 @@ import (
 
 	"github.com/inference-gateway/a2a/adk"
 	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
@@ -790,3 +791,326 @@ func TestDefaultTaskManager_ConversationHistoryLimitZeroDirectInstantiation(t *t
 
 	assert.Len(t, history, 0)
 }
+
+func TestDefaultTaskManager_CreateTaskForPrincipal(t *testing.T) {
+	alice := &middlewares.Principal{Subject: "alice"}
+	bob := &middlewares.Principal{Subject: "bob"}
+	admin := &middlewares.Principal{Subject: "root", Admin: true}
+
+	tests := []struct {
+		name          string
+		owner         *middlewares.Principal
+		caller        *middlewares.Principal
+		expectError   bool
+		expectedOwner string
+	}{
+		{
+			name:          "owner continues own context",
+			owner:         alice,
+			caller:        alice,
+			expectedOwner: "alice",
+		},
+		{
+			name:        "other principal is denied access to context",
+			owner:       alice,
+			caller:      bob,
+			expectError: true,
+		},
+		{
+			name:          "admin may continue any context",
+			owner:         alice,
+			caller:        admin,
+			expectedOwner: "root",
+		},
+		{
+			name:          "nil principal creates unowned task",
+			owner:         alice,
+			caller:        nil,
+			expectedOwner: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+
+			_, err := taskManager.CreateTaskForPrincipal(tt.owner, "context-1", adk.TaskStateSubmitted, nil)
+			assert.NoError(t, err)
+
+			task, err := taskManager.CreateTaskForPrincipal(tt.caller, "context-1", adk.TaskStateSubmitted, nil)
+			if tt.expectError {
+				assert.Error(t, err)
+				var accessDenied *server.ContextAccessDeniedError
+				assert.ErrorAs(t, err, &accessDenied)
+				assert.Nil(t, task)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, task)
+			assert.Equal(t, tt.expectedOwner, taskManager.TaskOwner(task.ID))
+			assert.Empty(t, task.Metadata, "the owner is not exposed in the task metadata")
+		})
+	}
+}
+
+func TestDefaultTaskManager_AuthorizeTask(t *testing.T) {
+	alice := &middlewares.Principal{Subject: "alice", Tenant: "acme"}
+	colleague := &middlewares.Principal{Subject: "carol", Tenant: "acme"}
+	bob := &middlewares.Principal{Subject: "bob"}
+	admin := &middlewares.Principal{Subject: "root", Admin: true}
+
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	owned, err := taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateSubmitted, nil)
+	assert.NoError(t, err)
+	unowned := taskManager.CreateTask("context-2", adk.TaskStateSubmitted, nil)
+
+	tests := []struct {
+		name        string
+		taskID      string
+		principal   *middlewares.Principal
+		expectedErr interface{}
+	}{
+		{
+			name:      "owner is allowed",
+			taskID:    owned.ID,
+			principal: alice,
+		},
+		{
+			name:      "principal of the same tenant is allowed",
+			taskID:    owned.ID,
+			principal: colleague,
+		},
+		{
+			name:        "other principal is denied",
+			taskID:      owned.ID,
+			principal:   bob,
+			expectedErr: &server.TaskAccessDeniedError{},
+		},
+		{
+			name:      "admin is allowed",
+			taskID:    owned.ID,
+			principal: admin,
+		},
+		{
+			name:      "unowned task is accessible to everyone",
+			taskID:    unowned.ID,
+			principal: bob,
+		},
+		{
+			name:        "missing task is reported as not found",
+			taskID:      "missing",
+			principal:   alice,
+			expectedErr: &server.TaskNotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := taskManager.AuthorizeTask(tt.taskID, tt.principal)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.IsType(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestDefaultTaskManager_ListTasksForPrincipal(t *testing.T) {
+	alice := &middlewares.Principal{Subject: "alice"}
+	bob := &middlewares.Principal{Subject: "bob"}
+	admin := &middlewares.Principal{Subject: "root", Admin: true}
+
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	for i := 0; i < 2; i++ {
+		_, err := taskManager.CreateTaskForPrincipal(alice, "alice-context", adk.TaskStateSubmitted, nil)
+		assert.NoError(t, err)
+	}
+	_, err := taskManager.CreateTaskForPrincipal(bob, "bob-context", adk.TaskStateSubmitted, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		principal     *middlewares.Principal
+		expectedTotal int
+	}{
+		{name: "alice sees own tasks", principal: alice, expectedTotal: 2},
+		{name: "bob sees own tasks", principal: bob, expectedTotal: 1},
+		{name: "admin sees all tasks", principal: admin, expectedTotal: 3},
+		{name: "nil principal sees all tasks", principal: nil, expectedTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskList, err := taskManager.ListTasksForPrincipal(adk.TaskListParams{}, tt.principal)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTotal, taskList.Total)
+		})
+	}
+}
+
+func TestDefaultTaskManager_SetTaskMetadata(t *testing.T) {
+	alice := &middlewares.Principal{Subject: "alice"}
+	bob := &middlewares.Principal{Subject: "bob"}
+
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	task, err := taskManager.CreateTaskForPrincipal(alice, "alice-context", adk.TaskStateWorking, nil)
+	assert.NoError(t, err)
+
+	taskManager.SetTaskMetadata(task, "skillId", "summarize")
+	before := task.Metadata
+	taskManager.SetTaskMetadata(task, "owner", "bob")
+	taskManager.SetTaskMetadata(task, "skillId", nil)
+
+	assert.Equal(t, map[string]interface{}{"owner": "bob"}, task.Metadata, "a nil value removes the key")
+	assert.Equal(t, map[string]interface{}{"skillId": "summarize"}, before, "metadata read before an update does not change")
+	assert.Equal(t, "alice", taskManager.TaskOwner(task.ID), "the metadata does not change the owner of the task")
+	assert.Error(t, taskManager.AuthorizeTask(task.ID, bob))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			taskManager.SetTaskMetadata(task, "step", i)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		taskList, err := taskManager.ListTasksForPrincipal(adk.TaskListParams{}, alice)
+		assert.NoError(t, err)
+		for _, listed := range taskList.Tasks {
+			for range listed.Metadata {
+			}
+		}
+	}
+	<-done
+}
+
+func TestDefaultTaskManager_ResumeTask(t *testing.T) {
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	waiting := taskManager.CreateTask("context-1", adk.TaskStateInputRequired, nil)
+	completed := taskManager.CreateTask("context-2", adk.TaskStateCompleted, nil)
+
+	reply := &adk.Message{Kind: "message", MessageID: "reply", Role: "user"}
+
+	tests := []struct {
+		name        string
+		taskID      string
+		expectedErr interface{}
+	}{
+		{name: "input-required task is resumed", taskID: waiting.ID},
+		{name: "task not waiting for input is rejected", taskID: completed.ID, expectedErr: &server.TaskNotResumableError{}},
+		{name: "missing task is reported as not found", taskID: "missing", expectedErr: &server.TaskNotFoundError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := taskManager.ResumeTask(tt.taskID, adk.TaskStateWorking, reply)
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.IsType(t, tt.expectedErr, err)
+				assert.Nil(t, task)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, adk.TaskStateWorking, task.Status.State)
+			assert.Equal(t, []adk.Message{*reply}, task.History)
+			assert.Equal(t, task.History, taskManager.GetConversationHistory(task.ContextID))
+		})
+	}
+}
+
+func TestDefaultTaskManager_ResumeTask_ConcurrentReplies(t *testing.T) {
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	task := taskManager.CreateTask("context-1", adk.TaskStateInputRequired, nil)
+
+	const replies = 10
+	results := make(chan error, replies)
+	for i := 0; i < replies; i++ {
+		go func(i int) {
+			_, err := taskManager.ResumeTask(task.ID, adk.TaskStateSubmitted, &adk.Message{
+				Kind:      "message",
+				MessageID: fmt.Sprintf("reply-%d", i),
+				Role:      "user",
+			})
+			results <- err
+		}(i)
+	}
+
+	resumed := 0
+	for i := 0; i < replies; i++ {
+		if err := <-results; err == nil {
+			resumed++
+		} else {
+			assert.IsType(t, &server.TaskNotResumableError{}, err)
+		}
+	}
+
+	assert.Equal(t, 1, resumed, "only one reply resumes the task")
+	stored, exists := taskManager.GetTask(task.ID)
+	assert.True(t, exists)
+	assert.Len(t, stored.History, 1)
+}
+
+func TestDefaultTaskManager_CleanupCompletedTasks_ContextOwners(t *testing.T) {
+	alice := &middlewares.Principal{Subject: "alice"}
+	bob := &middlewares.Principal{Subject: "bob"}
+	message := &adk.Message{Kind: "message", MessageID: "msg-1", Role: "user"}
+
+	tests := []struct {
+		name         string
+		setup        func(taskManager *server.DefaultTaskManager)
+		expectDenied bool
+	}{
+		{
+			name: "owner of a context without tasks and history is removed",
+			setup: func(taskManager *server.DefaultTaskManager) {
+				_, err := taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateCompleted, nil)
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "owner of a context with history is kept",
+			setup: func(taskManager *server.DefaultTaskManager) {
+				_, err := taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateCompleted, message)
+				assert.NoError(t, err)
+			},
+			expectDenied: true,
+		},
+		{
+			name: "owner of a context with an active task is kept",
+			setup: func(taskManager *server.DefaultTaskManager) {
+				_, err := taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateCompleted, nil)
+				assert.NoError(t, err)
+				_, err = taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateWorking, nil)
+				assert.NoError(t, err)
+			},
+			expectDenied: true,
+		},
+		{
+			name: "owner of a context whose history was cleared is removed",
+			setup: func(taskManager *server.DefaultTaskManager) {
+				_, err := taskManager.CreateTaskForPrincipal(alice, "context-1", adk.TaskStateCompleted, message)
+				assert.NoError(t, err)
+				taskManager.UpdateConversationHistory("context-1", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+			tt.setup(taskManager)
+
+			taskManager.CleanupCompletedTasks()
+
+			_, err := taskManager.CreateTaskForPrincipal(bob, "context-1", adk.TaskStateSubmitted, nil)
+			if tt.expectDenied {
+				assert.IsType(t, &server.ContextAccessDeniedError{}, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	adk "github.com/inference-gateway/a2a/adk"
+	middlewares "github.com/inference-gateway/a2a/adk/server/middlewares"
+	otel "github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+)
+
+// MetadataTokenUsageKey is the task metadata key holding the tokens used by the LLM for the task
+const MetadataTokenUsageKey = "tokenUsage"
+
+// TokenUsageReporter receives the tokens used by each LLM completion of a task
+type TokenUsageReporter func(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage)
+
+// tokenUsageReporterContextKey is the context key of the token usage reporter of the task being processed
+type tokenUsageReporterContextKey struct{}
+
+// WithTokenUsageReporter returns a copy of ctx carrying the token usage reporter of the task being processed
+func WithTokenUsageReporter(ctx context.Context, reporter TokenUsageReporter) context.Context {
+	return context.WithValue(ctx, tokenUsageReporterContextKey{}, reporter)
+}
+
+// RecordTokenUsage adds the tokens used by an LLM completion to the task metadata and reports them
+// to the token usage reporter of ctx, if any. Completions without usage are ignored.
+func RecordTokenUsage(ctx context.Context, task *adk.Task, usage *sdk.CompletionUsage) {
+	if task == nil || usage == nil {
+		return
+	}
+
+	total := TaskTokenUsage(task)
+	addTokenUsage(&total, *usage)
+	SetTaskMetadata(ctx, task, MetadataTokenUsageKey, total)
+
+	reporter, ok := ctx.Value(tokenUsageReporterContextKey{}).(TokenUsageReporter)
+	if !ok || reporter == nil {
+		return
+	}
+	reporter(ctx, task, *usage)
+}
+
+// TaskTokenUsage returns the tokens used by the LLM for the task so far
+func TaskTokenUsage(task *adk.Task) sdk.CompletionUsage {
+	switch value := task.Metadata[MetadataTokenUsageKey].(type) {
+	case sdk.CompletionUsage:
+		return value
+	case nil:
+		return sdk.CompletionUsage{}
+	default:
+		var usage sdk.CompletionUsage
+		if raw, err := json.Marshal(value); err == nil {
+			_ = json.Unmarshal(raw, &usage)
+		}
+		return usage
+	}
+}
+
+// addTokenUsage adds the usage to the total
+func addTokenUsage(total *sdk.CompletionUsage, usage sdk.CompletionUsage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+}
+
+// TokenUsageTracker rolls up the tokens used by tasks per context per day and per principal per month
+// and records them in the token usage metrics, attributed to the principal owning the task.
+// Days and months are calendar periods in UTC, the usage of past periods is discarded.
+type TokenUsageTracker struct {
+	telemetry otel.OpenTelemetry
+	attrs     otel.TelemetryAttributes
+	now       func() time.Time
+
+	mu         sync.RWMutex
+	contexts   map[string]*usageWindow
+	principals map[string]*usageWindow
+}
+
+// usageWindow is the token usage of a context or principal in the current period
+type usageWindow struct {
+	start time.Time
+	usage sdk.CompletionUsage
+}
+
+// NewTokenUsageTracker creates a token usage tracker, telemetry is optional
+func NewTokenUsageTracker(telemetry otel.OpenTelemetry, attrs otel.TelemetryAttributes) *TokenUsageTracker {
+	return &TokenUsageTracker{
+		telemetry:  telemetry,
+		attrs:      attrs,
+		now:        time.Now,
+		contexts:   make(map[string]*usageWindow),
+		principals: make(map[string]*usageWindow),
+	}
+}
+
+// Record implements TokenUsageReporter, a nil tracker records nothing
+func (t *TokenUsageTracker) Record(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage) {
+	if t == nil {
+		return
+	}
+
+	principal := requestPrincipal(ctx)
+	now := t.now().UTC()
+
+	t.mu.Lock()
+	addTokenUsage(&t.window(t.contexts, task.ContextID, startOfDay(now)).usage, usage)
+	if principal != "" {
+		addTokenUsage(&t.window(t.principals, principal, startOfMonth(now)).usage, usage)
+	}
+	t.mu.Unlock()
+
+	if t.telemetry == nil {
+		return
+	}
+
+	attrs := t.attrs
+	attrs.TaskID = task.ID
+	attrs.Principal = principal
+	if model, ok := task.Metadata[MetadataLLMModelKey].(string); ok && model != "" {
+		attrs.Model = parseModelName(model, "")
+	} else if options, ok := task.Metadata[MetadataLLMRequestKey].(LLMRequestOptions); ok && options.Model != "" {
+		attrs.Model = options.Model
+	}
+	t.telemetry.RecordTokenUsage(ctx, attrs, usage)
+}
+
+// ContextUsage returns the tokens used by the tasks of the context today
+func (t *TokenUsageTracker) ContextUsage(contextID string) sdk.CompletionUsage {
+	if t == nil {
+		return sdk.CompletionUsage{}
+	}
+	return t.usage(t.contexts, contextID, startOfDay(t.now().UTC()))
+}
+
+// PrincipalUsage returns the tokens used this month by the tasks owned by the principal, identified by its owner
+func (t *TokenUsageTracker) PrincipalUsage(owner string) sdk.CompletionUsage {
+	if t == nil {
+		return sdk.CompletionUsage{}
+	}
+	return t.usage(t.principals, owner, startOfMonth(t.now().UTC()))
+}
+
+// usage returns the usage of the key in the period starting at start
+func (t *TokenUsageTracker) usage(windows map[string]*usageWindow, key string, start time.Time) sdk.CompletionUsage {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if window, ok := windows[key]; ok && window.start.Equal(start) {
+		return window.usage
+	}
+	return sdk.CompletionUsage{}
+}
+
+// window returns the usage of the key in the period starting at start, discarding the usage of past periods
+func (t *TokenUsageTracker) window(windows map[string]*usageWindow, key string, start time.Time) *usageWindow {
+	window, ok := windows[key]
+	if ok && window.start.Equal(start) {
+		return window
+	}
+
+	for otherKey, other := range windows {
+		if other.start.Before(start) {
+			delete(windows, otherKey)
+		}
+	}
+
+	window = &usageWindow{start: start}
+	windows[key] = window
+	return window
+}
+
+// requestPrincipal returns the owner of the principal of the request processing the task
+func requestPrincipal(ctx context.Context) string {
+	principal, _ := middlewares.PrincipalFromContext(ctx)
+	return principal.Owner()
+}
@@ -0,0 +1,189 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/client"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/config"
+	"github.com/inference-gateway/a2a/adk/server/middlewares"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	"github.com/inference-gateway/a2a/adk/server/otel"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func withUsage(response *sdk.CreateChatCompletionResponse, prompt int64, completion int64) *sdk.CreateChatCompletionResponse {
+	response.Usage = &sdk.CompletionUsage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+	return response
+}
+
+func TestDefaultOpenAICompatibleAgent_RecordsTokenUsage(t *testing.T) {
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("lookup", "Look up records", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "record 7", nil
+		}))
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, withUsage(newToolCallResponse("lookup"), 100, 20), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, withUsage(completionResponse("Found record 7."), 150, 10), nil)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithToolBox(toolBox).Build()
+	require.NoError(t, err)
+
+	var reported []sdk.CompletionUsage
+	ctx := server.WithTokenUsageReporter(context.Background(), func(ctx context.Context, task *adk.Task, usage sdk.CompletionUsage) {
+		reported = append(reported, usage)
+	})
+
+	task, err := agent.ProcessTask(ctx, &adk.Task{ID: "task-1", ContextID: "ctx-1"}, textMessage("user", "Find record 7"))
+	require.NoError(t, err)
+	require.Equal(t, adk.TaskStateCompleted, task.Status.State)
+
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 250, CompletionTokens: 30, TotalTokens: 280}, server.TaskTokenUsage(task))
+	assert.Equal(t, []sdk.CompletionUsage{
+		{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+		{PromptTokens: 150, CompletionTokens: 10, TotalTokens: 160},
+	}, reported, "each iteration is reported")
+}
+
+func TestTokenUsageTracker(t *testing.T) {
+	telemetry := &mocks.FakeOpenTelemetry{}
+	tracker := server.NewTokenUsageTracker(telemetry, otel.TelemetryAttributes{Provider: "openai", Model: "gpt-4o"})
+
+	aliceCtx := middlewares.WithPrincipal(context.Background(), &middlewares.Principal{Subject: "team-a"})
+	alice := &adk.Task{ID: "task-1", ContextID: "ctx-1"}
+	aliceAgain := &adk.Task{ID: "task-2", ContextID: "ctx-1", Metadata: map[string]interface{}{
+		server.MetadataLLMRequestKey: server.LLMRequestOptions{Model: "gpt-4o-mini"},
+	}}
+	anonymous := &adk.Task{ID: "task-3", ContextID: "ctx-2"}
+
+	tracker.Record(aliceCtx, alice, sdk.CompletionUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+	tracker.Record(aliceCtx, aliceAgain, sdk.CompletionUsage{PromptTokens: 20, CompletionTokens: 5, TotalTokens: 25})
+	tracker.Record(context.Background(), anonymous, sdk.CompletionUsage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2})
+
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 30, CompletionTokens: 10, TotalTokens: 40}, tracker.ContextUsage("ctx-1"))
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2}, tracker.ContextUsage("ctx-2"))
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 30, CompletionTokens: 10, TotalTokens: 40}, tracker.PrincipalUsage("team-a"))
+	assert.Equal(t, sdk.CompletionUsage{}, tracker.PrincipalUsage("team-b"))
+
+	require.Equal(t, 3, telemetry.RecordTokenUsageCallCount())
+	_, attrs, usage := telemetry.RecordTokenUsageArgsForCall(1)
+	assert.Equal(t, otel.TelemetryAttributes{Provider: "openai", Model: "gpt-4o-mini", TaskID: "task-2", Principal: "team-a"}, attrs)
+	assert.Equal(t, int64(25), usage.TotalTokens)
+	_, attrs, _ = telemetry.RecordTokenUsageArgsForCall(2)
+	assert.Empty(t, attrs.Principal)
+}
+
+func TestMessageHandler_HandleMessageStream_RecordsTokenUsage(t *testing.T) {
+	responses := make(chan *sdk.CreateChatCompletionStreamResponse, 2)
+	responses <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{{Delta: sdk.ChatCompletionStreamResponseDelta{Content: "Hello"}}},
+	}
+	responses <- &sdk.CreateChatCompletionStreamResponse{
+		Choices: []sdk.ChatCompletionStreamChoice{{FinishReason: "stop"}},
+		Usage:   &sdk.CompletionUsage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15},
+	}
+	close(responses)
+	errs := make(chan error)
+	close(errs)
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateStreamingChatCompletionReturns(responses, errs)
+
+	agent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).Build()
+	require.NoError(t, err)
+
+	taskManager := server.NewDefaultTaskManager(zap.NewNop(), 10)
+	messageHandler := server.NewDefaultMessageHandlerWithAgent(zap.NewNop(), taskManager, agent, &config.Config{
+		AgentConfig: config.AgentConfig{MaxChatCompletionIterations: 5},
+	})
+
+	tracker := server.NewTokenUsageTracker(nil, otel.TelemetryAttributes{})
+	ctx := server.WithTokenUsageReporter(context.Background(), tracker.Record)
+
+	responseChan := make(chan adk.SendStreamingMessageResponse, 50)
+	message := textMessage("user", "Hi")
+	contextID := "ctx-stream"
+	message.ContextID = &contextID
+	require.NoError(t, messageHandler.HandleMessageStream(ctx, adk.MessageSendParams{Message: *message}, responseChan))
+	close(responseChan)
+
+	var taskID string
+	for response := range responseChan {
+		if statusUpdate, ok := response.(adk.TaskStatusUpdateEvent); ok {
+			taskID = statusUpdate.TaskID
+		}
+	}
+
+	task, exists := taskManager.GetTask(taskID)
+	require.True(t, exists)
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}, server.TaskTokenUsage(task))
+	assert.Equal(t, int64(15), tracker.ContextUsage("ctx-stream").TotalTokens)
+}
+
+func TestOpenAICompatibleLLMClient_StreamAttachesUsageToFinishChunk(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+		_, _ = fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		_, _ = fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2,\"total_tokens\":9}}\n\n")
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer gateway.Close()
+
+	client, err := server.NewOpenAICompatibleLLMClient(newLLMGatewayConfig(gateway.URL), zap.NewNop())
+	require.NoError(t, err)
+
+	streamResponses, streamErrors := client.CreateStreamingChatCompletion(context.Background(), []sdk.Message{{Role: sdk.User, Content: "Hi"}})
+
+	var received []*sdk.CreateChatCompletionStreamResponse
+	for streamResponse := range streamResponses {
+		received = append(received, streamResponse)
+	}
+	require.NoError(t, <-streamErrors)
+
+	require.Len(t, received, 2)
+	assert.Equal(t, "stop", string(received[1].Choices[0].FinishReason))
+	require.NotNil(t, received[1].Usage)
+	assert.Equal(t, int64(9), received[1].Usage.TotalTokens)
+}
+
+func TestNewA2AServerEnvironmentAware_RecordsTokenUsage(t *testing.T) {
+	a2aServer := server.NewA2AServerEnvironmentAware(&config.Config{}, zap.NewNop(), nil)
+	a2aServer.SetTaskHandler(taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		server.RecordTokenUsage(ctx, task, &sdk.CompletionUsage{PromptTokens: 8, CompletionTokens: 2, TotalTokens: 10})
+		task.Status.State = adk.TaskStateCompleted
+		return task, nil
+	}))
+
+	handler, err := a2aServer.Handler()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	message := textMessage("user", "Hi")
+	contextID := "ctx-env"
+	message.ContextID = &contextID
+	eventChan := make(chan interface{}, 10)
+	require.NoError(t, client.NewClient(httpServer.URL).SendTaskStreaming(context.Background(), adk.MessageSendParams{Message: *message}, eventChan))
+
+	assert.Equal(t, int64(10), a2aServer.GetTokenUsageTracker().ContextUsage("ctx-env").TotalTokens)
+}
+
+func TestTokenUsageTracker_Nil(t *testing.T) {
+	var tracker *server.TokenUsageTracker
+
+	assert.NotPanics(t, func() {
+		tracker.Record(context.Background(), &adk.Task{ID: "task-1", ContextID: "ctx-1"}, sdk.CompletionUsage{TotalTokens: 10})
+	})
+	assert.Equal(t, sdk.CompletionUsage{}, tracker.ContextUsage("ctx-1"))
+}
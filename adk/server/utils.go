@@ -1,6 +1,14 @@
 package server
 
-import "github.com/google/uuid"
+import (
+	"context"
+
+	"github.com/google/uuid"
+	adk "github.com/inference-gateway/a2a/adk"
+)
+
+// MetadataSkillIDKey is the metadata key clients use to target a specific agent skill
+const MetadataSkillIDKey = "skillId"
 
 // StringPtr returns a pointer to the given string
 func StringPtr(s string) *string {
@@ -11,3 +19,78 @@ func StringPtr(s string) *string {
 func GenerateTaskID() string {
 	return uuid.New().String()
 }
+
+// IsFinalTaskState reports whether a task in the state stopped, either finished or waiting for input or authentication
+func IsFinalTaskState(state adk.TaskState) bool {
+	switch state {
+	case adk.TaskStateCompleted, adk.TaskStateFailed, adk.TaskStateCanceled, adk.TaskStateRejected,
+		adk.TaskStateInputRequired, adk.TaskStateAuthRequired:
+		return true
+	}
+	return false
+}
+
+// RequestedSkillID returns the agent skill ID requested via the message metadata, if any
+// It is the skill a SkillRouter selects for the message before looking at its content
+func RequestedSkillID(params adk.MessageSendParams) string {
+	return messageSkillID(&params.Message)
+}
+
+// messageSkillID returns the skill ID of the skillId metadata of the message, if any
+func messageSkillID(message *adk.Message) string {
+	if message == nil {
+		return ""
+	}
+	skillID, _ := message.Metadata[MetadataSkillIDKey].(string)
+	return skillID
+}
+
+// taskIDContextKey is the context key of the task a tool call is executed for
+type taskIDContextKey struct{}
+
+// withTaskID returns a copy of ctx carrying the ID of the task being processed
+func withTaskID(ctx context.Context, taskID string) context.Context {
+	return context.WithValue(ctx, taskIDContextKey{}, taskID)
+}
+
+// taskIDFromContext returns the ID of the task being processed, if any
+func taskIDFromContext(ctx context.Context) string {
+	taskID, _ := ctx.Value(taskIDContextKey{}).(string)
+	return taskID
+}
+
+// contextIDContextKey is the context key of the conversation context a tool call is executed in
+type contextIDContextKey struct{}
+
+// withContextID returns a copy of ctx carrying the context ID of the task being processed
+func withContextID(ctx context.Context, contextID string) context.Context {
+	return context.WithValue(ctx, contextIDContextKey{}, contextID)
+}
+
+// contextIDFromContext returns the context ID of the task being processed, if any
+func contextIDFromContext(ctx context.Context) string {
+	contextID, _ := ctx.Value(contextIDContextKey{}).(string)
+	return contextID
+}
+
+// requestValuesContext carries the cancellation of one context and the values of another
+type requestValuesContext struct {
+	context.Context
+	values context.Context
+}
+
+// Value looks up the key in the request values first
+func (c requestValuesContext) Value(key interface{}) interface{} {
+	if value := c.values.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+// withRequestValues returns ctx extended with the values of the originating request, if any
+func withRequestValues(ctx context.Context, requestCtx context.Context) context.Context {
+	if requestCtx == nil {
+		return ctx
+	}
+	return requestValuesContext{Context: ctx, values: requestCtx}
+}
@@ -3,6 +3,7 @@ package server_test
 import (
 	"testing"
 
+	"github.com/inference-gateway/a2a/adk"
 	"github.com/inference-gateway/a2a/adk/server"
 	"github.com/stretchr/testify/assert"
 )
@@ -66,3 +67,53 @@ func TestGenerateTaskID_ThreadSafety(t *testing.T) {
 	expectedTotal := numGoroutines * numIDsPerGoroutine
 	assert.Len(t, allIDs, expectedTotal, "All concurrently generated IDs should be unique")
 }
+
+func TestRequestedSkillID(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   adk.MessageSendParams
+		expected string
+	}{
+		{
+			name:     "message metadata",
+			params:   adk.MessageSendParams{Message: adk.Message{Metadata: map[string]interface{}{server.MetadataSkillIDKey: "billing"}}},
+			expected: "billing",
+		},
+		{
+			name:   "request metadata is not a skill source",
+			params: adk.MessageSendParams{Metadata: map[string]interface{}{server.MetadataSkillIDKey: "billing"}},
+		},
+		{
+			name:   "no skill",
+			params: adk.MessageSendParams{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, server.RequestedSkillID(tt.params))
+		})
+	}
+}
+
+func TestIsFinalTaskState(t *testing.T) {
+	tests := []struct {
+		state    adk.TaskState
+		expected bool
+	}{
+		{state: adk.TaskStateSubmitted, expected: false},
+		{state: adk.TaskStateWorking, expected: false},
+		{state: adk.TaskStateCompleted, expected: true},
+		{state: adk.TaskStateFailed, expected: true},
+		{state: adk.TaskStateCanceled, expected: true},
+		{state: adk.TaskStateRejected, expected: true},
+		{state: adk.TaskStateInputRequired, expected: true},
+		{state: adk.TaskStateAuthRequired, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			assert.Equal(t, tt.expected, server.IsFinalTaskState(tt.state))
+		})
+	}
+}
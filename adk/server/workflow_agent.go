@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	adk "github.com/inference-gateway/a2a/adk"
+	sdk "github.com/inference-gateway/sdk"
+	zap "go.uber.org/zap"
+)
+
+var (
+	_ OpenAICompatibleAgent = (*SequentialAgent)(nil)
+	_ OpenAICompatibleAgent = (*ParallelAgent)(nil)
+	_ OpenAICompatibleAgent = (*LoopAgent)(nil)
+	_ TaskHandler           = (*SequentialAgent)(nil)
+	_ TaskHandler           = (*ParallelAgent)(nil)
+	_ TaskHandler           = (*LoopAgent)(nil)
+	_ workflowAgent         = (*SequentialAgent)(nil)
+	_ compositeAgent        = (*SequentialAgent)(nil)
+)
+
+// MetadataWorkflowStepKey is the metadata key of the workflow step a status message belongs to
+const MetadataWorkflowStepKey = "workflowStep"
+
+// MetadataWorkflowPausedStepKey is the task metadata key of the parallel workflow step waiting for input,
+// the reply of the user resumes that step only
+const MetadataWorkflowPausedStepKey = "workflowPausedStep"
+
+// DefaultLoopMaxIterations is the iteration cap of loop agents configured without one
+const DefaultLoopMaxIterations = 10
+
+// WorkflowStep is a named unit of work of a workflow agent, processed by an agent or a task handler
+type WorkflowStep struct {
+	Name    string
+	Handler TaskHandler
+	agent   OpenAICompatibleAgent
+}
+
+// handleTask processes the task with the agent or task handler of the step
+func (s WorkflowStep) handleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	if s.agent != nil {
+		return s.agent.ProcessTask(ctx, task, message)
+	}
+	if s.Handler == nil {
+		return failedTask(task, fmt.Sprintf("Workflow step %s has no agent or handler", s.Name)), nil
+	}
+	return s.Handler.HandleTask(ctx, task, message)
+}
+
+// NewAgentStep creates a workflow step processing the task with an agent
+func NewAgentStep(name string, agent OpenAICompatibleAgent) WorkflowStep {
+	return WorkflowStep{Name: name, agent: agent}
+}
+
+// NewHandlerStep creates a workflow step processing the task with a task handler
+func NewHandlerStep(name string, handler TaskHandler) WorkflowStep {
+	return WorkflowStep{Name: name, Handler: handler}
+}
+
+// workflowAgent is an agent composed of workflow steps, it streams the progress of its steps instead of LLM tokens
+type workflowAgent interface {
+	OpenAICompatibleAgent
+	isWorkflow()
+}
+
+// compositeAgent is an agent delegating to other agents, which the server decorates individually
+type compositeAgent interface {
+	agents() []OpenAICompatibleAgent
+}
+
+// workflow holds the steps shared by the workflow agents
+// Workflow agents have no LLM client, tool box or system prompt of their own
+type workflow struct {
+	logger *zap.Logger
+	steps  []WorkflowStep
+}
+
+// agents returns the agents of the workflow steps
+func (w *workflow) agents() []OpenAICompatibleAgent {
+	agents := make([]OpenAICompatibleAgent, 0, len(w.steps))
+	for _, step := range w.steps {
+		if step.agent != nil {
+			agents = append(agents, step.agent)
+		}
+	}
+	return agents
+}
+
+func (w *workflow) isWorkflow() {}
+
+// GetLLMClient returns nil, the steps use their own LLM clients
+func (w *workflow) GetLLMClient() LLMClient {
+	return nil
+}
+
+// GetToolBox returns nil, the steps use their own tool boxes
+func (w *workflow) GetToolBox() ToolBox {
+	return nil
+}
+
+// GetSystemPrompt returns an empty prompt, the steps use their own system prompts
+func (w *workflow) GetSystemPrompt() string {
+	return ""
+}
+
+// runStep processes the task with a step, reporting its start and its output as working statuses
+func (w *workflow) runStep(ctx context.Context, step WorkflowStep, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	w.logger.Debug("running workflow step",
+		zap.String("step", step.Name),
+		zap.String("task_id", task.ID))
+
+	task.Status.State = adk.TaskStateWorking
+	ReportTaskStatus(ctx, task, workingStatus(stepMessage(task, step.Name, fmt.Sprintf("Running step %s", step.Name))))
+
+	result, err := step.handleTask(ctx, task, message)
+	if err != nil {
+		return nil, fmt.Errorf("workflow step %s failed: %w", step.Name, err)
+	}
+	if result == nil {
+		result = task
+	}
+
+	if output := result.Status.Message; output != nil && result.Status.State == adk.TaskStateCompleted {
+		progress := *output
+		progress.Metadata = map[string]interface{}{MetadataWorkflowStepKey: step.Name}
+
+		// Reporting may update the stored task, which can be the task being processed
+		status := result.Status
+		ReportTaskStatus(ctx, result, workingStatus(&progress))
+		result.Status = status
+	}
+	return result, nil
+}
+
+// workingStatus returns a working status with the message
+func workingStatus(message *adk.Message) adk.TaskStatus {
+	return adk.TaskStatus{
+		State:     adk.TaskStateWorking,
+		Message:   message,
+		Timestamp: StringPtr(time.Now().UTC().Format(time.RFC3339Nano)),
+	}
+}
+
+// stepMessage creates an agent message about a workflow step
+func stepMessage(task *adk.Task, stepName string, text string) *adk.Message {
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: uuid.New().String(),
+		Role:      "assistant",
+		Metadata:  map[string]interface{}{MetadataWorkflowStepKey: stepName},
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": text,
+			},
+		},
+		TaskID:    StringPtr(task.ID),
+		ContextID: StringPtr(task.ContextID),
+	}
+}
+
+// pipedMessage returns the input of the next step, the output of the previous step sent as a user message
+// The previous input is kept if the step produced no output
+func pipedMessage(task *adk.Task, previous *adk.Message) *adk.Message {
+	output := task.Status.Message
+	if output == nil || len(output.Parts) == 0 {
+		return previous
+	}
+
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: uuid.New().String(),
+		Role:      "user",
+		Parts:     output.Parts,
+		TaskID:    StringPtr(task.ID),
+		ContextID: StringPtr(task.ContextID),
+	}
+}
+
+// SequentialAgent runs its steps one after another on the same task, the output of each step is the input of the next
+// It stops at the first step that does not complete, leaving the task in the state of that step
+type SequentialAgent struct {
+	workflow
+}
+
+// NewSequentialAgent creates an agent running the steps in order
+func NewSequentialAgent(logger *zap.Logger, steps ...WorkflowStep) *SequentialAgent {
+	return &SequentialAgent{workflow: workflow{logger: logger, steps: steps}}
+}
+
+// HandleTask runs the steps in order
+func (a *SequentialAgent) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	if len(a.steps) == 0 {
+		return failedTask(task, "Workflow has no steps"), nil
+	}
+
+	input := message
+	for _, step := range a.steps {
+		result, err := a.runStep(ctx, step, task, input)
+		if err != nil {
+			return nil, err
+		}
+		task = result
+
+		if task.Status.State != adk.TaskStateCompleted {
+			a.logger.Info("sequential workflow stopped",
+				zap.String("step", step.Name),
+				zap.String("task_id", task.ID),
+				zap.String("state", string(task.Status.State)))
+			return task, nil
+		}
+		input = pipedMessage(task, input)
+	}
+	return task, nil
+}
+
+// ProcessTask runs the steps in order, so the workflow can be used as the server agent
+func (a *SequentialAgent) ProcessTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return a.HandleTask(ctx, task, message)
+}
+
+// ParallelAgent runs its steps concurrently on copies of the task with the same input
+// The history, artifacts and metadata each step adds are merged into the task in step order, the token usage
+// and cost of the steps are added up, and the outputs of the steps are combined into the status message.
+// If a step does not complete, the task takes the state and status message of the first such step.
+// A step waiting for input is resumed on its own by the reply of the user.
+type ParallelAgent struct {
+	workflow
+}
+
+// NewParallelAgent creates an agent running the steps concurrently
+func NewParallelAgent(logger *zap.Logger, steps ...WorkflowStep) *ParallelAgent {
+	return &ParallelAgent{workflow: workflow{logger: logger, steps: steps}}
+}
+
+// HandleTask runs the steps concurrently and merges their results
+func (a *ParallelAgent) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	if len(a.steps) == 0 {
+		return failedTask(task, "Workflow has no steps"), nil
+	}
+
+	steps := a.steps
+	if step, ok := a.pausedStep(task); ok {
+		a.logger.Info("resuming parallel workflow step",
+			zap.String("step", step.Name),
+			zap.String("task_id", task.ID))
+		steps = []WorkflowStep{step}
+	}
+
+	results := make([]*adk.Task, len(steps))
+	errs := make([]error, len(steps))
+
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		branch := cloneTask(task)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = a.runStep(ctx, step, branch, message)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return a.merge(ctx, task, steps, results), nil
+}
+
+// ProcessTask runs the steps concurrently, so the workflow can be used as the server agent
+func (a *ParallelAgent) ProcessTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return a.HandleTask(ctx, task, message)
+}
+
+// pausedStep returns the step of the task waiting for input, if any
+func (a *ParallelAgent) pausedStep(task *adk.Task) (WorkflowStep, bool) {
+	if task.Status.State != adk.TaskStateInputRequired {
+		return WorkflowStep{}, false
+	}
+	name, _ := task.Metadata[MetadataWorkflowPausedStepKey].(string)
+	for _, step := range a.steps {
+		if name != "" && step.Name == name {
+			return step, true
+		}
+	}
+	return WorkflowStep{}, false
+}
+
+// merge adds the history, artifacts and metadata of the step results to the task and sets its status
+func (a *ParallelAgent) merge(ctx context.Context, task *adk.Task, steps []WorkflowStep, results []*adk.Task) *adk.Task {
+	historyLength := len(task.History)
+	artifactsLength := len(task.Artifacts)
+
+	var interrupted *adk.Task
+	var interruptedStep string
+	sections := make([]string, 0, len(results))
+	for i, result := range results {
+		if len(result.History) > historyLength {
+			task.History = append(task.History, result.History[historyLength:]...)
+		}
+		if len(result.Artifacts) > artifactsLength {
+			task.Artifacts = append(task.Artifacts, result.Artifacts[artifactsLength:]...)
+		}
+
+		if result.Status.State != adk.TaskStateCompleted && interrupted == nil {
+			interrupted = result
+			interruptedStep = steps[i].Name
+		}
+		if text := messageText(result.Status.Message); text != "" {
+			sections = append(sections, fmt.Sprintf("%s:\n%s", steps[i].Name, text))
+		}
+	}
+
+	mergeStepMetadata(ctx, task, results, interrupted)
+
+	if interrupted != nil {
+		task.Status.State = interrupted.Status.State
+		task.Status.Message = interrupted.Status.Message
+		if interrupted.Status.State == adk.TaskStateInputRequired {
+			SetTaskMetadata(ctx, task, MetadataWorkflowPausedStepKey, interruptedStep)
+		}
+		return task
+	}
+
+	task.Status.State = adk.TaskStateCompleted
+	task.Status.Message = &adk.Message{
+		Kind:      "message",
+		MessageID: uuid.New().String(),
+		Role:      "assistant",
+		Parts: []adk.Part{
+			map[string]interface{}{
+				"kind": "text",
+				"text": strings.Join(sections, "\n\n"),
+			},
+		},
+		TaskID:    StringPtr(task.ID),
+		ContextID: StringPtr(task.ContextID),
+	}
+	return task
+}
+
+// mergeStepMetadata sets the metadata the step results added or changed on the task, in step order
+// The token usage and cost of the steps are added to those of the task, and the tool approval pending
+// is the one of the interrupted step, if any
+func mergeStepMetadata(ctx context.Context, task *adk.Task, results []*adk.Task, interrupted *adk.Task) {
+	usage := TaskTokenUsage(task)
+	cost := taskTokenCost(task)
+	baseUsage := usage
+	baseCost := cost
+
+	for _, result := range results {
+		for key, value := range result.Metadata {
+			switch key {
+			case MetadataTokenUsageKey, MetadataTokenCostKey, MetadataPendingToolApprovalKey, MetadataWorkflowPausedStepKey:
+				continue
+			}
+			if current, exists := task.Metadata[key]; exists && reflect.DeepEqual(current, value) {
+				continue
+			}
+			SetTaskMetadata(ctx, task, key, value)
+		}
+
+		stepUsage := TaskTokenUsage(result)
+		addTokenUsage(&usage, sdk.CompletionUsage{
+			PromptTokens:     stepUsage.PromptTokens - baseUsage.PromptTokens,
+			CompletionTokens: stepUsage.CompletionTokens - baseUsage.CompletionTokens,
+			TotalTokens:      stepUsage.TotalTokens - baseUsage.TotalTokens,
+		})
+		cost += taskTokenCost(result) - baseCost
+	}
+
+	if usage != baseUsage {
+		SetTaskMetadata(ctx, task, MetadataTokenUsageKey, usage)
+	}
+	if cost != baseCost {
+		SetTaskMetadata(ctx, task, MetadataTokenCostKey, cost)
+	}
+
+	var pending interface{}
+	if interrupted != nil {
+		pending = interrupted.Metadata[MetadataPendingToolApprovalKey]
+	}
+	if _, exists := task.Metadata[MetadataPendingToolApprovalKey]; exists || pending != nil {
+		SetTaskMetadata(ctx, task, MetadataPendingToolApprovalKey, pending)
+	}
+	if _, exists := task.Metadata[MetadataWorkflowPausedStepKey]; exists {
+		SetTaskMetadata(ctx, task, MetadataWorkflowPausedStepKey, nil)
+	}
+}
+
+// cloneTask copies the task so a step can change its history, artifacts and metadata independently
+func cloneTask(task *adk.Task) *adk.Task {
+	clone := *task
+	clone.History = append([]adk.Message(nil), task.History...)
+	clone.Artifacts = append([]adk.Artifact(nil), task.Artifacts...)
+	if task.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(task.Metadata))
+		for key, value := range task.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+	return &clone
+}
+
+// LoopCondition decides after an iteration whether the loop is done
+type LoopCondition func(ctx context.Context, task *adk.Task, iteration int) bool
+
+// LoopAgent repeats its step on the same task until the condition is met or the iteration cap is reached
+// The output of each iteration is the input of the next. The loop stops early when an iteration does not complete.
+type LoopAgent struct {
+	workflow
+	maxIterations int
+	until         LoopCondition
+}
+
+// NewLoopAgent creates an agent repeating the step until the condition returns true
+// A nil condition repeats the step maxIterations times, a cap below one uses DefaultLoopMaxIterations
+func NewLoopAgent(logger *zap.Logger, step WorkflowStep, maxIterations int, until LoopCondition) *LoopAgent {
+	if maxIterations < 1 {
+		maxIterations = DefaultLoopMaxIterations
+	}
+	return &LoopAgent{
+		workflow:      workflow{logger: logger, steps: []WorkflowStep{step}},
+		maxIterations: maxIterations,
+		until:         until,
+	}
+}
+
+// HandleTask repeats the step until the loop is done
+func (a *LoopAgent) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	step := a.steps[0]
+	input := message
+
+	for iteration := 1; iteration <= a.maxIterations; iteration++ {
+		iterationStep := step
+		iterationStep.Name = fmt.Sprintf("%s (iteration %d)", step.Name, iteration)
+
+		result, err := a.runStep(ctx, iterationStep, task, input)
+		if err != nil {
+			return nil, err
+		}
+		task = result
+
+		if task.Status.State != adk.TaskStateCompleted {
+			return task, nil
+		}
+		if a.until != nil && a.until(ctx, task, iteration) {
+			a.logger.Debug("loop condition met",
+				zap.String("step", step.Name),
+				zap.Int("iterations", iteration))
+			return task, nil
+		}
+		input = pipedMessage(task, input)
+	}
+
+	a.logger.Info("loop reached max iterations",
+		zap.String("step", step.Name),
+		zap.Int("max_iterations", a.maxIterations))
+	return task, nil
+}
+
+// ProcessTask repeats the step, so the workflow can be used as the server agent
+func (a *LoopAgent) ProcessTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return a.HandleTask(ctx, task, message)
+}
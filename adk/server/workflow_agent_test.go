@@ -0,0 +1,297 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/inference-gateway/a2a/adk"
+	"github.com/inference-gateway/a2a/adk/server"
+	"github.com/inference-gateway/a2a/adk/server/mocks"
+	sdk "github.com/inference-gateway/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type taskHandlerFunc func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error)
+
+func (f taskHandlerFunc) HandleTask(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+	return f(ctx, task, message)
+}
+
+func textOf(message *adk.Message) string {
+	if message == nil || len(message.Parts) == 0 {
+		return ""
+	}
+	text, _ := message.Parts[0].(map[string]interface{})["text"].(string)
+	return text
+}
+
+func textMessage(role string, text string) *adk.Message {
+	return &adk.Message{
+		Kind:      "message",
+		MessageID: role + "-" + text,
+		Role:      role,
+		Parts:     []adk.Part{map[string]interface{}{"kind": "text", "text": text}},
+	}
+}
+
+// transformStep completes the task with the transformed text of its input
+func transformStep(name string, transform func(string) string) server.WorkflowStep {
+	return server.NewHandlerStep(name, taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		output := textMessage("assistant", transform(textOf(message)))
+		task.History = append(task.History, *output)
+		task.Status.State = adk.TaskStateCompleted
+		task.Status.Message = output
+		return task, nil
+	}))
+}
+
+// statusRecorder collects the reported statuses
+type statusRecorder struct {
+	mu       sync.Mutex
+	statuses []adk.TaskStatus
+}
+
+func (r *statusRecorder) context() context.Context {
+	return server.WithTaskStatusReporter(context.Background(), func(ctx context.Context, task *adk.Task, status adk.TaskStatus) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.statuses = append(r.statuses, status)
+	})
+}
+
+func (r *statusRecorder) texts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	texts := make([]string, 0, len(r.statuses))
+	for _, status := range r.statuses {
+		texts = append(texts, textOf(status.Message))
+	}
+	return texts
+}
+
+func TestSequentialAgent(t *testing.T) {
+	recorder := &statusRecorder{}
+	agent := server.NewSequentialAgent(zap.NewNop(),
+		transformStep("upper", strings.ToUpper),
+		transformStep("exclaim", func(text string) string { return text + "!" }),
+	)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	result, err := agent.ProcessTask(recorder.context(), task, textMessage("user", "hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+	assert.Equal(t, "HELLO!", textOf(result.Status.Message), "the output of each step is the input of the next")
+	require.Len(t, result.History, 2, "the steps share the task history")
+	assert.Equal(t, "HELLO", textOf(&result.History[0]))
+	assert.Equal(t, []string{"Running step upper", "HELLO", "Running step exclaim", "HELLO!"}, recorder.texts())
+	assert.Equal(t, "exclaim", recorder.statuses[3].Message.Metadata[server.MetadataWorkflowStepKey])
+}
+
+func TestSequentialAgent_StopsAtInterruptedStep(t *testing.T) {
+	var finalCalled bool
+	agent := server.NewSequentialAgent(zap.NewNop(),
+		server.NewHandlerStep("ask", taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			task.Status.State = adk.TaskStateInputRequired
+			task.Status.Message = textMessage("assistant", "Which city?")
+			return task, nil
+		})),
+		server.NewHandlerStep("final", taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			finalCalled = true
+			return task, nil
+		})),
+	)
+
+	result, err := agent.HandleTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "weather"))
+	require.NoError(t, err)
+	assert.Equal(t, adk.TaskStateInputRequired, result.Status.State)
+	assert.Equal(t, "Which city?", textOf(result.Status.Message))
+	assert.False(t, finalCalled)
+}
+
+func TestSequentialAgent_StepError(t *testing.T) {
+	agent := server.NewSequentialAgent(zap.NewNop(),
+		server.NewHandlerStep("broken", taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			return nil, errors.New("boom")
+		})),
+	)
+
+	_, err := agent.HandleTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "hi"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow step broken failed: boom")
+}
+
+func TestParallelAgent(t *testing.T) {
+	artifactStep := func(name string) server.WorkflowStep {
+		return server.NewHandlerStep(name, taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			output := textMessage("assistant", name+" result for "+textOf(message))
+			task.History = append(task.History, *output)
+			task.Artifacts = append(task.Artifacts, adk.Artifact{ArtifactID: name})
+			task.Status.State = adk.TaskStateCompleted
+			task.Status.Message = output
+			return task, nil
+		}))
+	}
+
+	t.Run("merges the results in step order", func(t *testing.T) {
+		agent := server.NewParallelAgent(zap.NewNop(), artifactStep("flights"), artifactStep("hotels"))
+
+		task := &adk.Task{
+			ID:        "task-1",
+			History:   []adk.Message{*textMessage("user", "earlier")},
+			Artifacts: []adk.Artifact{{ArtifactID: "existing"}},
+		}
+		result, err := agent.HandleTask(context.Background(), task, textMessage("user", "Paris"))
+		require.NoError(t, err)
+
+		assert.Equal(t, adk.TaskStateCompleted, result.Status.State)
+		assert.Equal(t, "flights:\nflights result for Paris\n\nhotels:\nhotels result for Paris", textOf(result.Status.Message))
+		require.Len(t, result.History, 3)
+		assert.Equal(t, "flights result for Paris", textOf(&result.History[1]))
+		assert.Equal(t, "hotels result for Paris", textOf(&result.History[2]))
+
+		artifactIDs := make([]string, 0, len(result.Artifacts))
+		for _, artifact := range result.Artifacts {
+			artifactIDs = append(artifactIDs, artifact.ArtifactID)
+		}
+		assert.Equal(t, []string{"existing", "flights", "hotels"}, artifactIDs)
+	})
+
+	t.Run("takes the state of the first failed step", func(t *testing.T) {
+		failing := server.NewHandlerStep("cars", taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+			task.Status.State = adk.TaskStateFailed
+			task.Status.Message = textMessage("assistant", "no cars available")
+			return task, nil
+		}))
+		agent := server.NewParallelAgent(zap.NewNop(), artifactStep("flights"), failing)
+
+		result, err := agent.HandleTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Paris"))
+		require.NoError(t, err)
+		assert.Equal(t, adk.TaskStateFailed, result.Status.State)
+		assert.Equal(t, "no cars available", textOf(result.Status.Message))
+		assert.Len(t, result.Artifacts, 1, "completed steps still contribute their artifacts")
+	})
+}
+
+func TestParallelAgent_ResumesToolApproval(t *testing.T) {
+	var recipients []string
+	toolBox := server.NewDefaultToolBox()
+	toolBox.AddTool(server.NewBasicTool("send_email", "Send an email", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			recipient, _ := args["to"].(string)
+			recipients = append(recipients, recipient)
+			return "sent to " + recipient, nil
+		},
+		server.WithApprovalRequired()))
+	toolBox.AddTool(server.NewBasicTool("lookup_contact", "Look up a contact", map[string]interface{}{"type": "object"},
+		func(ctx context.Context, args map[string]interface{}) (string, error) {
+			return "alice@example.com", nil
+		}))
+
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, withUsage(sendEmailResponse(), 100, 20), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, withUsage(completionResponse("Email sent."), 50, 5), nil)
+
+	emailAgent, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithToolBox(toolBox).Build()
+	require.NoError(t, err)
+
+	notesCalls := 0
+	notes := server.NewHandlerStep("notes", taskHandlerFunc(func(ctx context.Context, task *adk.Task, message *adk.Message) (*adk.Task, error) {
+		notesCalls++
+		server.RecordTokenUsage(ctx, task, &sdk.CompletionUsage{PromptTokens: 30, CompletionTokens: 10, TotalTokens: 40})
+		task.Status.State = adk.TaskStateCompleted
+		task.Status.Message = textMessage("assistant", "Notes taken")
+		return task, nil
+	}))
+
+	agent := server.NewParallelAgent(zap.NewNop(), server.NewAgentStep("email", emailAgent), notes)
+
+	task := &adk.Task{ID: "task-1", ContextID: "context-1"}
+	task, err = agent.HandleTask(context.Background(), task, textMessage("user", "Email Alice"))
+	require.NoError(t, err)
+
+	require.Equal(t, adk.TaskStateInputRequired, task.Status.State)
+	assert.Contains(t, task.Metadata, server.MetadataPendingToolApprovalKey, "the approval of the paused step is kept")
+	assert.Equal(t, "email", task.Metadata[server.MetadataWorkflowPausedStepKey])
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 130, CompletionTokens: 30, TotalTokens: 160}, server.TaskTokenUsage(task), "the usage of all steps is added up")
+	assert.Empty(t, recipients)
+
+	task, err = agent.HandleTask(context.Background(), task, textMessage("user", "Approve"))
+	require.NoError(t, err)
+
+	assert.Equal(t, adk.TaskStateCompleted, task.Status.State)
+	assert.Equal(t, "email:\nEmail sent.", textOf(task.Status.Message))
+	assert.Equal(t, []string{"alice@example.com"}, recipients)
+	assert.Equal(t, 1, notesCalls, "only the paused step is resumed")
+	assert.NotContains(t, task.Metadata, server.MetadataPendingToolApprovalKey)
+	assert.NotContains(t, task.Metadata, server.MetadataWorkflowPausedStepKey)
+	assert.Equal(t, sdk.CompletionUsage{PromptTokens: 180, CompletionTokens: 35, TotalTokens: 215}, server.TaskTokenUsage(task))
+}
+
+func TestLoopAgent(t *testing.T) {
+	double := transformStep("double", func(text string) string { return text + text })
+
+	tests := []struct {
+		name          string
+		maxIterations int
+		until         server.LoopCondition
+		expectedText  string
+		expectedRuns  int
+	}{
+		{
+			name:          "stops when the condition is met",
+			maxIterations: 10,
+			until: func(ctx context.Context, task *adk.Task, iteration int) bool {
+				return len(textOf(task.Status.Message)) >= 8
+			},
+			expectedText: "abababab",
+			expectedRuns: 2,
+		},
+		{
+			name:          "stops at the iteration cap",
+			maxIterations: 3,
+			expectedText:  strings.Repeat("ab", 8),
+			expectedRuns:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := server.NewLoopAgent(zap.NewNop(), double, tt.maxIterations, tt.until)
+
+			result, err := agent.HandleTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "ab"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedText, textOf(result.Status.Message))
+			assert.Len(t, result.History, tt.expectedRuns)
+		})
+	}
+}
+
+func TestWorkflowAgent_AgentSteps(t *testing.T) {
+	llmClient := &mocks.FakeLLMClient{}
+	llmClient.CreateChatCompletionReturnsOnCall(0, completionResponse("draft"), nil)
+	llmClient.CreateChatCompletionReturnsOnCall(1, completionResponse("reviewed draft"), nil)
+
+	writer, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithSystemPrompt("Write.").Build()
+	require.NoError(t, err)
+	reviewer, err := server.NewAgentBuilder(zap.NewNop()).WithLLMClient(llmClient).WithSystemPrompt("Review.").Build()
+	require.NoError(t, err)
+
+	agent := server.NewSequentialAgent(zap.NewNop(),
+		server.NewAgentStep("write", writer),
+		server.NewAgentStep("review", reviewer),
+	)
+
+	result, err := agent.ProcessTask(context.Background(), &adk.Task{ID: "task-1"}, textMessage("user", "Write a poem"))
+	require.NoError(t, err)
+	assert.Equal(t, "reviewed draft", textOf(result.Status.Message))
+
+	_, messages, _ := llmClient.CreateChatCompletionArgsForCall(1)
+	assert.Equal(t, "Review.", messages[0].Content)
+	assert.Equal(t, "draft", messages[len(messages)-1].Content, "the reviewer receives the draft as its input")
+}